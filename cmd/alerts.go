@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/alerts"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var cmdAlerts = &cobra.Command{
+	Use:   "alerts",
+	Short: "Deal with price and portfolio alerts",
+}
+
+var cmdAlertsCheck = &cobra.Command{
+	Use:   "check",
+	Short: "Evaluate alert rules against the latest wallets and notify those met",
+	Run: func(cmd *cobra.Command, args []string) {
+		results, err := alerts.Evaluate()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := utils.OutputResult(results); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdAlertsTest = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test alert to every enabled sink",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := alerts.Test(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func alertsCmdInit() {
+	rootCmd.AddCommand(cmdAlerts)
+
+	cmdAlerts.AddCommand(cmdAlertsCheck)
+
+	cmdAlerts.AddCommand(cmdAlertsTest)
+}
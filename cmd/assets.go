@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var cmdAssets = &cobra.Command{
+	Use:   "assets",
+	Short: "List assets of all sources with their class",
+	Run: func(cmd *cobra.Command, args []string) {
+		transactions, err := ledger.LoadAll()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		coinList, err := coingecko.GetCoinList()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		coins := ledger.NewCoins(coinList)
+
+		classes := make(map[string]ledger.AssetClass)
+		for _, tx := range transactions {
+			for _, asset := range []string{tx.Asset, tx.QuoteAsset, tx.FeeAsset} {
+				if asset != "" {
+					classes[asset] = coins.Classify(asset)
+				}
+			}
+		}
+
+		if err := utils.OutputResult(classes); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func assetsCmdInit() {
+	rootCmd.AddCommand(cmdAssets)
+}
@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/eliasbokreta/tracklet/pkg/binance"
+	"github.com/eliasbokreta/tracklet/pkg/importer"
 	"github.com/spf13/cobra"
 )
 
@@ -15,7 +19,10 @@ var cmdBinanceProcess = &cobra.Command{
 	Short: "Process Binance data",
 	Run: func(cmd *cobra.Command, args []string) {
 		binance := binance.New()
-		binance.ProcessBinanceData(verbose)
+		if err := binance.ProcessBinanceData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	},
 }
 
@@ -23,8 +30,19 @@ var cmdBinanceWallet = &cobra.Command{
 	Use:   "wallet",
 	Short: "Get binance wallet",
 	Run: func(cmd *cobra.Command, args []string) {
-		wallet := binance.NewWallet()
-		wallet.ProcessWallet()
+		processWallet(binance.NewWallet())
+	},
+}
+
+var cmdBinanceImport = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import a Binance statement to recover data older than the API history",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := importer.ImportCSV("binance", args[0], "binance"); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	},
 }
 
@@ -35,4 +53,6 @@ func binanceCmdInit() {
 	cmdBinanceProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
 
 	cmdBinance.AddCommand(cmdBinanceWallet)
+
+	cmdBinance.AddCommand(cmdBinanceImport)
 }
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/bitcoin"
+	"github.com/spf13/cobra"
+)
+
+var cmdBitcoin = &cobra.Command{
+	Use:   "bitcoin",
+	Short: "Deal with Bitcoin on-chain wallets",
+}
+
+var cmdBitcoinProcess = &cobra.Command{
+	Use:   "process",
+	Short: "Process Bitcoin data",
+	Run: func(cmd *cobra.Command, args []string) {
+		bitcoin := bitcoin.New()
+		if err := bitcoin.ProcessBitcoinData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdBitcoinWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get bitcoin wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		processWallet(bitcoin.NewWallet())
+	},
+}
+
+func bitcoinCmdInit() {
+	rootCmd.AddCommand(cmdBitcoin)
+
+	cmdBitcoin.AddCommand(cmdBitcoinProcess)
+	cmdBitcoinProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
+
+	cmdBitcoin.AddCommand(cmdBitcoinWallet)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/bitpanda"
+	"github.com/spf13/cobra"
+)
+
+var cmdBitpanda = &cobra.Command{
+	Use:   "bitpanda",
+	Short: "Deal with Bitpanda",
+}
+
+var cmdBitpandaProcess = &cobra.Command{
+	Use:   "process",
+	Short: "Process Bitpanda data",
+	Run: func(cmd *cobra.Command, args []string) {
+		bitpanda := bitpanda.New()
+		if err := bitpanda.ProcessBitpandaData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdBitpandaWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get bitpanda wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		processWallet(bitpanda.NewWallet())
+	},
+}
+
+func bitpandaCmdInit() {
+	rootCmd.AddCommand(cmdBitpanda)
+
+	cmdBitpanda.AddCommand(cmdBitpandaProcess)
+	cmdBitpandaProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
+
+	cmdBitpanda.AddCommand(cmdBitpandaWallet)
+}
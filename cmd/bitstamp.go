@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/bitstamp"
+	"github.com/spf13/cobra"
+)
+
+var cmdBitstamp = &cobra.Command{
+	Use:   "bitstamp",
+	Short: "Deal with Bitstamp",
+}
+
+var cmdBitstampProcess = &cobra.Command{
+	Use:   "process",
+	Short: "Process Bitstamp data",
+	Run: func(cmd *cobra.Command, args []string) {
+		bitstamp := bitstamp.New()
+		if err := bitstamp.ProcessBitstampData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdBitstampWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get bitstamp wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		processWallet(bitstamp.NewWallet())
+	},
+}
+
+func bitstampCmdInit() {
+	rootCmd.AddCommand(cmdBitstamp)
+
+	cmdBitstamp.AddCommand(cmdBitstampProcess)
+	cmdBitstampProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
+
+	cmdBitstamp.AddCommand(cmdBitstampWallet)
+}
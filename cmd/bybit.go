@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/bybit"
+	"github.com/spf13/cobra"
+)
+
+var cmdBybit = &cobra.Command{
+	Use:   "bybit",
+	Short: "Deal with Bybit",
+}
+
+var cmdBybitProcess = &cobra.Command{
+	Use:   "process",
+	Short: "Process Bybit data",
+	Run: func(cmd *cobra.Command, args []string) {
+		bybit := bybit.New()
+		if err := bybit.ProcessBybitData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdBybitWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get bybit wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		processWallet(bybit.NewWallet())
+	},
+}
+
+func bybitCmdInit() {
+	rootCmd.AddCommand(cmdBybit)
+
+	cmdBybit.AddCommand(cmdBybitProcess)
+	cmdBybitProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
+
+	cmdBybit.AddCommand(cmdBybitWallet)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/coinbase"
+	"github.com/spf13/cobra"
+)
+
+var cmdCoinbase = &cobra.Command{
+	Use:   "coinbase",
+	Short: "Deal with Coinbase",
+}
+
+var cmdCoinbaseProcess = &cobra.Command{
+	Use:   "process",
+	Short: "Process Coinbase data",
+	Run: func(cmd *cobra.Command, args []string) {
+		coinbase := coinbase.New()
+		if err := coinbase.ProcessCoinbaseData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdCoinbaseWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get coinbase wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		processWallet(coinbase.NewWallet())
+	},
+}
+
+func coinbaseCmdInit() {
+	rootCmd.AddCommand(cmdCoinbase)
+
+	cmdCoinbase.AddCommand(cmdCoinbaseProcess)
+	cmdCoinbaseProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
+
+	cmdCoinbase.AddCommand(cmdCoinbaseWallet)
+}
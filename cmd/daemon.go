@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/daemon"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var cmdDaemon = &cobra.Command{
+	Use:   "daemon",
+	Short: "Sync every configured source and refresh wallets on schedules",
+	Run: func(cmd *cobra.Command, args []string) {
+		d, err := daemon.New()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := d.Run(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdDaemonStatus = &cobra.Command{
+	Use:   "status",
+	Short: "Show the daemon jobs and the sync state of every source",
+	Run: func(cmd *cobra.Command, args []string) {
+		status, err := daemon.LoadStatus()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := utils.OutputResult(status); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func daemonCmdInit() {
+	rootCmd.AddCommand(cmdDaemon)
+
+	cmdDaemon.AddCommand(cmdDaemonStatus)
+}
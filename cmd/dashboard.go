@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/dashboard"
+	"github.com/spf13/cobra"
+)
+
+var cmdDashboard = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Browse holdings, allocation, exchanges and transactions in a full screen terminal UI",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := dashboard.New().Run(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func dashboardCmdInit() {
+	rootCmd.AddCommand(cmdDashboard)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	depositsSource     string
+	depositsUnassigned bool
+	depositsCurrency   string
+)
+
+// Load crypto deposits coming from outside tracked sources, with assigned costs
+func loadExternalDeposits() []ledger.Transaction {
+	transactions, err := ledger.LoadAllWithCosts()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return ledger.ExternalDeposits(transactions, ledger.MatchTransfers(transactions))
+}
+
+var cmdDeposits = &cobra.Command{
+	Use:   "deposits",
+	Short: "List crypto deposits coming from outside tracked sources",
+	Run: func(cmd *cobra.Command, args []string) {
+		deposits := []ledger.Transaction{}
+		for _, tx := range loadExternalDeposits() {
+			if depositsSource != "" && tx.Source != depositsSource {
+				continue
+			}
+			if depositsUnassigned && ledger.IsCash(tx.QuoteAsset) {
+				continue
+			}
+			deposits = append(deposits, tx)
+		}
+
+		if err := utils.OutputResult(deposits); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdDepositsAssign = &cobra.Command{
+	Use:   "assign [source] [id] [cost]",
+	Short: "Assign a cost to a deposit of unknown origin",
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		cost, err := strconv.ParseFloat(args[2], 64)
+		if err != nil || cost < 0 {
+			fmt.Printf("Invalid cost '%s'\n", args[2])
+			os.Exit(1)
+		}
+
+		currency := strings.ToUpper(depositsCurrency)
+		if currency == "" {
+			currency = ledger.ReportingCurrency()
+		}
+		if !ledger.IsFiat(currency) {
+			fmt.Printf("Invalid currency '%s'\n", depositsCurrency)
+			os.Exit(1)
+		}
+
+		var deposit *ledger.Transaction
+		for _, tx := range loadExternalDeposits() {
+			if tx.Source == args[0] && tx.ID == args[1] {
+				deposit = &tx
+				break
+			}
+		}
+		if deposit == nil {
+			fmt.Printf("No external deposit '%s' found in '%s'\n", args[1], args[0])
+			os.Exit(1)
+		}
+
+		costs, err := ledger.LoadDepositCosts()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		costs.Assign(*deposit, cost, currency)
+		if err := costs.Save(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		log.Infof("Assigned a cost of %.2f %s to %g %s deposit '%s'", cost, currency, deposit.Amount, deposit.Asset, deposit.ID)
+	},
+}
+
+func depositsCmdInit() {
+	rootCmd.AddCommand(cmdDeposits)
+	cmdDeposits.AddCommand(cmdDepositsAssign)
+	cmdDepositsAssign.Flags().StringVarP(&depositsCurrency, "currency", "c", "", "Fiat currency of the cost (default: the reporting currency)")
+	cmdDeposits.Flags().StringVarP(&depositsSource, "source", "s", "", "Only list deposits of a source")
+	cmdDeposits.Flags().BoolVarP(&depositsUnassigned, "unassigned", "u", false, "Only list deposits of unknown cost")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/evm"
+	"github.com/spf13/cobra"
+)
+
+var cmdEVM = &cobra.Command{
+	Use:   "evm",
+	Short: "Deal with EVM wallets (Ethereum, BSC, Polygon, Arbitrum...)",
+}
+
+var cmdEVMProcess = &cobra.Command{
+	Use:   "process",
+	Short: "Process EVM data",
+	Run: func(cmd *cobra.Command, args []string) {
+		evm := evm.New()
+		if err := evm.ProcessEVMData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdEVMWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get evm wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		processWallet(evm.NewWallet())
+	},
+}
+
+func evmCmdInit() {
+	rootCmd.AddCommand(cmdEVM)
+
+	cmdEVM.AddCommand(cmdEVMProcess)
+	cmdEVMProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
+
+	cmdEVM.AddCommand(cmdEVMWallet)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	feesSource string
+	feesYear   int
+)
+
+var cmdFees = &cobra.Command{
+	Use:   "fees",
+	Short: "Report fees paid per asset, source and year",
+	Run: func(cmd *cobra.Command, args []string) {
+		transactions, err := ledger.LoadAll()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		filtered := []ledger.Transaction{}
+		for _, tx := range transactions {
+			if feesSource != "" && tx.Source != feesSource {
+				continue
+			}
+			if feesYear != 0 && time.UnixMilli(tx.Time).UTC().Year() != feesYear {
+				continue
+			}
+			filtered = append(filtered, tx)
+		}
+
+		report := ledger.Fees(filtered)
+
+		if err := utils.WriteToFile("fees", report); err != nil {
+			log.Errorf("Could not save fees to file: %v", err)
+		}
+
+		if err := utils.OutputResult(report); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func feesCmdInit() {
+	rootCmd.AddCommand(cmdFees)
+	cmdFees.Flags().StringVarP(&feesSource, "source", "s", "", "Only report fees of a source")
+	cmdFees.Flags().IntVarP(&feesYear, "year", "y", 0, "Only report fees of a year")
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySource   string
+	historyPeriod   string
+	historyBackfill bool
+)
+
+// Build the history of every day since the first fiat payment from ledgers
+func backfillHistory() (*ledger.History, error) {
+	transactions, err := ledger.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not load ledgers: %w", err)
+	}
+
+	if historySource != "" {
+		filtered := []ledger.Transaction{}
+		for _, tx := range transactions {
+			if tx.Source == historySource {
+				filtered = append(filtered, tx)
+			}
+		}
+		transactions = filtered
+	}
+
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no transaction found, ledgers are filled on every source run")
+	}
+
+	log.Info("Getting Coingecko coin list")
+	coinList, err := coingecko.GetCoinList()
+	if err != nil {
+		return nil, fmt.Errorf("could not get coin list: %w", err)
+	}
+
+	prices := ledger.NewPriceHistory(coinList)
+	history, err := ledger.BackfillHistory(transactions, prices, historyPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := prices.Save(); err != nil {
+		log.Warnf("Could not cache prices: %v", err)
+	}
+
+	return history, nil
+}
+
+// Build the history from wallet snapshots
+func snapshotHistory() (*ledger.History, error) {
+	var snapshots []ledger.Snapshot
+	var err error
+	if historySource != "" {
+		snapshots, err = ledger.LoadSnapshots(historySource)
+	} else {
+		snapshots, err = ledger.LoadAllSnapshots()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshot found, snapshots are taken on every wallet run")
+	}
+
+	return ledger.BuildHistory(snapshots, historyPeriod)
+}
+
+var cmdHistory = &cobra.Command{
+	Use:   "history",
+	Short: "Show portfolio value over time from wallet snapshots or backfilled from ledgers",
+	Run: func(cmd *cobra.Command, args []string) {
+		var history *ledger.History
+		var err error
+		if historyBackfill {
+			history, err = backfillHistory()
+		} else {
+			history, err = snapshotHistory()
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := utils.OutputResult(history); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func historyCmdInit() {
+	rootCmd.AddCommand(cmdHistory)
+	cmdHistory.Flags().StringVarP(&historySource, "source", "s", "", "Only show the history of a source")
+	cmdHistory.Flags().StringVarP(&historyPeriod, "period", "p", ledger.PeriodDaily, "Period between points (daily, weekly or monthly)")
+	cmdHistory.Flags().BoolVarP(&historyBackfill, "backfill", "b", false, "Value every day since the first fiat payment from ledgers instead of snapshots")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/importer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat string
+	importSource string
+)
+
+var cmdImport = &cobra.Command{
+	Use:   "import",
+	Short: "Import transactions from exported files",
+}
+
+var cmdImportCSV = &cobra.Command{
+	Use:   "csv [file]",
+	Short: "Import transactions from a CSV file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := importer.ImportCSV(importFormat, args[0], importSource); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func importCmdInit() {
+	rootCmd.AddCommand(cmdImport)
+
+	cmdImport.AddCommand(cmdImportCSV)
+	cmdImportCSV.Flags().StringVarP(&importFormat, "format", "f", "", fmt.Sprintf("File format (%s)", strings.Join(importer.FormatNames(), ", ")))
+	cmdImportCSV.Flags().StringVarP(&importSource, "source", "s", "", "Source the transactions belong to (default: the format source)")
+	if err := cmdImportCSV.MarkFlagRequired("format"); err != nil {
+		fmt.Println(err)
+	}
+}
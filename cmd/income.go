@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	incomeSource string
+	incomeYear   int
+)
+
+var cmdIncome = &cobra.Command{
+	Use:   "income",
+	Short: "Report staking rewards, interest and other income per asset, source and month",
+	Run: func(cmd *cobra.Command, args []string) {
+		transactions, err := ledger.LoadAll()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if incomeSource != "" {
+			filtered := []ledger.Transaction{}
+			for _, tx := range transactions {
+				if tx.Source == incomeSource {
+					filtered = append(filtered, tx)
+				}
+			}
+			transactions = filtered
+		}
+
+		from, to := int64(0), int64(math.MaxInt64)
+		if incomeYear != 0 {
+			from = time.Date(incomeYear, time.January, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+			to = time.Date(incomeYear+1, time.January, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+		}
+
+		log.Info("Getting Coingecko coin list")
+		coinList, err := coingecko.GetCoinList()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		prices := ledger.NewPriceHistory(coinList)
+		report := ledger.CalculateIncome(transactions, prices, from, to)
+
+		if err := prices.Save(); err != nil {
+			log.Warnf("Could not cache prices: %v", err)
+		}
+
+		if err := utils.WriteToFile("income", report); err != nil {
+			log.Errorf("Could not save income to file: %v", err)
+		}
+
+		if err := utils.OutputResult(report); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func incomeCmdInit() {
+	rootCmd.AddCommand(cmdIncome)
+	cmdIncome.Flags().StringVarP(&incomeSource, "source", "s", "", "Only report income of a source")
+	cmdIncome.Flags().IntVarP(&incomeYear, "year", "y", 0, "Only report income of a year")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/kraken"
+	"github.com/spf13/cobra"
+)
+
+var cmdKraken = &cobra.Command{
+	Use:   "kraken",
+	Short: "Deal with Kraken",
+}
+
+var cmdKrakenProcess = &cobra.Command{
+	Use:   "process",
+	Short: "Process Kraken data",
+	Run: func(cmd *cobra.Command, args []string) {
+		kraken := kraken.New()
+		if err := kraken.ProcessKrakenData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdKrakenWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get kraken wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		processWallet(kraken.NewWallet())
+	},
+}
+
+func krakenCmdInit() {
+	rootCmd.AddCommand(cmdKraken)
+
+	cmdKraken.AddCommand(cmdKrakenProcess)
+	cmdKrakenProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
+
+	cmdKraken.AddCommand(cmdKrakenWallet)
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/eliasbokreta/tracklet/pkg/kucoin"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +18,10 @@ var cmdKucoinProcess = &cobra.Command{
 	Short: "Process Kucoin data",
 	Run: func(cmd *cobra.Command, args []string) {
 		kucoin := kucoin.New()
-		kucoin.ProcessKucoinData(verbose)
+		if err := kucoin.ProcessKucoinData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	},
 }
 
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/manual"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manualEntry manual.Entry
+	manualType  string
+)
+
+var cmdManual = &cobra.Command{
+	Use:   "manual",
+	Short: "Deal with manually recorded transactions",
+}
+
+var cmdManualAdd = &cobra.Command{
+	Use:   "add",
+	Short: "Record a manual transaction",
+	Run: func(cmd *cobra.Command, args []string) {
+		manualEntry.Type = ledger.Type(manualType)
+
+		entry, err := manual.Add(manualEntry)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := utils.OutputResult(entry); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdManualList = &cobra.Command{
+	Use:   "list",
+	Short: "List manual transactions",
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := manual.Load()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := utils.OutputResult(entries); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdManualRemove = &cobra.Command{
+	Use:   "remove [id]",
+	Short: "Remove a manual transaction",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := manual.Remove(args[0]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdManualWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get manual wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := manual.Sync(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		processWallet(manual.NewWallet())
+	},
+}
+
+func manualCmdInit() {
+	rootCmd.AddCommand(cmdManual)
+
+	cmdManual.AddCommand(cmdManualAdd)
+	cmdManualAdd.Flags().StringVarP(&manualType, "type", "t", "", "Transaction type (buy, sell, deposit, withdraw, income, fee)")
+	cmdManualAdd.Flags().StringVarP(&manualEntry.Asset, "asset", "a", "", "Asset bought, sold or moved")
+	cmdManualAdd.Flags().Float64VarP(&manualEntry.Amount, "amount", "n", 0, "Quantity of asset")
+	cmdManualAdd.Flags().StringVar(&manualEntry.QuoteAsset, "quote-asset", "", "Asset paid or received, fiat for a value of other types")
+	cmdManualAdd.Flags().Float64Var(&manualEntry.QuoteAmount, "quote-amount", 0, "Quantity of quote asset")
+	cmdManualAdd.Flags().StringVar(&manualEntry.FeeAsset, "fee-asset", "", "Asset the fee was paid in")
+	cmdManualAdd.Flags().Float64Var(&manualEntry.FeeAmount, "fee-amount", 0, "Fee quantity")
+	cmdManualAdd.Flags().StringVar(&manualEntry.Time, "time", "", "UTC time as '2006-01-02 15:04:05' (default: now)")
+	cmdManualAdd.Flags().StringVar(&manualEntry.TxHash, "tx-hash", "", "On-chain transaction hash")
+	cmdManualAdd.Flags().StringVar(&manualEntry.Note, "note", "", "Free text note")
+	for _, flag := range []string{"type", "asset", "amount"} {
+		if err := cmdManualAdd.MarkFlagRequired(flag); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	cmdManual.AddCommand(cmdManualList)
+
+	cmdManual.AddCommand(cmdManualRemove)
+
+	cmdManual.AddCommand(cmdManualWallet)
+}
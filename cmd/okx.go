@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/okx"
+	"github.com/spf13/cobra"
+)
+
+var cmdOKX = &cobra.Command{
+	Use:   "okx",
+	Short: "Deal with OKX",
+}
+
+var cmdOKXProcess = &cobra.Command{
+	Use:   "process",
+	Short: "Process OKX data",
+	Run: func(cmd *cobra.Command, args []string) {
+		okx := okx.New()
+		if err := okx.ProcessOKXData(verbose); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var cmdOKXWallet = &cobra.Command{
+	Use:   "wallet",
+	Short: "Get okx wallet",
+	Run: func(cmd *cobra.Command, args []string) {
+		processWallet(okx.NewWallet())
+	},
+}
+
+func okxCmdInit() {
+	rootCmd.AddCommand(cmdOKX)
+
+	cmdOKX.AddCommand(cmdOKXProcess)
+	cmdOKXProcess.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print json data while processing")
+
+	cmdOKX.AddCommand(cmdOKXWallet)
+}
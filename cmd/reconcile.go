@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/reconcile"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var reconcileAll bool
+
+var cmdReconcile = &cobra.Command{
+	Use:   "reconcile [source...]",
+	Short: fmt.Sprintf("Compare computed holdings to live balances (%s)", strings.Join(reconcile.Sources(), ", ")),
+	Run: func(cmd *cobra.Command, args []string) {
+		sources := args
+		if len(sources) == 0 {
+			sources = reconcile.Sources()
+		}
+
+		reports := []*reconcile.Report{}
+		for _, source := range sources {
+			report, err := reconcile.Reconcile(source, viper.GetFloat64("reconcile.tolerance"))
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			for _, asset := range report.Assets {
+				if !asset.Matching {
+					log.Warnf("%s %s: computed %f, actual %f", source, asset.Asset, asset.Computed, asset.Actual)
+				}
+			}
+
+			if err := utils.WriteToFile(fmt.Sprintf("%s_reconcile", source), report); err != nil {
+				log.Errorf("Could not save reconciliation to file: %v", err)
+			}
+
+			if !reconcileAll {
+				discrepancies := []reconcile.Asset{}
+				for _, asset := range report.Assets {
+					if !asset.Matching {
+						discrepancies = append(discrepancies, asset)
+					}
+				}
+				report.Assets = discrepancies
+			}
+			reports = append(reports, report)
+		}
+
+		if err := utils.OutputResult(reports); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func reconcileCmdInit() {
+	rootCmd.AddCommand(cmdReconcile)
+	cmdReconcile.Flags().BoolVarP(&reconcileAll, "all", "a", false, "List matching assets along with discrepancies")
+	cmdReconcile.Flags().Float64P("tolerance", "t", 1, "Allowed difference, in percent")
+	if err := viper.BindPFlag("reconcile.tolerance", cmdReconcile.Flags().Lookup("tolerance")); err != nil {
+		fmt.Println(err)
+	}
+}
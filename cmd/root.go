@@ -2,20 +2,77 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/eliasbokreta/tracklet/pkg/alerts"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var (
 	verbose bool
 )
 
-var rootCmd = &cobra.Command{Use: "tracklet"}
+var rootCmd = &cobra.Command{
+	Use: "tracklet",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return utils.ValidateOutput(viper.GetString("tracklet.output"))
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if err := metrics.Flush(); err != nil {
+			log.Warnf("Could not save fetcher metrics: %v", err)
+		}
+	},
+}
+
+// Process a source ledger into a wallet, then evaluate alerts against its new snapshot
+func processWallet(wallet *ledger.Wallet) {
+	if err := wallet.ProcessWallet(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if err := alerts.AfterRefresh(); err != nil {
+		log.Errorf("Could not evaluate alerts: %v", err)
+	}
+}
 
 func initCmd() {
 	cobra.OnInitialize()
+
+	rootCmd.PersistentFlags().StringP("output", "o", utils.OutputJSON, "Output format (table, json, yaml or csv)")
+	rootCmd.PersistentFlags().String("sort", "", "Column to sort table and csv outputs by")
+	_ = viper.BindPFlag("tracklet.output", rootCmd.PersistentFlags().Lookup("output"))
+	_ = viper.BindPFlag("tracklet.sort", rootCmd.PersistentFlags().Lookup("sort"))
+
 	binanceCmdInit()
 	kucoinCmdInit()
+	coinbaseCmdInit()
+	krakenCmdInit()
+	bitstampCmdInit()
+	bitpandaCmdInit()
+	okxCmdInit()
+	bybitCmdInit()
+	bitcoinCmdInit()
+	evmCmdInit()
+	manualCmdInit()
+	transfersCmdInit()
+	reconcileCmdInit()
+	feesCmdInit()
+	depositsCmdInit()
+	incomeCmdInit()
+	taxCmdInit()
+	assetsCmdInit()
+	historyCmdInit()
+	dashboardCmdInit()
+	serveCmdInit()
+	daemonCmdInit()
+	alertsCmdInit()
+	importCmdInit()
 }
 
 func Execute() error {
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddress string
+	serveToken   string
+)
+
+var cmdServe = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve holdings, stats, transactions, snapshots and tax reports over a local HTTP API",
+	Run: func(cmd *cobra.Command, args []string) {
+		s := server.New()
+		if serveAddress != "" {
+			s.SetAddress(serveAddress)
+		}
+		if serveToken != "" {
+			s.SetToken(serveToken)
+		}
+
+		if err := s.Run(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func serveCmdInit() {
+	rootCmd.AddCommand(cmdServe)
+	cmdServe.Flags().StringVarP(&serveAddress, "address", "a", "", "Address to listen on (default: server.address)")
+	cmdServe.Flags().StringVarP(&serveToken, "token", "t", "", "Bearer token required by requests (default: server.token)")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var taxSource string
+
+var cmdTax = &cobra.Command{
+	Use:   "tax [year]",
+	Short: "Report gains realized by sales for cash and income received during a year",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		year, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Printf("Invalid year '%s'\n", args[0])
+			os.Exit(1)
+		}
+
+		transactions, err := ledger.LoadAllWithCosts()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		log.Info("Getting Coingecko coin list")
+		coinList, err := coingecko.GetCoinList()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		prices := ledger.NewPriceHistory(coinList)
+		report := ledger.CalculateTax(transactions, prices, year, taxSource)
+
+		if err := prices.Save(); err != nil {
+			log.Warnf("Could not cache prices: %v", err)
+		}
+
+		if err := utils.OutputResult(report); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func taxCmdInit() {
+	rootCmd.AddCommand(cmdTax)
+	cmdTax.Flags().StringVarP(&taxSource, "source", "s", "", "Only report disposals and income of a source")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var transfersUnmatched bool
+
+var cmdTransfers = &cobra.Command{
+	Use:   "transfers",
+	Short: "Match withdrawals to deposits across all sources",
+	Run: func(cmd *cobra.Command, args []string) {
+		transactions, err := ledger.LoadAll()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		transfers := ledger.MatchTransfers(transactions)
+		log.Infof("Matched %d transfers, %d withdrawals and deposits left unmatched", len(transfers.Matched), len(transfers.Unmatched))
+
+		if err := utils.WriteToFile("transfers", transfers); err != nil {
+			log.Errorf("Could not save transfers to file: %v", err)
+		}
+
+		var output interface{} = transfers
+		if transfersUnmatched {
+			output = transfers.Unmatched
+		}
+
+		if err := utils.OutputResult(output); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func transfersCmdInit() {
+	rootCmd.AddCommand(cmdTransfers)
+	cmdTransfers.Flags().BoolVarP(&transfersUnmatched, "unmatched", "u", false, "Only list unmatched withdrawals and deposits for review")
+}
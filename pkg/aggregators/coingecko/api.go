@@ -3,6 +3,8 @@ package coingecko
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 )
 
 const (
@@ -39,10 +41,8 @@ type CoinPrice struct {
 	Symbol     string `json:"symbol"`
 	Name       string `json:"name"`
 	MarketData struct {
-		CurrentPrice struct {
-			EUR float64 `json:"eur"`
-			USD float64 `json:"usd"`
-		} `json:"current_price"`
+		// Prices by lowercase currency code
+		CurrentPrice map[string]float64 `json:"current_price"`
 	} `json:"market_data"`
 }
 
@@ -66,3 +66,59 @@ func GetCoinPrice(id string) (*CoinPrice, error) {
 
 	return &coinPrice, nil
 }
+
+type CoinHistory struct {
+	ID         string `json:"id"`
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+	MarketData struct {
+		// Prices by lowercase currency code
+		CurrentPrice map[string]float64 `json:"current_price"`
+	} `json:"market_data"`
+}
+
+// Get a coin price at a given day, in UTC
+func GetCoinHistory(id string, date time.Time) (*CoinHistory, error) {
+	client := NewClient()
+	params := map[string]string{
+		"date":         date.UTC().Format("02-01-2006"),
+		"localization": "false",
+	}
+	body, err := client.RequestWithRetries(fmt.Sprintf("%s/%s/history", coinPricesEndpoint, id), params)
+	if err != nil {
+		return nil, fmt.Errorf("could not request coin history endpoint: %w", err)
+	}
+
+	coinHistory := CoinHistory{}
+	if err := json.Unmarshal(body, &coinHistory); err != nil {
+		return nil, fmt.Errorf("could not unmarshal coin history: %w", err)
+	}
+
+	return &coinHistory, nil
+}
+
+type CoinMarketChart struct {
+	// Unix milliseconds time and price pairs
+	Prices [][2]float64 `json:"prices"`
+}
+
+// Get a coin prices in a currency between two Unix milliseconds times, daily for ranges above 90 days
+func GetCoinMarketChart(id string, currency string, from int64, to int64) (*CoinMarketChart, error) {
+	client := NewClient()
+	params := map[string]string{
+		"vs_currency": strings.ToLower(currency),
+		"from":        fmt.Sprintf("%d", from/1000),
+		"to":          fmt.Sprintf("%d", to/1000),
+	}
+	body, err := client.RequestWithRetries(fmt.Sprintf("%s/%s/market_chart/range", coinPricesEndpoint, id), params)
+	if err != nil {
+		return nil, fmt.Errorf("could not request coin market chart endpoint: %w", err)
+	}
+
+	coinMarketChart := CoinMarketChart{}
+	if err := json.Unmarshal(body, &coinMarketChart); err != nil {
+		return nil, fmt.Errorf("could not unmarshal coin market chart: %w", err)
+	}
+
+	return &coinMarketChart, nil
+}
@@ -8,10 +8,14 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+// Name of the aggregator in fetcher metrics
+const source = "coingecko"
+
 type Client struct {
 	HTTPClient *http.Client
 	BaseURL    string
@@ -82,6 +86,7 @@ func (c *Client) RequestWithRetries(endpoint string, parameters map[string]strin
 	retries := 0
 
 	for {
+		metrics.Request(source)
 		body, err = c.request(endpoint, parameters)
 		if body != nil {
 			break
@@ -93,6 +98,7 @@ func (c *Client) RequestWithRetries(endpoint string, parameters map[string]strin
 		}
 
 		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
 		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
 		time.Sleep(time.Second * c.RetryDelay)
 	}
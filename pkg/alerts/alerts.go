@@ -0,0 +1,190 @@
+// Handles alert evaluation and notification
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const stateFile = "alerts_state"
+
+// A notification of a rule being met
+type Alert struct {
+	Rule  string  `json:"rule"`
+	Type  string  `json:"type"`
+	Asset string  `json:"asset,omitempty"`
+	Value float64 `json:"value"`
+	Text  string  `json:"text"`
+	Time  int64   `json:"time"`
+}
+
+// Return the alert as a single line
+func (a Alert) String() string {
+	return fmt.Sprintf("%s [%s] %s", time.UnixMilli(a.Time).Format("2006-01-02 15:04:05"), a.Rule, a.Text)
+}
+
+// Outcome of a rule evaluation
+type Result struct {
+	Rule     string  `json:"rule"`
+	Met      bool    `json:"met"`
+	Value    float64 `json:"value"`
+	Text     string  `json:"text,omitempty"`
+	Notified bool    `json:"notified"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// Whether a rule is met since its last notification, kept between runs
+type ruleState struct {
+	Active       bool  `json:"active"`
+	LastNotified int64 `json:"lastNotified,omitempty"`
+}
+
+// Check if any alert rule is configured
+func Configured() bool {
+	return viper.IsSet("alerts.rules")
+}
+
+// Load rule states saved by previous evaluations, by rule name
+func loadState() (map[string]*ruleState, error) {
+	state := make(map[string]*ruleState)
+	if !utils.FileExists(fmt.Sprintf("%s.json", stateFile)) {
+		return state, nil
+	}
+
+	if err := json.Unmarshal(utils.LoadFromFile(fmt.Sprintf("%s.json", stateFile)), &state); err != nil {
+		return nil, fmt.Errorf("could not unmarshal alerts state: %w", err)
+	}
+
+	return state, nil
+}
+
+// Send an alert to every sink, a failing sink not preventing others from being notified
+func notify(sinks []Sink, alert Alert) bool {
+	sent := false
+	for _, sink := range sinks {
+		if err := sink.Notify(alert); err != nil {
+			log.Errorf("Could not notify '%s' alert to %s: %v", alert.Rule, sink.Name(), err)
+			continue
+		}
+		sent = true
+	}
+
+	return sent
+}
+
+// Evaluate every rule against the latest snapshots, notifying those becoming met
+func Evaluate() ([]Result, error) {
+	sinks, err := LoadSinks()
+	if err != nil {
+		return nil, err
+	}
+	portfolio, err := LoadPortfolio()
+	if err != nil {
+		return nil, err
+	}
+
+	return EvaluatePortfolio(portfolio, sinks)
+}
+
+// Evaluate every rule against a portfolio, notifying sinks of those becoming met
+// A rule notifies once when it becomes met, and not again before its cooldown is over
+func EvaluatePortfolio(portfolio *Portfolio, sinks []Sink) ([]Result, error) {
+	rules, err := LoadRules()
+	if err != nil {
+		return nil, err
+	}
+	if len(sinks) == 0 {
+		log.Warn("No alert sink enabled under 'alerts.sinks'")
+	}
+
+	state, err := loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []Result{}
+	for _, rule := range rules {
+		result := Result{Rule: rule.Name}
+		met, value, text, err := rule.evaluate(portfolio)
+		if err != nil {
+			log.Errorf("Could not evaluate '%s' alert: %v", rule.Name, err)
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Met, result.Value, result.Text = met, value, text
+
+		if state[rule.Name] == nil {
+			state[rule.Name] = &ruleState{}
+		}
+		s := state[rule.Name]
+
+		now := time.Now()
+		switch {
+		case !met:
+			s.Active = false
+		case s.Active:
+		case now.Sub(time.UnixMilli(s.LastNotified)) < time.Duration(rule.Cooldown)*time.Second:
+			log.Infof("Alert '%s' is met but cooling down", rule.Name)
+		default:
+			alert := Alert{Rule: rule.Name, Type: rule.Type, Asset: rule.Asset, Value: value, Text: text, Time: now.UnixMilli()}
+			if notify(sinks, alert) {
+				s.Active = true
+				s.LastNotified = alert.Time
+				result.Notified = true
+			}
+		}
+		results = append(results, result)
+	}
+
+	if err := utils.WriteToFile(stateFile, state); err != nil {
+		return nil, fmt.Errorf("could not save alerts state: %w", err)
+	}
+
+	return results, nil
+}
+
+// Evaluate rules against the latest snapshots once wallets have been refreshed and saved, when any is configured
+func AfterRefresh() error {
+	if !Configured() {
+		return nil
+	}
+
+	log.Info("Evaluating alerts")
+	_, err := Evaluate()
+
+	return err
+}
+
+// Send a test alert to every sink, failing when any sink does
+func Test() error {
+	sinks, err := LoadSinks()
+	if err != nil {
+		return err
+	}
+	if len(sinks) == 0 {
+		return fmt.Errorf("no alert sink enabled under 'alerts.sinks'")
+	}
+
+	alert := Alert{Rule: "test", Type: "test", Text: "Test alert from tracklet", Time: time.Now().UnixMilli()}
+	failed := 0
+	for _, sink := range sinks {
+		if err := sink.Notify(alert); err != nil {
+			log.Errorf("Could not notify %s: %v", sink.Name(), err)
+			failed++
+			continue
+		}
+		log.Infof("Test alert sent to %s", sink.Name())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sinks failed", failed, len(sinks))
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// Record alerts instead of sending them
+type recordingSink struct {
+	alerts []Alert
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Notify(alert Alert) error {
+	s.alerts = append(s.alerts, alert)
+
+	return nil
+}
+
+func TestEvaluatePortfolioCooldown(t *testing.T) {
+	tests := []struct {
+		name     string
+		cooldown int
+		// Whether each evaluation notifies, the portfolio value being met, met, not met then met again
+		notified []bool
+	}{
+		{name: "cooling down", cooldown: 3600, notified: []bool{true, false, false, false}},
+		{name: "cooled down", cooldown: 0, notified: []bool{true, false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if err := os.MkdirAll(filepath.Join(home, ".tracklet", "data"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			viper.Set("alerts.cooldown", tt.cooldown)
+			viper.Set("alerts.rules", []map[string]interface{}{{"name": "low", "type": TypeValue, "below": 1500}})
+			t.Cleanup(func() {
+				viper.Set("alerts.cooldown", nil)
+				viper.Set("alerts.rules", nil)
+			})
+
+			sink := &recordingSink{}
+			for i, value := range []float64{1000, 1000, 2000, 1000} {
+				portfolio := &Portfolio{Currency: "EUR", Value: value}
+				results, err := EvaluatePortfolio(portfolio, []Sink{sink})
+				if err != nil {
+					t.Fatalf("evaluation %d error: %v", i, err)
+				}
+				if len(results) != 1 {
+					t.Fatalf("evaluation %d gave %d results, want 1", i, len(results))
+				}
+				if results[0].Met != (value < 1500) || results[0].Notified != tt.notified[i] {
+					t.Errorf("evaluation %d = met %t notified %t, want met %t notified %t", i, results[0].Met, results[0].Notified, value < 1500, tt.notified[i])
+				}
+			}
+
+			want := 0
+			for _, notified := range tt.notified {
+				if notified {
+					want++
+				}
+			}
+			if len(sink.alerts) != want {
+				t.Errorf("sink got %d alerts, want %d", len(sink.alerts), want)
+			}
+		})
+	}
+}
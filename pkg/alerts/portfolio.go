@@ -0,0 +1,106 @@
+// Handles the portfolio alert rules are evaluated against
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+)
+
+// Value of every source added up, by asset
+type Portfolio struct {
+	Currency string
+	Value    float64
+	Assets   map[string]float64
+	// Fiat and stablecoins counted as cash, left out of rules on any asset
+	Cash map[string]bool
+	// Coin IDs, retrieved once needed
+	coins ledger.Coins
+}
+
+// Build the portfolio from the latest snapshot of every source
+func LoadPortfolio() (*Portfolio, error) {
+	snapshots, err := ledger.LoadAllSnapshots()
+	if err != nil {
+		return nil, fmt.Errorf("could not load snapshots: %w", err)
+	}
+
+	latest := make(map[string]ledger.Snapshot)
+	for _, snapshot := range snapshots {
+		latest[snapshot.Source] = snapshot
+	}
+	if len(latest) == 0 {
+		return nil, fmt.Errorf("no snapshot found, calculate a wallet first")
+	}
+
+	portfolio := &Portfolio{
+		Currency: ledger.ReportingCurrency(),
+		Assets:   make(map[string]float64),
+		Cash:     make(map[string]bool),
+	}
+	for _, snapshot := range latest {
+		if snapshot.Stats.Currency != "" {
+			portfolio.Currency = snapshot.Stats.Currency
+		}
+		for asset, d := range snapshot.Holdings {
+			portfolio.Assets[asset] += d.CurrentValue
+		}
+		for asset, d := range snapshot.Cash {
+			portfolio.Assets[asset] += d.CurrentValue
+			portfolio.Cash[asset] = true
+		}
+		portfolio.Value += snapshot.Stats.TotalValue
+	}
+
+	return portfolio, nil
+}
+
+// Return the share of the portfolio value an asset amounts to, in percent
+func (p *Portfolio) Allocation(asset string) float64 {
+	if p.Value == 0 {
+		return 0
+	}
+
+	return p.Assets[asset] / p.Value * 100
+}
+
+// Return the Coingecko ID of an asset, getting the coin list the first time
+func (p *Portfolio) coinID(asset string) (string, error) {
+	if p.coins == nil {
+		coinList, err := coingecko.GetCoinList()
+		if err != nil {
+			return "", fmt.Errorf("could not get coin list: %w", err)
+		}
+		p.coins = ledger.NewCoins(coinList)
+	}
+
+	id, ok := p.coins.ID(p.coins.PricedAsset(asset))
+	if !ok {
+		return "", fmt.Errorf("no Coingecko coin found for '%s'", asset)
+	}
+
+	return id, nil
+}
+
+// Return the price change of an asset over a period until now, in percent
+func (p *Portfolio) PriceChange(asset string, period time.Duration) (float64, error) {
+	id, err := p.coinID(asset)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	chart, err := coingecko.GetCoinMarketChart(id, p.Currency, now.Add(-period).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("could not get '%s' market chart: %w", id, err)
+	}
+	if len(chart.Prices) < 2 || chart.Prices[0][1] == 0 {
+		return 0, fmt.Errorf("not enough '%s' prices over %s", id, period)
+	}
+
+	first, last := chart.Prices[0][1], chart.Prices[len(chart.Prices)-1][1]
+
+	return (last - first) / first * 100, nil
+}
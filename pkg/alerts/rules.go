@@ -0,0 +1,145 @@
+// Handles alert rules configuration and evaluation
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Kinds of rules
+const (
+	// Price change of an asset over a period, in percent
+	TypeChange = "change"
+	// Share of the portfolio value of an asset, or of any asset, in percent
+	TypeAllocation = "allocation"
+	// Total portfolio value, in reporting currency
+	TypeValue = "value"
+)
+
+const defaultPeriod = 24
+
+// A condition on the portfolio, met when its value is above or below a threshold
+type Rule struct {
+	Name  string   `mapstructure:"name" json:"name"`
+	Type  string   `mapstructure:"type" json:"type"`
+	Asset string   `mapstructure:"asset" json:"asset,omitempty"`
+	Above *float64 `mapstructure:"above" json:"above,omitempty"`
+	Below *float64 `mapstructure:"below" json:"below,omitempty"`
+	// Hours a price change is measured over
+	Period int `mapstructure:"period" json:"period,omitempty"`
+	// Seconds before the rule notifies again, `alerts.cooldown` when not set
+	Cooldown int `mapstructure:"cooldown" json:"cooldown,omitempty"`
+}
+
+// Load rules set in configuration, checking they are complete
+func LoadRules() ([]Rule, error) {
+	rules := []Rule{}
+	if err := viper.UnmarshalKey("alerts.rules", &rules); err != nil {
+		return nil, fmt.Errorf("could not unmarshal alert rules: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for i := range rules {
+		rule := &rules[i]
+		rule.Type = strings.ToLower(rule.Type)
+		rule.Asset = strings.ToUpper(rule.Asset)
+		if rule.Name == "" {
+			rule.Name = rule.describe()
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("alert rule '%s' is defined twice", rule.Name)
+		}
+		names[rule.Name] = true
+
+		switch rule.Type {
+		case TypeChange:
+			if rule.Asset == "" {
+				return nil, fmt.Errorf("alert rule '%s' needs an asset", rule.Name)
+			}
+			if rule.Period <= 0 {
+				rule.Period = defaultPeriod
+			}
+		case TypeAllocation, TypeValue:
+		default:
+			return nil, fmt.Errorf("alert rule '%s' has unknown type '%s', expected change, allocation or value", rule.Name, rule.Type)
+		}
+
+		if rule.Above == nil && rule.Below == nil {
+			return nil, fmt.Errorf("alert rule '%s' needs an 'above' or 'below' threshold", rule.Name)
+		}
+		if rule.Cooldown <= 0 {
+			rule.Cooldown = viper.GetInt("alerts.cooldown")
+		}
+	}
+
+	return rules, nil
+}
+
+// Describe a rule lacking a name
+func (r *Rule) describe() string {
+	subject := r.Type
+	if r.Asset != "" {
+		subject = fmt.Sprintf("%s %s", r.Asset, r.Type)
+	}
+	if r.Above != nil {
+		return fmt.Sprintf("%s above %v", subject, *r.Above)
+	}
+	if r.Below != nil {
+		return fmt.Sprintf("%s below %v", subject, *r.Below)
+	}
+
+	return subject
+}
+
+// Check if a value crosses the rule thresholds
+func (r *Rule) crosses(value float64) bool {
+	return (r.Above != nil && value > *r.Above) || (r.Below != nil && value < *r.Below)
+}
+
+// Evaluate the rule, returning whether it is met, the value compared to thresholds and a message describing it
+func (r *Rule) evaluate(portfolio *Portfolio) (bool, float64, string, error) {
+	switch r.Type {
+	case TypeChange:
+		change, err := portfolio.PriceChange(r.Asset, time.Duration(r.Period)*time.Hour)
+		if err != nil {
+			return false, 0, "", err
+		}
+
+		return r.crosses(change), change, fmt.Sprintf("%s price changed by %.2f%% over %dh", r.Asset, change, r.Period), nil
+	case TypeAllocation:
+		if r.Asset != "" {
+			allocation := portfolio.Allocation(r.Asset)
+			return r.crosses(allocation), allocation, fmt.Sprintf("%s amounts to %.2f%% of the portfolio", r.Asset, allocation), nil
+		}
+
+		// Any asset but cash, the highest allocation being reported
+		assets := []string{}
+		highest := 0.0
+		for asset := range portfolio.Assets {
+			if portfolio.Cash[asset] {
+				continue
+			}
+			allocation := portfolio.Allocation(asset)
+			if r.crosses(allocation) {
+				assets = append(assets, fmt.Sprintf("%s %.2f%%", asset, allocation))
+				if allocation > highest {
+					highest = allocation
+				}
+			}
+		}
+		sort.Strings(assets)
+		if len(assets) == 0 {
+			return false, 0, "", nil
+		}
+
+		return true, highest, fmt.Sprintf("Portfolio allocation of %s", strings.Join(assets, ", ")), nil
+	case TypeValue:
+		return r.crosses(portfolio.Value), portfolio.Value, fmt.Sprintf("Portfolio value is %.2f %s", portfolio.Value, portfolio.Currency), nil
+	}
+
+	return false, 0, "", fmt.Errorf("unknown rule type '%s'", r.Type)
+}
@@ -0,0 +1,64 @@
+package alerts
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/spf13/viper"
+)
+
+func threshold(value float64) *float64 {
+	return &value
+}
+
+func TestRuleEvaluate(t *testing.T) {
+	// BTC went from 100 to 88 over the period
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/coins/bitcoin/market_chart/range" || r.URL.Query().Get("vs_currency") != "eur" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"prices": [[1000, 100], [2000, 95], [3000, 88]]}`)
+	}))
+	defer server.Close()
+	viper.Set("aggregators.coingecko.apiBaseURL", server.URL)
+	t.Cleanup(func() { viper.Set("aggregators.coingecko.apiBaseURL", nil) })
+
+	portfolio := &Portfolio{
+		Currency: "EUR",
+		Value:    1000,
+		Assets:   map[string]float64{"BTC": 500, "ETH": 300, "EUR": 200},
+		Cash:     map[string]bool{"EUR": true},
+		coins:    ledger.Coins{"BTC": "bitcoin", "ETH": "ethereum"},
+	}
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		met   bool
+		value float64
+	}{
+		{name: "change below", rule: Rule{Type: TypeChange, Asset: "BTC", Period: 24, Below: threshold(-10)}, met: true, value: -12},
+		{name: "change not above", rule: Rule{Type: TypeChange, Asset: "BTC", Period: 24, Above: threshold(5)}, value: -12},
+		{name: "allocation of an asset", rule: Rule{Type: TypeAllocation, Asset: "ETH", Above: threshold(25)}, met: true, value: 30},
+		{name: "allocation of any asset", rule: Rule{Type: TypeAllocation, Above: threshold(40)}, met: true, value: 50},
+		{name: "allocation of any asset but cash", rule: Rule{Type: TypeAllocation, Below: threshold(25)}},
+		{name: "value below", rule: Rule{Type: TypeValue, Below: threshold(1500)}, met: true, value: 1000},
+		{name: "value not above", rule: Rule{Type: TypeValue, Above: threshold(1500)}, value: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			met, value, _, err := tt.rule.evaluate(portfolio)
+			if err != nil {
+				t.Fatalf("evaluate() error: %v", err)
+			}
+			if met != tt.met || value != tt.value {
+				t.Errorf("evaluate() = %t %f, want %t %f", met, value, tt.met, tt.value)
+			}
+		})
+	}
+}
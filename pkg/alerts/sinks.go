@@ -0,0 +1,217 @@
+// Handles the sinks alerts are sent to
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// A destination of alert notifications
+type Sink interface {
+	Name() string
+	Notify(alert Alert) error
+}
+
+// Return the sinks enabled in configuration
+func LoadSinks() ([]Sink, error) {
+	sinks := []Sink{}
+
+	if viper.GetBool("alerts.sinks.stdout") {
+		sinks = append(sinks, &stdoutSink{})
+	}
+	if file := viper.GetString("alerts.sinks.logFile"); file != "" {
+		sinks = append(sinks, &fileSink{path: os.ExpandEnv(file)})
+	}
+	if url := viper.GetString("alerts.sinks.webhook.url"); url != "" {
+		sinks = append(sinks, &webhookSink{
+			url:     url,
+			headers: viper.GetStringMapString("alerts.sinks.webhook.headers"),
+			client:  &http.Client{Timeout: time.Second * viper.GetDuration("tracklet.timeout")},
+		})
+	}
+	if host := viper.GetString("alerts.sinks.smtp.host"); host != "" {
+		to := viper.GetStringSlice("alerts.sinks.smtp.to")
+		from := viper.GetString("alerts.sinks.smtp.from")
+		if len(to) == 0 || from == "" {
+			return nil, fmt.Errorf("smtp sink needs 'from' and 'to' settings")
+		}
+		sinks = append(sinks, &smtpSink{
+			address:  fmt.Sprintf("%s:%d", host, viper.GetInt("alerts.sinks.smtp.port")),
+			host:     host,
+			username: viper.GetString("alerts.sinks.smtp.username"),
+			password: viper.GetString("alerts.sinks.smtp.password"),
+			from:     from,
+			to:       to,
+		})
+	}
+	if viper.GetBool("alerts.sinks.desktop") {
+		sinks = append(sinks, &desktopSink{})
+	}
+
+	return sinks, nil
+}
+
+// Return sinks but the standard output, for interfaces drawing the terminal themselves
+func WithoutStdout(sinks []Sink) []Sink {
+	filtered := []Sink{}
+	for _, sink := range sinks {
+		if _, ok := sink.(*stdoutSink); !ok {
+			filtered = append(filtered, sink)
+		}
+	}
+
+	return filtered
+}
+
+// Print alerts to the standard output
+type stdoutSink struct{}
+
+func (s *stdoutSink) Name() string {
+	return "stdout"
+}
+
+func (s *stdoutSink) Notify(alert Alert) error {
+	_, err := fmt.Println(alert.String())
+
+	return err
+}
+
+// Append alerts to a file, one per line
+type fileSink struct {
+	path string
+}
+
+func (s *fileSink) Name() string {
+	return "logFile"
+}
+
+func (s *fileSink) Notify(alert Alert) error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open alerts log file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintln(file, alert.String()); err != nil {
+		return fmt.Errorf("could not write alerts log file: %w", err)
+	}
+
+	return nil
+}
+
+// Post alerts as JSON to a URL, their `text` field being understood by chat webhooks
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Notify(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("could not marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Send alerts by email
+type smtpSink struct {
+	address  string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func (s *smtpSink) Name() string {
+	return "smtp"
+}
+
+func (s *smtpSink) Notify(alert Alert) error {
+	message := strings.Join([]string{
+		fmt.Sprintf("From: %s", s.from),
+		fmt.Sprintf("To: %s", strings.Join(s.to, ", ")),
+		fmt.Sprintf("Subject: tracklet alert: %s", alert.Rule),
+		fmt.Sprintf("Date: %s", time.UnixMilli(alert.Time).Format(time.RFC1123Z)),
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		alert.Text,
+		"",
+	}, "\r\n")
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	if err := smtp.SendMail(s.address, auth, s.from, s.to, []byte(message)); err != nil {
+		return fmt.Errorf("could not send email: %w", err)
+	}
+
+	return nil
+}
+
+// Show alerts as desktop notifications
+type desktopSink struct{}
+
+func (s *desktopSink) Name() string {
+	return "desktop"
+}
+
+func (s *desktopSink) Notify(alert Alert) error {
+	title := fmt.Sprintf("tracklet: %s", alert.Rule)
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("notify-send", title, alert.Text)
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", alert.Text, title))
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("could not show desktop notification: %w: %s", err, message)
+		}
+		return fmt.Errorf("could not show desktop notification: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package alerts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestWebhookSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "accepted", status: http.StatusNoContent},
+		{name: "rejected", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received Alert
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Token") != "secret" {
+					t.Errorf("got %s request with content type '%s' and token '%s'", r.Method, r.Header.Get("Content-Type"), r.Header.Get("X-Token"))
+				}
+				if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+					t.Errorf("could not decode webhook body: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			viper.Set("alerts.sinks.stdout", true)
+			viper.Set("alerts.sinks.webhook.url", server.URL)
+			viper.Set("alerts.sinks.webhook.headers", map[string]string{"X-Token": "secret"})
+			t.Cleanup(func() {
+				viper.Set("alerts.sinks.stdout", nil)
+				viper.Set("alerts.sinks.webhook.url", nil)
+				viper.Set("alerts.sinks.webhook.headers", nil)
+			})
+
+			sinks, err := LoadSinks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sinks = WithoutStdout(sinks)
+			if len(sinks) != 1 || sinks[0].Name() != "webhook" {
+				t.Fatalf("got %d sinks, want the webhook only", len(sinks))
+			}
+
+			alert := Alert{Rule: "low", Type: TypeValue, Value: 1000, Text: "Portfolio value is 1000.00 EUR", Time: 1000}
+			err = sinks[0].Notify(alert)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify() error = %v, want error %t", err, tt.wantErr)
+			}
+			if received != alert {
+				t.Errorf("webhook received %+v, want %+v", received, alert)
+			}
+		})
+	}
+}
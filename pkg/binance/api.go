@@ -16,6 +16,10 @@ const (
 	dividendHistoryEndpoint       = "/sapi/v1/asset/assetDividend"
 	depositHistoryEndpoint        = "/sapi/v1/capital/deposit/hisrec"
 	withdrawHistoryEndpoint       = "/sapi/v1/capital/withdraw/history"
+	accountEndpoint               = "/api/v3/account"
+	fundingAssetEndpoint          = "/sapi/v1/asset/get-funding-asset"
+	flexiblePositionEndpoint      = "/sapi/v1/simple-earn/flexible/position"
+	lockedPositionEndpoint        = "/sapi/v1/simple-earn/locked/position"
 )
 
 type TradingPairs struct {
@@ -214,6 +218,7 @@ func GetDividendHistory() (*DividendHistory, error) {
 type DepositHistory struct {
 	Amount     string `json:"amount"`
 	Coin       string `json:"coin"`
+	TxID       string `json:"txId"`
 	InsertTime int    `json:"insertTime"`
 }
 
@@ -250,9 +255,12 @@ func GetDepositHistory() (*[]DepositHistory, error) {
 }
 
 type WithdrawHistory struct {
-	Amount    string `json:"amount"`
-	Coin      string `json:"coin"`
-	ApplyTime string `json:"applyTime"`
+	ID             string `json:"id"`
+	Amount         string `json:"amount"`
+	TransactionFee string `json:"transactionFee"`
+	Coin           string `json:"coin"`
+	TxID           string `json:"txId"`
+	ApplyTime      string `json:"applyTime"`
 }
 
 // Get withdraw history
@@ -286,3 +294,116 @@ func GetWithdrawHistory() (*[]WithdrawHistory, error) {
 
 	return &withdrawHistory, nil
 }
+
+type Account struct {
+	Balances []struct {
+		Asset  string `json:"asset"`
+		Free   string `json:"free"`
+		Locked string `json:"locked"`
+	} `json:"balances"`
+}
+
+// Get spot account balances
+func GetAccount() (*Account, error) {
+	client := NewClient()
+	params := map[string]string{
+		"omitZeroBalances": "true",
+	}
+
+	body, err := client.RequestWithRetries(accountEndpoint, params)
+	if err != nil {
+		return nil, fmt.Errorf("could not request account endpoint: %w", err)
+	}
+
+	account := Account{}
+	if err := json.Unmarshal(body, &account); err != nil {
+		return nil, fmt.Errorf("could not unmarshal account: %w", err)
+	}
+
+	return &account, nil
+}
+
+type FundingAsset struct {
+	Asset       string `json:"asset"`
+	Free        string `json:"free"`
+	Locked      string `json:"locked"`
+	Freeze      string `json:"freeze"`
+	Withdrawing string `json:"withdrawing"`
+}
+
+// Get funding wallet balances
+func GetFundingAssets() (*[]FundingAsset, error) {
+	client := NewClient()
+	params := map[string]string{
+		"needBtcValuation": "false",
+	}
+
+	body, err := client.PostWithRetries(fundingAssetEndpoint, params)
+	if err != nil {
+		return nil, fmt.Errorf("could not request funding asset endpoint: %w", err)
+	}
+
+	fundingAssets := []FundingAsset{}
+	if err := json.Unmarshal(body, &fundingAssets); err != nil {
+		return nil, fmt.Errorf("could not unmarshal funding assets: %w", err)
+	}
+
+	return &fundingAssets, nil
+}
+
+type EarnPosition struct {
+	Asset       string `json:"asset"`
+	TotalAmount string `json:"totalAmount"`
+	Amount      string `json:"amount"`
+}
+
+// Get every page of a Simple Earn positions endpoint
+func getEarnPositions(endpoint string) ([]EarnPosition, error) {
+	client := NewClient()
+	positions := []EarnPosition{}
+
+	for current := 1; ; current++ {
+		params := map[string]string{
+			"current": fmt.Sprintf("%d", current),
+			"size":    "100",
+		}
+
+		body, err := client.RequestWithRetries(endpoint, params)
+		if err != nil {
+			return nil, err
+		}
+
+		page := struct {
+			Rows  []EarnPosition `json:"rows"`
+			Total int            `json:"total"`
+		}{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("could not unmarshal earn positions: %w", err)
+		}
+
+		positions = append(positions, page.Rows...)
+
+		if len(page.Rows) == 0 || len(positions) >= page.Total {
+			break
+		}
+	}
+
+	return positions, nil
+}
+
+// Get Simple Earn flexible and locked positions
+func GetEarnPositions() (*[]EarnPosition, error) {
+	flexible, err := getEarnPositions(flexiblePositionEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not request flexible positions endpoint: %w", err)
+	}
+
+	locked, err := getEarnPositions(lockedPositionEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not request locked positions endpoint: %w", err)
+	}
+
+	positions := append(flexible, locked...)
+
+	return &positions, nil
+}
@@ -0,0 +1,56 @@
+// Handles live balances retrieval
+package binance
+
+import (
+	"strings"
+)
+
+// Get live balances per asset, summing spot, funding and earn wallets
+func GetBalances() (map[string]float64, error) {
+	balances := make(map[string]float64)
+
+	earn, err := GetEarnPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	inEarn := make(map[string]bool)
+	for _, p := range *earn {
+		amount := p.TotalAmount
+		if amount == "" {
+			amount = p.Amount
+		}
+		balances[p.Asset] += parseFloat(amount)
+		inEarn[p.Asset] = true
+	}
+
+	account, err := GetAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range account.Balances {
+		// Flexible earn positions may also show in spot as "LD" prefixed assets
+		if strings.HasPrefix(b.Asset, "LD") && inEarn[strings.TrimPrefix(b.Asset, "LD")] {
+			continue
+		}
+		balances[b.Asset] += parseFloat(b.Free) + parseFloat(b.Locked)
+	}
+
+	funding, err := GetFundingAssets()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range *funding {
+		balances[f.Asset] += parseFloat(f.Free) + parseFloat(f.Locked) + parseFloat(f.Freeze) + parseFloat(f.Withdrawing)
+	}
+
+	for asset, balance := range balances {
+		if balance == 0 {
+			delete(balances, asset)
+		}
+	}
+
+	return balances, nil
+}
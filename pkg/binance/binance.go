@@ -3,8 +3,9 @@ package binance
 
 import (
 	"fmt"
-	"os"
 
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
 	"github.com/eliasbokreta/tracklet/pkg/utils"
 	log "github.com/sirupsen/logrus"
 )
@@ -24,47 +25,71 @@ func New() *Binance {
 	return &Binance{}
 }
 
+// Create a new Wallet object computed from the Binance ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
 // Write all fetched data to files
-func (b *Binance) saveDataToFile() {
+func (b *Binance) saveDataToFile() error {
 	if err := utils.WriteToFile("trading_pairs", b.TradingPairs); err != nil {
 		log.Errorf("Could not write data to trading_pairs: %v", err)
+	} else {
+		metrics.Synced(source, "trading_pairs")
 	}
 
 	if err := utils.WriteToFile("fiat_payments", b.FiatPayments); err != nil {
 		log.Errorf("Could not write data to fiat_payments: %v", err)
+	} else {
+		metrics.Synced(source, "fiat_payments")
 	}
 
 	if err := utils.WriteToFile("trading_history", b.TradingHistory); err != nil {
 		log.Errorf("Could not write data to trading_history: %v", err)
+	} else {
+		metrics.Synced(source, "trading_history")
 	}
 
 	if err := utils.WriteToFile("dust_conversion", b.DustConversion); err != nil {
 		log.Errorf("Could not write data to dust_conversion: %v", err)
+	} else {
+		metrics.Synced(source, "dust_conversion")
 	}
 
 	if err := utils.WriteToFile("dividend_history", b.DividendHistory); err != nil {
 		log.Errorf("Could not write data to dividend_history: %v", err)
+	} else {
+		metrics.Synced(source, "dividend_history")
 	}
 
 	if err := utils.WriteToFile("deposit_history", b.DepositHistory); err != nil {
 		log.Errorf("Could not write data to deposit_history: %v", err)
+	} else {
+		metrics.Synced(source, "deposit_history")
 	}
 
 	if err := utils.WriteToFile("withdraw_history", b.WithdrawHistory); err != nil {
 		log.Errorf("Could not write data to withdraw_history: %v", err)
+	} else {
+		metrics.Synced(source, "withdraw_history")
 	}
+
+	if err := ledger.Save(source, b.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
 }
 
 // Retrieve all account data from Binance
-func (b *Binance) ProcessBinanceData(verbose bool) {
+func (b *Binance) ProcessBinanceData(verbose bool) error {
 	log.Info("Starting process Binance data...")
 
 	// EXCHANGE'S TRADING PAIRS
 	log.Info("Fetching trading pairs data...")
 	tradingPairs, err := GetTradingPairs()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	b.TradingPairs = tradingPairs
@@ -73,16 +98,14 @@ func (b *Binance) ProcessBinanceData(verbose bool) {
 	log.Info("Fetching fiat payments history data...")
 	fiatPayments, err := GetFiatPaymentsHistory()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	b.FiatPayments = fiatPayments
 
 	if verbose {
 		if err := utils.OutputResult(b.FiatPayments); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
 
@@ -90,16 +113,14 @@ func (b *Binance) ProcessBinanceData(verbose bool) {
 	log.Info("Fetching trading history data...")
 	tradingHistory, err := GetTradingHistory(b.TradingPairs)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	b.TradingHistory = tradingHistory
 
 	if verbose {
 		if err := utils.OutputResult(b.TradingHistory); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
 
@@ -107,16 +128,14 @@ func (b *Binance) ProcessBinanceData(verbose bool) {
 	log.Info("Fetching dust conversion history data...")
 	dustConversion, err := GetDustConversionHistory()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	b.DustConversion = dustConversion
 
 	if verbose {
 		if err := utils.OutputResult(b.DustConversion); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
 
@@ -124,16 +143,14 @@ func (b *Binance) ProcessBinanceData(verbose bool) {
 	log.Info("Fetching dividend history data...")
 	dividendHistory, err := GetDividendHistory()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	b.DividendHistory = dividendHistory
 
 	if verbose {
 		if err := utils.OutputResult(b.DividendHistory); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
 
@@ -141,16 +158,14 @@ func (b *Binance) ProcessBinanceData(verbose bool) {
 	log.Info("Fetching deposit history data...")
 	depositHistory, err := GetDepositHistory()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	b.DepositHistory = depositHistory
 
 	if verbose {
 		if err := utils.OutputResult(b.DepositHistory); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
 
@@ -158,18 +173,16 @@ func (b *Binance) ProcessBinanceData(verbose bool) {
 	log.Info("Fetching withdraw history data...")
 	withdrawHistory, err := GetWithdrawHistory()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	b.WithdrawHistory = withdrawHistory
 
 	if verbose {
 		if err := utils.OutputResult(b.WithdrawHistory); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
 
-	b.saveDataToFile()
+	return b.saveDataToFile()
 }
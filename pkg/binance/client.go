@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -102,9 +103,9 @@ func (c *Client) buildQueryString(q url.Values, params map[string]string) url.Va
 	return q
 }
 
-// HTTP get request for a given Binance API endpoint
-func (c *Client) request(endpoint string, parameters map[string]string) ([]byte, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", c.BaseURL, endpoint), nil)
+// HTTP request for a given Binance API endpoint, signed parameters being sent in the query string
+func (c *Client) request(method string, endpoint string, parameters map[string]string) ([]byte, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.BaseURL, endpoint), nil)
 	if err != nil {
 		return nil, fmt.Errorf("error while executing request: %w", err)
 	}
@@ -136,14 +137,25 @@ func (c *Client) request(endpoint string, parameters map[string]string) ([]byte,
 	return body, nil
 }
 
-// Retry mechanism for HTTP requests
+// Retry mechanism for HTTP get requests
 func (c *Client) RequestWithRetries(endpoint string, parameters map[string]string) ([]byte, error) {
+	return c.requestWithRetries("GET", endpoint, parameters)
+}
+
+// Retry mechanism for HTTP post requests
+func (c *Client) PostWithRetries(endpoint string, parameters map[string]string) ([]byte, error) {
+	return c.requestWithRetries("POST", endpoint, parameters)
+}
+
+// Retry mechanism for HTTP requests
+func (c *Client) requestWithRetries(method string, endpoint string, parameters map[string]string) ([]byte, error) {
 	var body []byte
 	var err error
 	retries := 0
 
 	for {
-		body, err = c.request(endpoint, parameters)
+		metrics.Request(source)
+		body, err = c.request(method, endpoint, parameters)
 		if body != nil {
 			break
 		}
@@ -154,6 +166,7 @@ func (c *Client) RequestWithRetries(endpoint string, parameters map[string]strin
 		}
 
 		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
 		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
 		time.Sleep(time.Second * c.RetryDelay)
 	}
@@ -0,0 +1,209 @@
+// Handles conversion of Binance data to ledger transactions
+package binance
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const source = "binance"
+
+// Parse a Binance decimal string, defaulting to zero
+func parseFloat(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Errorf("Could not convert string to float: %v", err)
+		return 0
+	}
+
+	return f
+}
+
+// Parse a Binance UTC datetime to Unix milliseconds
+func parseTime(value string) int64 {
+	t, err := time.Parse("2006-01-02 15:04:05", value)
+	if err != nil {
+		log.Errorf("Could not parse time: %v", err)
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+// Card purchases are a fiat deposit immediately spent on a buy
+func (b *Binance) fiatPaymentsToLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+	if b.FiatPayments == nil {
+		return transactions
+	}
+
+	for _, fp := range b.FiatPayments.Data {
+		if fp.Status != "Completed" {
+			continue
+		}
+
+		sourceAmount := parseFloat(fp.SourceAmount)
+		totalFee := parseFloat(fp.TotalFee)
+
+		transactions = append(transactions,
+			ledger.Transaction{
+				ID:     fmt.Sprintf("fiat-deposit-%s", fp.OrderNo),
+				Source: source,
+				Type:   ledger.TypeDeposit,
+				Time:   int64(fp.CreateTime),
+				Asset:  fp.FiatCurrency,
+				Amount: sourceAmount,
+			},
+			ledger.Transaction{
+				ID:          fmt.Sprintf("fiat-payment-%s", fp.OrderNo),
+				Source:      source,
+				Type:        ledger.TypeBuy,
+				Time:        int64(fp.CreateTime),
+				Asset:       fp.CryptoCurrency,
+				Amount:      parseFloat(fp.ObtainAmount),
+				QuoteAsset:  fp.FiatCurrency,
+				QuoteAmount: sourceAmount - totalFee,
+				FeeAsset:    fp.FiatCurrency,
+				FeeAmount:   totalFee,
+			},
+		)
+	}
+
+	return transactions
+}
+
+// Convert trades, using trading pairs to split symbols into assets
+func (b *Binance) tradingHistoryToLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+	if b.TradingHistory == nil {
+		return transactions
+	}
+
+	pairs := make(map[string][2]string)
+	if b.TradingPairs != nil {
+		for _, tp := range b.TradingPairs.Symbols {
+			pairs[tp.Symbol] = [2]string{tp.BaseAsset, tp.QuoteAsset}
+		}
+	}
+
+	for _, th := range *b.TradingHistory {
+		pair, ok := pairs[th.Symbol]
+		if !ok {
+			log.Warnf("Unknown Binance symbol '%s'", th.Symbol)
+			continue
+		}
+
+		txType := ledger.TypeBuy
+		if !th.IsBuyer {
+			txType = ledger.TypeSell
+		}
+
+		transactions = append(transactions, ledger.Transaction{
+			ID:          fmt.Sprintf("trade-%s-%d", th.Symbol, th.ID),
+			Source:      source,
+			Type:        txType,
+			Time:        int64(th.Time),
+			Asset:       pair[0],
+			Amount:      parseFloat(th.Quantity),
+			QuoteAsset:  pair[1],
+			QuoteAmount: parseFloat(th.QuoteQuantity),
+			FeeAsset:    th.CommissionAsset,
+			FeeAmount:   parseFloat(th.Commission),
+		})
+	}
+
+	return transactions
+}
+
+// Dust conversions sell small balances for BNB
+func (b *Binance) dustConversionToLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+	if b.DustConversion == nil {
+		return transactions
+	}
+
+	for _, dribblet := range b.DustConversion.UserAssetDribblets {
+		for _, detail := range dribblet.UserAssetDribbletDetails {
+			transactions = append(transactions, ledger.Transaction{
+				ID:          fmt.Sprintf("dust-%d-%s", dribblet.OperateTime, detail.FromAsset),
+				Source:      source,
+				Type:        ledger.TypeSell,
+				Time:        int64(dribblet.OperateTime),
+				Asset:       detail.FromAsset,
+				Amount:      parseFloat(detail.Amount),
+				QuoteAsset:  "BNB",
+				QuoteAmount: parseFloat(detail.TransferedAmount),
+			})
+		}
+	}
+
+	return transactions
+}
+
+// Convert dividends, deposits and withdrawals
+func (b *Binance) transfersToLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+
+	if b.DividendHistory != nil {
+		for _, dividend := range b.DividendHistory.Rows {
+			transactions = append(transactions, ledger.Transaction{
+				ID:     fmt.Sprintf("dividend-%d-%s", dividend.DivTime, dividend.Asset),
+				Source: source,
+				Type:   ledger.TypeIncome,
+				Time:   int64(dividend.DivTime),
+				Asset:  dividend.Asset,
+				Amount: parseFloat(dividend.Amount),
+			})
+		}
+	}
+
+	if b.DepositHistory != nil {
+		for _, deposit := range *b.DepositHistory {
+			transactions = append(transactions, ledger.Transaction{
+				ID:     fmt.Sprintf("deposit-%d-%s", deposit.InsertTime, deposit.Coin),
+				Source: source,
+				Type:   ledger.TypeDeposit,
+				Time:   int64(deposit.InsertTime),
+				Asset:  deposit.Coin,
+				Amount: parseFloat(deposit.Amount),
+				TxHash: deposit.TxID,
+			})
+		}
+	}
+
+	if b.WithdrawHistory != nil {
+		for _, withdraw := range *b.WithdrawHistory {
+			transactions = append(transactions, ledger.Transaction{
+				ID:        fmt.Sprintf("withdraw-%s", withdraw.ID),
+				Source:    source,
+				Type:      ledger.TypeWithdraw,
+				Time:      parseTime(withdraw.ApplyTime),
+				Asset:     withdraw.Coin,
+				Amount:    parseFloat(withdraw.Amount),
+				FeeAsset:  withdraw.Coin,
+				FeeAmount: parseFloat(withdraw.TransactionFee),
+				TxHash:    withdraw.TxID,
+			})
+		}
+	}
+
+	return transactions
+}
+
+// Convert all fetched Binance data to ledger transactions
+func (b *Binance) toLedger() []ledger.Transaction {
+	transactions := b.fiatPaymentsToLedger()
+	transactions = append(transactions, b.tradingHistoryToLedger()...)
+	transactions = append(transactions, b.dustConversionToLedger()...)
+	transactions = append(transactions, b.transfersToLedger()...)
+
+	return transactions
+}
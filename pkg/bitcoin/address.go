@@ -0,0 +1,172 @@
+// Handles address encoding (base58check and bech32)
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const bech32Alphabet = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Compute the 4 bytes checksum of base58check payloads
+func checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+
+	return second[:4]
+}
+
+// Encode a payload with its checksum in base58
+func encodeBase58Check(payload []byte) string {
+	data := append(append([]byte{}, payload...), checksum(payload)...)
+
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	encoded := []byte{}
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	return string(encoded)
+}
+
+// Decode a base58 string and verify its checksum
+func decodeBase58Check(encoded string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+
+	for _, c := range encoded {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character '%c'", c)
+		}
+		n.Mul(n, radix).Add(n, big.NewInt(int64(digit)))
+	}
+
+	leadingZeros := 0
+	for leadingZeros < len(encoded) && encoded[leadingZeros] == base58Alphabet[0] {
+		leadingZeros++
+	}
+	data := append(make([]byte, leadingZeros), n.Bytes()...)
+
+	if len(data) < 4 {
+		return nil, errors.New("base58 data too short")
+	}
+
+	payload := data[:len(data)-4]
+	if !bytes.Equal(checksum(payload), data[len(data)-4:]) {
+		return nil, errors.New("invalid checksum")
+	}
+
+	return payload, nil
+}
+
+// Compute the bech32 checksum polynomial
+func bech32Polymod(values []byte) uint32 {
+	generator := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+// Encode a segwit version 0 program as a bech32 address (BIP173)
+func encodeSegwit(hrp string, program []byte) string {
+	data := []byte{0}
+
+	// Regroup 8 bits bytes into 5 bits words
+	acc, accBits := 0, 0
+	for _, b := range program {
+		acc = acc<<8 | int(b)
+		accBits += 8
+		for accBits >= 5 {
+			accBits -= 5
+			data = append(data, byte(acc>>accBits)&31)
+		}
+	}
+	if accBits > 0 {
+		data = append(data, byte(acc<<(5-accBits))&31)
+	}
+
+	values := []byte{}
+	for _, c := range hrp {
+		values = append(values, byte(c)>>5)
+	}
+	values = append(values, 0)
+	for _, c := range hrp {
+		values = append(values, byte(c)&31)
+	}
+	values = append(values, data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+
+	polymod := bech32Polymod(values) ^ 1
+	for i := 0; i < 6; i++ {
+		data = append(data, byte(polymod>>(5*(5-i)))&31)
+	}
+
+	var address strings.Builder
+	address.WriteString(hrp)
+	address.WriteString("1")
+	for _, d := range data {
+		address.WriteByte(bech32Alphabet[d])
+	}
+
+	return address.String()
+}
+
+// Return the address of a derived public key, according to the key script type
+func (k *ExtendedKey) Address() (string, error) {
+	keyHash := hash160(k.PublicKey)
+
+	switch k.Script {
+	case ScriptP2PKH:
+		version := byte(0x00)
+		if k.Testnet {
+			version = 0x6f
+		}
+		return encodeBase58Check(append([]byte{version}, keyHash...)), nil
+	case ScriptP2SHP2WPKH:
+		version := byte(0x05)
+		if k.Testnet {
+			version = 0xc4
+		}
+		redeemScript := append([]byte{0x00, 0x14}, keyHash...)
+		return encodeBase58Check(append([]byte{version}, hash160(redeemScript)...)), nil
+	case ScriptP2WPKH:
+		hrp := "bc"
+		if k.Testnet {
+			hrp = "tb"
+		}
+		return encodeSegwit(hrp, keyHash), nil
+	default:
+		return "", fmt.Errorf("unsupported script type '%s'", k.Script)
+	}
+}
@@ -0,0 +1,69 @@
+package bitcoin
+
+import "testing"
+
+// First receiving addresses of the BIP44, BIP49 and BIP84 account keys of the test mnemonic "abandon abandon ... about"
+func TestExtendedKeyAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want []string
+	}{
+		{
+			name: "BIP44",
+			key:  "xpub6BosfCnifzxcFwrSzQiqu2DBVTshkCXacvNsWGYJVVhhawA7d4R5WSWGFNbi8Aw6ZRc1brxMyWMzG3DSSSSoekkudhUd9yLb6qx39T9nMdj",
+			want: []string{"1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", "1Ak8PffB2meyfYnbXZR9EGfLfFZVpzJvQP"},
+		},
+		{
+			name: "BIP49",
+			key:  "ypub6Ww3ibxVfGzLrAH1PNcjyAWenMTbbAosGNB6VvmSEgytSER9azLDWCxoJwW7Ke7icmizBMXrzBx9979FfaHxHcrArf3zbeJJJUZPf663zsP",
+			want: []string{"37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf", "3LtMnn87fqUeHBUG414p9CWwnoV6E2pNKS"},
+		},
+		{
+			name: "BIP84",
+			key:  "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs",
+			want: []string{"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParseExtendedKey(tt.key)
+			if err != nil {
+				t.Fatalf("ParseExtendedKey() error = %v", err)
+			}
+
+			receiving, err := key.Child(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				child, err := receiving.Child(uint32(i))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := child.Address()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("address %d = %s, want %s", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseExtendedKeyErrors(t *testing.T) {
+	for _, key := range []string{
+		"",
+		// Checksum altered
+		"zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYt",
+		// Not an extended key
+		"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu",
+	} {
+		if _, err := ParseExtendedKey(key); err == nil {
+			t.Errorf("ParseExtendedKey(%q) succeeded, want an error", key)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Handles Esplora API endpoints logic
+package bitcoin
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	addressTransactionsEndpoint = "/address/%s/txs/chain"
+	pageSize                    = 25
+)
+
+type Output struct {
+	Address string `json:"scriptpubkey_address"`
+	Value   int64  `json:"value"`
+}
+
+type Input struct {
+	TxID       string `json:"txid"`
+	Vout       int    `json:"vout"`
+	Prevout    Output `json:"prevout"`
+	IsCoinbase bool   `json:"is_coinbase"`
+}
+
+type Status struct {
+	Confirmed   bool  `json:"confirmed"`
+	BlockHeight int64 `json:"block_height"`
+	BlockTime   int64 `json:"block_time"`
+}
+
+type Transaction struct {
+	TxID   string   `json:"txid"`
+	Vin    []Input  `json:"vin"`
+	Vout   []Output `json:"vout"`
+	Fee    int64    `json:"fee"`
+	Status Status   `json:"status"`
+}
+
+// Get all confirmed transactions of an address, newest first
+func GetAddressTransactions(address string) ([]Transaction, error) {
+	client := NewClient()
+	transactions := []Transaction{}
+	endpoint := fmt.Sprintf(addressTransactionsEndpoint, address)
+	lastSeen := ""
+
+	for {
+		pageEndpoint := endpoint
+		if lastSeen != "" {
+			pageEndpoint = fmt.Sprintf("%s/%s", endpoint, lastSeen)
+		}
+
+		body, err := client.RequestWithRetries(pageEndpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		page := []Transaction{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("could not unmarshal address transactions: %w", err)
+		}
+
+		transactions = append(transactions, page...)
+
+		if len(page) < pageSize {
+			break
+		}
+		lastSeen = page[len(page)-1].TxID
+	}
+
+	return transactions, nil
+}
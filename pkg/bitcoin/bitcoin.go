@@ -0,0 +1,174 @@
+// Handles Bitcoin on-chain package logic to fetch all data
+package bitcoin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Address struct {
+	Address      string  `json:"address"`
+	Key          int     `json:"key"`
+	Path         string  `json:"path"`
+	Balance      float64 `json:"balance"`
+	Transactions int     `json:"transactions"`
+}
+
+type Bitcoin struct {
+	Addresses    *[]Address
+	Transactions *[]Transaction
+}
+
+// Create a new Bitcoin object
+func New() *Bitcoin {
+	return &Bitcoin{}
+}
+
+// Create a new Wallet object computed from the Bitcoin ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
+// Compute the balance of an address from its transactions
+func balance(address string, transactions []Transaction) int64 {
+	var satoshis int64
+	for _, tx := range transactions {
+		for _, output := range tx.Vout {
+			if output.Address == address {
+				satoshis += output.Value
+			}
+		}
+		for _, input := range tx.Vin {
+			if input.Prevout.Address == address {
+				satoshis -= input.Prevout.Value
+			}
+		}
+	}
+
+	return satoshis
+}
+
+// Derive the addresses of every configured extended key until the gap limit and fetch their history
+// Chain 0 holds receiving addresses and chain 1 change addresses
+func scan(keys []string, gapLimit int) ([]Address, []Transaction, error) {
+	addresses := []Address{}
+	transactions := make(map[string]Transaction)
+
+	for i, encoded := range keys {
+		key, err := ParseExtendedKey(encoded)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse key #%d: %w", i, err)
+		}
+
+		for chain := uint32(0); chain <= 1; chain++ {
+			chainKey, err := key.Child(chain)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not derive chain %d of key #%d: %w", chain, i, err)
+			}
+
+			unused := 0
+			for index := uint32(0); unused < gapLimit; index++ {
+				child, err := chainKey.Child(index)
+				if err != nil {
+					log.Warnf("Skipping address %d/%d of key #%d: %v", chain, index, i, err)
+					continue
+				}
+
+				address, err := child.Address()
+				if err != nil {
+					return nil, nil, fmt.Errorf("could not encode address: %w", err)
+				}
+
+				history, err := GetAddressTransactions(address)
+				if err != nil {
+					return nil, nil, fmt.Errorf("could not get '%s' transactions: %w", address, err)
+				}
+
+				if len(history) == 0 {
+					unused++
+					continue
+				}
+				unused = 0
+
+				addresses = append(addresses, Address{
+					Address:      address,
+					Key:          i,
+					Path:         fmt.Sprintf("m/%d/%d", chain, index),
+					Balance:      toBTC(balance(address, history)),
+					Transactions: len(history),
+				})
+				for _, tx := range history {
+					transactions[tx.TxID] = tx
+				}
+			}
+		}
+
+		log.Infof("Found %d used addresses for key #%d", len(addresses), i)
+	}
+
+	list := []Transaction{}
+	for _, tx := range transactions {
+		list = append(list, tx)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Status.BlockHeight < list[j].Status.BlockHeight
+	})
+
+	return addresses, list, nil
+}
+
+// Write all fetched data to files
+func (b *Bitcoin) saveDataToFile() error {
+	if err := utils.WriteToFile("bitcoin_addresses", b.Addresses); err != nil {
+		log.Errorf("Could not write data to bitcoin_addresses: %v", err)
+	} else {
+		metrics.Synced(source, "bitcoin_addresses")
+	}
+
+	if err := utils.WriteToFile("bitcoin_transactions", b.Transactions); err != nil {
+		log.Errorf("Could not write data to bitcoin_transactions: %v", err)
+	} else {
+		metrics.Synced(source, "bitcoin_transactions")
+	}
+
+	if err := ledger.Save(source, b.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve the on-chain history of all configured extended public keys
+func (b *Bitcoin) ProcessBitcoinData(verbose bool) error {
+	log.Info("Starting process Bitcoin data...")
+
+	keys := viper.GetStringSlice("wallets.bitcoin.xpubs")
+	if len(keys) == 0 {
+		return errors.New("no extended public key configured under 'wallets.bitcoin.xpubs'")
+	}
+
+	// ADDRESSES AND TRANSACTIONS
+	log.Info("Scanning addresses...")
+	addresses, transactions, err := scan(keys, viper.GetInt("wallets.bitcoin.gapLimit"))
+	if err != nil {
+		return err
+	}
+
+	b.Addresses = &addresses
+	b.Transactions = &transactions
+
+	if verbose {
+		if err := utils.OutputResult(b.Addresses); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	return b.saveDataToFile()
+}
@@ -0,0 +1,206 @@
+// Handles extended public keys parsing and BIP32 public derivation
+package bitcoin
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Address script types, given by the extended key version (BIP44, BIP49, BIP84)
+const (
+	ScriptP2PKH      = "p2pkh"
+	ScriptP2SHP2WPKH = "p2sh-p2wpkh"
+	ScriptP2WPKH     = "p2wpkh"
+)
+
+type keyVersion struct {
+	script  string
+	testnet bool
+}
+
+var keyVersions = map[string]keyVersion{
+	"0488b21e": {script: ScriptP2PKH},                     // xpub
+	"049d7cb2": {script: ScriptP2SHP2WPKH},                // ypub
+	"04b24746": {script: ScriptP2WPKH},                    // zpub
+	"043587cf": {script: ScriptP2PKH, testnet: true},      // tpub
+	"044a5262": {script: ScriptP2SHP2WPKH, testnet: true}, // upub
+	"045f1cf6": {script: ScriptP2WPKH, testnet: true},     // vpub
+}
+
+// secp256k1 curve parameters
+var (
+	curveP, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	curveN, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	curveGx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	curveGy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	curveB     = big.NewInt(7)
+)
+
+// A secp256k1 point, nil coordinates being the point at infinity
+type point struct {
+	x *big.Int
+	y *big.Int
+}
+
+// Add two curve points
+func addPoints(p1 point, p2 point) point {
+	if p1.x == nil {
+		return p2
+	}
+	if p2.x == nil {
+		return p1
+	}
+
+	var slope *big.Int
+	if p1.x.Cmp(p2.x) == 0 {
+		sum := new(big.Int).Add(p1.y, p2.y)
+		if sum.Mod(sum, curveP).Sign() == 0 {
+			return point{}
+		}
+		// Doubling: 3x² / 2y
+		numerator := new(big.Int).Mul(p1.x, p1.x)
+		numerator.Mul(numerator, big.NewInt(3))
+		denominator := new(big.Int).Lsh(p1.y, 1)
+		slope = numerator.Mul(numerator, denominator.ModInverse(denominator, curveP))
+	} else {
+		numerator := new(big.Int).Sub(p2.y, p1.y)
+		denominator := new(big.Int).Sub(p2.x, p1.x)
+		denominator.Mod(denominator, curveP)
+		slope = numerator.Mul(numerator, denominator.ModInverse(denominator, curveP))
+	}
+	slope.Mod(slope, curveP)
+
+	x := new(big.Int).Mul(slope, slope)
+	x.Sub(x, p1.x).Sub(x, p2.x).Mod(x, curveP)
+
+	y := new(big.Int).Sub(p1.x, x)
+	y.Mul(y, slope).Sub(y, p1.y).Mod(y, curveP)
+
+	return point{x: x, y: y}
+}
+
+// Multiply the generator point by a scalar
+func multiplyGenerator(k *big.Int) point {
+	result := point{}
+	addend := point{x: curveGx, y: curveGy}
+
+	for i := 0; i < k.BitLen(); i++ {
+		if k.Bit(i) == 1 {
+			result = addPoints(result, addend)
+		}
+		addend = addPoints(addend, addend)
+	}
+
+	return result
+}
+
+// Decode a compressed public key into a curve point
+func decompress(key []byte) (point, error) {
+	if len(key) != 33 || (key[0] != 0x02 && key[0] != 0x03) {
+		return point{}, errors.New("invalid compressed public key")
+	}
+
+	x := new(big.Int).SetBytes(key[1:])
+
+	// y² = x³ + 7, p ≡ 3 (mod 4) so the root is (y²)^((p+1)/4)
+	ySquare := new(big.Int).Exp(x, big.NewInt(3), curveP)
+	ySquare.Add(ySquare, curveB).Mod(ySquare, curveP)
+	exponent := new(big.Int).Add(curveP, big.NewInt(1))
+	exponent.Rsh(exponent, 2)
+	y := new(big.Int).Exp(ySquare, exponent, curveP)
+
+	if new(big.Int).Exp(y, big.NewInt(2), curveP).Cmp(ySquare) != 0 {
+		return point{}, errors.New("public key is not on the curve")
+	}
+	if y.Bit(0) != uint(key[0]&1) {
+		y.Sub(curveP, y)
+	}
+
+	return point{x: x, y: y}, nil
+}
+
+// Encode a curve point as a compressed public key
+func compress(p point) []byte {
+	key := make([]byte, 33)
+	key[0] = 0x02 + byte(p.y.Bit(0))
+	p.x.FillBytes(key[1:])
+
+	return key
+}
+
+type ExtendedKey struct {
+	Script    string
+	Testnet   bool
+	PublicKey []byte
+	ChainCode []byte
+}
+
+// Parse a base58 encoded extended public key (xpub, ypub, zpub and their testnet counterparts)
+func ParseExtendedKey(encoded string) (*ExtendedKey, error) {
+	data, err := decodeBase58Check(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode extended key: %w", err)
+	}
+
+	if len(data) != 78 {
+		return nil, fmt.Errorf("invalid extended key length %d", len(data))
+	}
+
+	version, ok := keyVersions[hex.EncodeToString(data[:4])]
+	if !ok {
+		return nil, fmt.Errorf("unsupported extended key version '%x', only public keys are supported", data[:4])
+	}
+
+	if _, err := decompress(data[45:]); err != nil {
+		return nil, fmt.Errorf("invalid extended key: %w", err)
+	}
+
+	return &ExtendedKey{
+		Script:    version.script,
+		Testnet:   version.testnet,
+		PublicKey: data[45:],
+		ChainCode: data[13:45],
+	}, nil
+}
+
+// Derive a non-hardened child public key (BIP32 CKDpub)
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if index >= 0x80000000 {
+		return nil, errors.New("hardened derivation needs a private key")
+	}
+
+	data := make([]byte, 37)
+	copy(data, k.PublicKey)
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	mac := hmac.New(sha512.New, k.ChainCode)
+	mac.Write(data)
+	digest := mac.Sum(nil)
+
+	tweak := new(big.Int).SetBytes(digest[:32])
+	if tweak.Cmp(curveN) >= 0 {
+		return nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+
+	parent, err := decompress(k.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	child := addPoints(multiplyGenerator(tweak), parent)
+	if child.x == nil {
+		return nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+
+	return &ExtendedKey{
+		Script:    k.Script,
+		Testnet:   k.Testnet,
+		PublicKey: compress(child),
+		ChainCode: digest[32:],
+	}, nil
+}
@@ -0,0 +1,70 @@
+// Handles conversion of on-chain history to ledger transactions
+package bitcoin
+
+import (
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+)
+
+const (
+	source        = "bitcoin"
+	asset         = "BTC"
+	satoshiPerBTC = 100000000
+)
+
+// Convert satoshis to BTC
+func toBTC(satoshis int64) float64 {
+	return float64(satoshis) / satoshiPerBTC
+}
+
+// Convert on-chain transactions to ledger transactions
+// Each transaction is netted over every derived address, moves between them only costing the fee
+func (b *Bitcoin) toLedger() []ledger.Transaction {
+	own := make(map[string]bool)
+	for _, address := range *b.Addresses {
+		own[address.Address] = true
+	}
+
+	transactions := []ledger.Transaction{}
+	for _, tx := range *b.Transactions {
+		var received, spent int64
+		for _, output := range tx.Vout {
+			if own[output.Address] {
+				received += output.Value
+			}
+		}
+		for _, input := range tx.Vin {
+			if own[input.Prevout.Address] {
+				spent += input.Prevout.Value
+			}
+		}
+
+		entry := ledger.Transaction{
+			ID:     tx.TxID,
+			Source: source,
+			Time:   tx.Status.BlockTime * 1000,
+			Asset:  asset,
+			TxHash: tx.TxID,
+		}
+
+		sent := spent - received - tx.Fee
+		switch {
+		case spent == 0 || received > spent:
+			entry.Type = ledger.TypeDeposit
+			entry.Amount = toBTC(received - spent)
+		case sent > 0:
+			entry.Type = ledger.TypeWithdraw
+			entry.Amount = toBTC(sent)
+			entry.FeeAsset = asset
+			entry.FeeAmount = toBTC(tx.Fee)
+		default:
+			entry.Type = ledger.TypeFee
+			entry.Amount = toBTC(spent - received)
+		}
+
+		if entry.Amount > 0 {
+			transactions = append(transactions, entry)
+		}
+	}
+
+	return transactions
+}
@@ -0,0 +1,230 @@
+// Handles Bitpanda API endpoints logic
+package bitpanda
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	walletsEndpoint                = "/v1/wallets"
+	walletTransactionsEndpoint     = "/v1/wallets/transactions"
+	fiatWalletsEndpoint            = "/v1/fiatwallets"
+	fiatWalletTransactionsEndpoint = "/v1/fiatwallets/transactions"
+	tradesEndpoint                 = "/v1/trades"
+	pageSize                       = "100"
+)
+
+type Time struct {
+	DateISO8601 string `json:"date_iso8601"`
+	Unix        string `json:"unix"`
+}
+
+type Meta struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+// Request every page of a cursor paginated endpoint, returning the raw data items
+func getAllPages(endpoint string) ([]json.RawMessage, error) {
+	client := NewClient()
+	items := []json.RawMessage{}
+	cursor := ""
+
+	for {
+		params := map[string]string{
+			"page_size": pageSize,
+		}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		body, err := client.RequestWithRetries(endpoint, params)
+		if err != nil {
+			return nil, err
+		}
+
+		page := struct {
+			Data []json.RawMessage `json:"data"`
+			Meta Meta              `json:"meta"`
+		}{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("could not unmarshal page: %w", err)
+		}
+
+		items = append(items, page.Data...)
+
+		if len(page.Data) == 0 || page.Meta.NextCursor == "" {
+			break
+		}
+		cursor = page.Meta.NextCursor
+	}
+
+	return items, nil
+}
+
+// Decode raw data items into a typed slice
+func decodeItems(items []json.RawMessage, v interface{}) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+type Wallet struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		CryptocoinID     string `json:"cryptocoin_id"`
+		CryptocoinSymbol string `json:"cryptocoin_symbol"`
+		Balance          string `json:"balance"`
+		Name             string `json:"name"`
+	} `json:"attributes"`
+}
+
+// Get Bitpanda crypto wallets
+func GetWallets() (*[]Wallet, error) {
+	client := NewClient()
+	body, err := client.RequestWithRetries(walletsEndpoint, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("could not request wallets endpoint: %w", err)
+	}
+
+	wallets := struct {
+		Data []Wallet `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &wallets); err != nil {
+		return nil, fmt.Errorf("could not unmarshal wallets: %w", err)
+	}
+
+	return &wallets.Data, nil
+}
+
+type FiatWallet struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		FiatID     string `json:"fiat_id"`
+		FiatSymbol string `json:"fiat_symbol"`
+		Balance    string `json:"balance"`
+		Name       string `json:"name"`
+	} `json:"attributes"`
+}
+
+// Get Bitpanda fiat wallets
+func GetFiatWallets() (*[]FiatWallet, error) {
+	client := NewClient()
+	body, err := client.RequestWithRetries(fiatWalletsEndpoint, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("could not request fiat wallets endpoint: %w", err)
+	}
+
+	fiatWallets := struct {
+		Data []FiatWallet `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &fiatWallets); err != nil {
+		return nil, fmt.Errorf("could not unmarshal fiat wallets: %w", err)
+	}
+
+	return &fiatWallets.Data, nil
+}
+
+type FiatWalletTransaction struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		FiatWalletID string `json:"fiat_wallet_id"`
+		FiatID       string `json:"fiat_id"`
+		Amount       string `json:"amount"`
+		Fee          string `json:"fee"`
+		Type         string `json:"type"`
+		Status       string `json:"status"`
+		Time         Time   `json:"time"`
+	} `json:"attributes"`
+}
+
+// Get Bitpanda fiat wallets transactions (SEPA deposits, withdrawals...)
+func GetFiatWalletTransactions() (*[]FiatWalletTransaction, error) {
+	items, err := getAllPages(fiatWalletTransactionsEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not request fiat wallet transactions endpoint: %w", err)
+	}
+
+	fiatWalletTransactions := []FiatWalletTransaction{}
+	if err := decodeItems(items, &fiatWalletTransactions); err != nil {
+		return nil, fmt.Errorf("could not unmarshal fiat wallet transactions: %w", err)
+	}
+
+	return &fiatWalletTransactions, nil
+}
+
+type WalletTransaction struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		WalletID                string `json:"wallet_id"`
+		CryptocoinID            string `json:"cryptocoin_id"`
+		Amount                  string `json:"amount"`
+		Fee                     string `json:"fee"`
+		Type                    string `json:"type"`
+		InOrOut                 string `json:"in_or_out"`
+		Status                  string `json:"status"`
+		BlockchainTransactionID string `json:"blockchain_transaction_id"`
+		Time                    Time   `json:"time"`
+	} `json:"attributes"`
+}
+
+// Get Bitpanda crypto wallets transactions (deposits, withdrawals, rewards...)
+func GetWalletTransactions() (*[]WalletTransaction, error) {
+	items, err := getAllPages(walletTransactionsEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not request wallet transactions endpoint: %w", err)
+	}
+
+	walletTransactions := []WalletTransaction{}
+	if err := decodeItems(items, &walletTransactions); err != nil {
+		return nil, fmt.Errorf("could not unmarshal wallet transactions: %w", err)
+	}
+
+	return &walletTransactions, nil
+}
+
+type Trade struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Status           string `json:"status"`
+		Type             string `json:"type"`
+		CryptocoinID     string `json:"cryptocoin_id"`
+		FiatID           string `json:"fiat_id"`
+		AmountFiat       string `json:"amount_fiat"`
+		AmountCryptocoin string `json:"amount_cryptocoin"`
+		FiatToEURRate    string `json:"fiat_to_eur_rate"`
+		Price            string `json:"price"`
+		IsSavings        bool   `json:"is_savings"`
+		Time             Time   `json:"time"`
+	} `json:"attributes"`
+}
+
+// Get Bitpanda trades history, savings plans executions included
+func GetTrades() (*[]Trade, error) {
+	items, err := getAllPages(tradesEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not request trades endpoint: %w", err)
+	}
+
+	trades := []Trade{}
+	if err := decodeItems(items, &trades); err != nil {
+		return nil, fmt.Errorf("could not unmarshal trades: %w", err)
+	}
+
+	return &trades, nil
+}
+
+// Keep trades executed by a savings plan
+func FilterSavingsPlans(trades *[]Trade) *[]Trade {
+	savingsPlans := []Trade{}
+	for _, trade := range *trades {
+		if trade.Attributes.IsSavings {
+			savingsPlans = append(savingsPlans, trade)
+		}
+	}
+
+	return &savingsPlans
+}
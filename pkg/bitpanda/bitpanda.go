@@ -0,0 +1,146 @@
+// Handles Bitpanda package logic to fetch all data
+package bitpanda
+
+import (
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type Bitpanda struct {
+	Wallets                *[]Wallet
+	FiatWallets            *[]FiatWallet
+	FiatWalletTransactions *[]FiatWalletTransaction
+	WalletTransactions     *[]WalletTransaction
+	Trades                 *[]Trade
+	SavingsPlans           *[]Trade
+}
+
+// Create a new Bitpanda object
+func New() *Bitpanda {
+	return &Bitpanda{}
+}
+
+// Create a new Wallet object computed from the Bitpanda ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
+// Write all fetched data to files
+func (b *Bitpanda) saveDataToFile() error {
+	if err := utils.WriteToFile("bitpanda_wallets", b.Wallets); err != nil {
+		log.Errorf("Could not write data to bitpanda_wallets: %v", err)
+	} else {
+		metrics.Synced(source, "bitpanda_wallets")
+	}
+
+	if err := utils.WriteToFile("bitpanda_fiat_wallets", b.FiatWallets); err != nil {
+		log.Errorf("Could not write data to bitpanda_fiat_wallets: %v", err)
+	} else {
+		metrics.Synced(source, "bitpanda_fiat_wallets")
+	}
+
+	if err := utils.WriteToFile("bitpanda_fiat_wallet_transactions", b.FiatWalletTransactions); err != nil {
+		log.Errorf("Could not write data to bitpanda_fiat_wallet_transactions: %v", err)
+	} else {
+		metrics.Synced(source, "bitpanda_fiat_wallet_transactions")
+	}
+
+	if err := utils.WriteToFile("bitpanda_wallet_transactions", b.WalletTransactions); err != nil {
+		log.Errorf("Could not write data to bitpanda_wallet_transactions: %v", err)
+	} else {
+		metrics.Synced(source, "bitpanda_wallet_transactions")
+	}
+
+	if err := utils.WriteToFile("bitpanda_trades", b.Trades); err != nil {
+		log.Errorf("Could not write data to bitpanda_trades: %v", err)
+	} else {
+		metrics.Synced(source, "bitpanda_trades")
+	}
+
+	if err := utils.WriteToFile("bitpanda_savings_plans", b.SavingsPlans); err != nil {
+		log.Errorf("Could not write data to bitpanda_savings_plans: %v", err)
+	} else {
+		metrics.Synced(source, "bitpanda_savings_plans")
+	}
+
+	if err := ledger.Save(source, b.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve all account data from Bitpanda
+func (b *Bitpanda) ProcessBitpandaData(verbose bool) error {
+	log.Info("Starting process Bitpanda data...")
+
+	// CRYPTO WALLETS
+	log.Info("Fetching wallets data...")
+	wallets, err := GetWallets()
+	if err != nil {
+		return err
+	}
+
+	b.Wallets = wallets
+
+	// FIAT WALLETS
+	log.Info("Fetching fiat wallets data...")
+	fiatWallets, err := GetFiatWallets()
+	if err != nil {
+		return err
+	}
+
+	b.FiatWallets = fiatWallets
+
+	// FIAT WALLETS TRANSACTIONS
+	log.Info("Fetching fiat wallet transactions data...")
+	fiatWalletTransactions, err := GetFiatWalletTransactions()
+	if err != nil {
+		return err
+	}
+
+	b.FiatWalletTransactions = fiatWalletTransactions
+
+	if verbose {
+		if err := utils.OutputResult(b.FiatWalletTransactions); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// CRYPTO WALLETS TRANSACTIONS
+	log.Info("Fetching wallet transactions data...")
+	walletTransactions, err := GetWalletTransactions()
+	if err != nil {
+		return err
+	}
+
+	b.WalletTransactions = walletTransactions
+
+	if verbose {
+		if err := utils.OutputResult(b.WalletTransactions); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// TRADES AND SAVINGS PLANS
+	log.Info("Fetching trades data...")
+	trades, err := GetTrades()
+	if err != nil {
+		return err
+	}
+
+	b.Trades = trades
+	b.SavingsPlans = FilterSavingsPlans(trades)
+
+	if verbose {
+		if err := utils.OutputResult(b.Trades); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	return b.saveDataToFile()
+}
@@ -0,0 +1,114 @@
+// Handles HTTP requests logic
+package bitpanda
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	APIKey     string
+	RetryDelay time.Duration
+	MaxRetries int
+	MaxHistory int
+}
+
+// Create a new Client object
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: time.Second * viper.GetDuration("tracklet.timeout"),
+		},
+		BaseURL:    viper.GetString("exchanges.bitpanda.apiBaseURL"),
+		APIKey:     viper.GetString("exchanges.bitpanda.apiKey"),
+		RetryDelay: viper.GetDuration("tracklet.retryDelay"),
+		MaxRetries: viper.GetInt("tracklet.maxRetries"),
+		MaxHistory: viper.GetInt("tracklet.maxHistory"),
+	}
+}
+
+// Build http query string
+func (c *Client) buildQueryString(q url.Values, params map[string]string) url.Values {
+	if len(params) == 0 {
+		return nil
+	}
+	for elem := range params {
+		q.Add(elem, params[elem])
+	}
+
+	return q
+}
+
+// HTTP get request for a given Bitpanda API endpoint
+func (c *Client) request(endpoint string, parameters map[string]string) ([]byte, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", c.BaseURL, endpoint), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+
+	q := req.URL.Query()
+
+	queryString := c.buildQueryString(q, parameters)
+	if queryString != nil {
+		req.URL.RawQuery = queryString.Encode()
+	}
+
+	req.Header.Add("X-API-KEY", c.APIKey)
+
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non valid HTTP status code : %s", response.Status)
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading body: %w", err)
+	}
+
+	return body, nil
+}
+
+// Retry mechanism for HTTP requests
+func (c *Client) RequestWithRetries(endpoint string, parameters map[string]string) ([]byte, error) {
+	var body []byte
+	var err error
+	retries := 0
+
+	for {
+		metrics.Request(source)
+		body, err = c.request(endpoint, parameters)
+		if body != nil {
+			break
+		}
+
+		if retries == c.MaxRetries {
+			log.Warn("Max retries exceeded...")
+			break
+		}
+
+		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
+		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
+		time.Sleep(time.Second * c.RetryDelay)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not request '%s' endpoint: %w", endpoint, err)
+	}
+
+	return body, err
+}
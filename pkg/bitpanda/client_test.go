@@ -0,0 +1,100 @@
+package bitpanda
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// Serve pages of trades by cursor, checking requests carry the API key
+func tradesServer(t *testing.T, pages map[string]string) *int {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// Bitpanda requests are not signed, the API key being sent as is
+		if r.Header.Get("X-API-KEY") != "key" || r.URL.Query().Get("page_size") != pageSize {
+			t.Errorf("request %s is not authenticated", r.URL)
+		}
+
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+			page = `{"data": []}`
+		}
+		fmt.Fprint(w, page)
+	}))
+	t.Cleanup(server.Close)
+
+	viper.Set("exchanges.bitpanda.apiBaseURL", server.URL)
+	viper.Set("exchanges.bitpanda.apiKey", "key")
+	viper.Set("tracklet.maxRetries", 0)
+	t.Cleanup(func() {
+		for _, key := range []string{"exchanges.bitpanda.apiBaseURL", "exchanges.bitpanda.apiKey", "tracklet.maxRetries"} {
+			viper.Set(key, nil)
+		}
+	})
+
+	return &requests
+}
+
+func TestGetTradesPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		pages        map[string]string
+		wantRequests int
+		wantTrades   []string
+	}{
+		{
+			name:         "single page",
+			pages:        map[string]string{"": `{"data": [{"id": "t1"}, {"id": "t2"}], "meta": {}}`},
+			wantRequests: 1,
+			wantTrades:   []string{"t1", "t2"},
+		},
+		{
+			name: "cursor pages",
+			pages: map[string]string{
+				"":   `{"data": [{"id": "t1"}], "meta": {"next_cursor": "c1"}}`,
+				"c1": `{"data": [{"id": "t2"}], "meta": {"next_cursor": ""}}`,
+			},
+			wantRequests: 2,
+			wantTrades:   []string{"t1", "t2"},
+		},
+		{
+			// The last page may still give a cursor, an empty page ending the history
+			name: "empty last page",
+			pages: map[string]string{
+				"":   `{"data": [{"id": "t1"}], "meta": {"next_cursor": "c1"}}`,
+				"c1": `{"data": [], "meta": {"next_cursor": "c2"}}`,
+			},
+			wantRequests: 2,
+			wantTrades:   []string{"t1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := tradesServer(t, tt.pages)
+
+			trades, err := GetTrades()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", *requests, tt.wantRequests)
+			}
+
+			got := []string{}
+			for _, trade := range *trades {
+				got = append(got, trade.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantTrades) {
+				t.Errorf("got trades %v, want %v", got, tt.wantTrades)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Handles conversion of Bitpanda data to ledger transactions
+package bitpanda
+
+import (
+	"strconv"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const source = "bitpanda"
+
+// Parse a Bitpanda decimal string, defaulting to zero
+func parseFloat(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Errorf("Could not convert string to float: %v", err)
+		return 0
+	}
+
+	return f
+}
+
+// Convert a Bitpanda time to Unix milliseconds
+func parseTime(t Time) int64 {
+	seconds, err := strconv.ParseInt(t.Unix, 10, 64)
+	if err != nil {
+		log.Errorf("Could not parse time: %v", err)
+		return 0
+	}
+
+	return seconds * 1000
+}
+
+// Map Bitpanda internal ids to asset symbols
+func (b *Bitpanda) symbols() (map[string]string, map[string]string) {
+	cryptocoins := make(map[string]string)
+	if b.Wallets != nil {
+		for _, wallet := range *b.Wallets {
+			cryptocoins[wallet.Attributes.CryptocoinID] = wallet.Attributes.CryptocoinSymbol
+		}
+	}
+
+	fiats := make(map[string]string)
+	if b.FiatWallets != nil {
+		for _, fiatWallet := range *b.FiatWallets {
+			fiats[fiatWallet.Attributes.FiatID] = fiatWallet.Attributes.FiatSymbol
+		}
+	}
+
+	return cryptocoins, fiats
+}
+
+// Convert all fetched Bitpanda data to ledger transactions
+func (b *Bitpanda) toLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+	cryptocoins, fiats := b.symbols()
+
+	if b.Trades != nil {
+		for _, trade := range *b.Trades {
+			if trade.Attributes.Status != "finished" {
+				continue
+			}
+
+			txType := ledger.TypeBuy
+			if trade.Attributes.Type == "sell" {
+				txType = ledger.TypeSell
+			}
+
+			transactions = append(transactions, ledger.Transaction{
+				ID:          trade.ID,
+				Source:      source,
+				Type:        txType,
+				Time:        parseTime(trade.Attributes.Time),
+				Asset:       cryptocoins[trade.Attributes.CryptocoinID],
+				Amount:      parseFloat(trade.Attributes.AmountCryptocoin),
+				QuoteAsset:  fiats[trade.Attributes.FiatID],
+				QuoteAmount: parseFloat(trade.Attributes.AmountFiat),
+			})
+		}
+	}
+
+	if b.FiatWalletTransactions != nil {
+		for _, tx := range *b.FiatWalletTransactions {
+			if tx.Attributes.Status != "finished" {
+				continue
+			}
+
+			var txType ledger.Type
+			switch tx.Attributes.Type {
+			case "deposit":
+				txType = ledger.TypeDeposit
+			case "withdrawal":
+				txType = ledger.TypeWithdraw
+			default:
+				continue
+			}
+
+			fiat := fiats[tx.Attributes.FiatID]
+			transactions = append(transactions, ledger.Transaction{
+				ID:        tx.ID,
+				Source:    source,
+				Type:      txType,
+				Time:      parseTime(tx.Attributes.Time),
+				Asset:     fiat,
+				Amount:    parseFloat(tx.Attributes.Amount),
+				FeeAsset:  fiat,
+				FeeAmount: parseFloat(tx.Attributes.Fee),
+			})
+		}
+	}
+
+	if b.WalletTransactions != nil {
+		for _, tx := range *b.WalletTransactions {
+			if tx.Attributes.Status != "finished" {
+				continue
+			}
+
+			// Buys and sells are already covered by trades
+			var txType ledger.Type
+			switch {
+			case tx.Attributes.Type == "deposit":
+				txType = ledger.TypeDeposit
+			case tx.Attributes.Type == "withdrawal":
+				txType = ledger.TypeWithdraw
+			case tx.Attributes.Type == "transfer" && tx.Attributes.InOrOut == "incoming":
+				txType = ledger.TypeIncome
+			default:
+				continue
+			}
+
+			asset := cryptocoins[tx.Attributes.CryptocoinID]
+			transactions = append(transactions, ledger.Transaction{
+				ID:        tx.ID,
+				Source:    source,
+				Type:      txType,
+				Time:      parseTime(tx.Attributes.Time),
+				Asset:     asset,
+				Amount:    parseFloat(tx.Attributes.Amount),
+				FeeAsset:  asset,
+				FeeAmount: parseFloat(tx.Attributes.Fee),
+				TxHash:    tx.Attributes.BlockchainTransactionID,
+			})
+		}
+	}
+
+	return transactions
+}
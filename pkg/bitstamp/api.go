@@ -0,0 +1,51 @@
+// Handles Bitstamp API endpoints logic
+package bitstamp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	userTransactionsEndpoint = "/api/v2/user_transactions/"
+	pageSize                 = 1000
+)
+
+// Bitstamp user transactions carry one field per currency involved ("btc", "eur", "btc_eur"...)
+// so they are kept as raw maps
+type UserTransaction map[string]interface{}
+
+// Get Bitstamp user transactions (deposits, withdrawals, trades, staking...)
+func GetUserTransactions() (*[]UserTransaction, error) {
+	client := NewClient()
+	userTransactions := []UserTransaction{}
+	since := time.Now().AddDate(0, 0, -client.MaxHistory).Unix()
+
+	for offset := 0; ; offset += pageSize {
+		params := map[string]string{
+			"offset":          fmt.Sprint(offset),
+			"limit":           fmt.Sprint(pageSize),
+			"sort":            "asc",
+			"since_timestamp": fmt.Sprint(since),
+		}
+
+		body, err := client.RequestWithRetries(userTransactionsEndpoint, params)
+		if err != nil {
+			return nil, fmt.Errorf("could not request user transactions endpoint: %w", err)
+		}
+
+		userTransactionsPage := []UserTransaction{}
+		if err := json.Unmarshal(body, &userTransactionsPage); err != nil {
+			return nil, fmt.Errorf("could not unmarshal user transactions: %w", err)
+		}
+
+		userTransactions = append(userTransactions, userTransactionsPage...)
+
+		if len(userTransactionsPage) < pageSize {
+			break
+		}
+	}
+
+	return &userTransactions, nil
+}
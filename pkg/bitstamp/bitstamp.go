@@ -0,0 +1,62 @@
+// Handles Bitstamp package logic to fetch all data
+package bitstamp
+
+import (
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type Bitstamp struct {
+	UserTransactions *[]UserTransaction
+}
+
+// Create a new Bitstamp object
+func New() *Bitstamp {
+	return &Bitstamp{}
+}
+
+// Create a new Wallet object computed from the Bitstamp ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
+// Write all fetched data to files
+func (b *Bitstamp) saveDataToFile() error {
+	if err := utils.WriteToFile("bitstamp_user_transactions", b.UserTransactions); err != nil {
+		log.Errorf("Could not write data to bitstamp_user_transactions: %v", err)
+	} else {
+		metrics.Synced(source, "bitstamp_user_transactions")
+	}
+
+	if err := ledger.Save(source, b.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve all account data from Bitstamp
+func (b *Bitstamp) ProcessBitstampData(verbose bool) error {
+	log.Info("Starting process Bitstamp data...")
+
+	// USER TRANSACTIONS
+	log.Info("Fetching user transactions data...")
+	userTransactions, err := GetUserTransactions()
+	if err != nil {
+		return err
+	}
+
+	b.UserTransactions = userTransactions
+
+	if verbose {
+		if err := utils.OutputResult(b.UserTransactions); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	return b.saveDataToFile()
+}
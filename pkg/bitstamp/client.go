@@ -0,0 +1,153 @@
+// Handles HTTP requests logic
+package bitstamp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	APIKey     string
+	SecretKey  string
+	RetryDelay time.Duration
+	MaxRetries int
+	MaxHistory int
+}
+
+// Create a new Client object
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: time.Second * viper.GetDuration("tracklet.timeout"),
+		},
+		BaseURL:    viper.GetString("exchanges.bitstamp.apiBaseURL"),
+		APIKey:     viper.GetString("exchanges.bitstamp.apiKey"),
+		SecretKey:  viper.GetString("exchanges.bitstamp.secretKey"),
+		RetryDelay: viper.GetDuration("tracklet.retryDelay"),
+		MaxRetries: viper.GetInt("tracklet.maxRetries"),
+		MaxHistory: viper.GetInt("tracklet.maxHistory"),
+	}
+}
+
+// Generate a random UUID used as request nonce
+func (c *Client) generateNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate nonce: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// Generate a HMAC signed message for authorizing API requests
+func (c *Client) generateSignature(message string) (string, error) {
+	mac := hmac.New(sha256.New, []byte(c.SecretKey))
+	_, err := mac.Write([]byte(message))
+	if err != nil {
+		return "", fmt.Errorf("could not generate hmac: %w", err)
+	}
+	return strings.ToUpper(hex.EncodeToString(mac.Sum(nil))), nil
+}
+
+// HTTP post request for a given Bitstamp API endpoint
+func (c *Client) request(endpoint string, parameters map[string]string) ([]byte, error) {
+	values := url.Values{}
+	for elem := range parameters {
+		values.Add(elem, parameters[elem])
+	}
+	payload := values.Encode()
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.BaseURL, endpoint), strings.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+
+	contentType := ""
+	if payload != "" {
+		contentType = "application/x-www-form-urlencoded"
+		req.Header.Add("Content-Type", contentType)
+	}
+
+	nonce, err := c.generateNonce()
+	if err != nil {
+		return nil, err
+	}
+	timestamp := fmt.Sprint(time.Now().UnixMilli())
+
+	message := fmt.Sprintf("BITSTAMP %s%s%s%s%s%s%s%sv2%s",
+		c.APIKey, req.Method, req.URL.Host, req.URL.Path, req.URL.RawQuery, contentType, nonce, timestamp, payload)
+	signature, err := c.generateSignature(message)
+	if err != nil {
+		return nil, fmt.Errorf("error while generating signature: %w", err)
+	}
+
+	req.Header.Add("X-Auth", fmt.Sprintf("BITSTAMP %s", c.APIKey))
+	req.Header.Add("X-Auth-Signature", signature)
+	req.Header.Add("X-Auth-Nonce", nonce)
+	req.Header.Add("X-Auth-Timestamp", timestamp)
+	req.Header.Add("X-Auth-Version", "v2")
+
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non valid HTTP status code : %s", response.Status)
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading body: %w", err)
+	}
+
+	return body, nil
+}
+
+// Retry mechanism for HTTP requests
+func (c *Client) RequestWithRetries(endpoint string, parameters map[string]string) ([]byte, error) {
+	var body []byte
+	var err error
+	retries := 0
+
+	for {
+		metrics.Request(source)
+		body, err = c.request(endpoint, parameters)
+		if body != nil {
+			break
+		}
+
+		if retries == c.MaxRetries {
+			log.Warn("Max retries exceeded...")
+			break
+		}
+
+		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
+		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
+		time.Sleep(time.Second * c.RetryDelay)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not request '%s' endpoint: %w", endpoint, err)
+	}
+
+	return body, err
+}
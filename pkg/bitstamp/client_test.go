@@ -0,0 +1,105 @@
+package bitstamp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// HMAC-SHA256 test case 2 of RFC 4231, Bitstamp signatures being upper case hex encoded
+func TestGenerateSignature(t *testing.T) {
+	client := &Client{SecretKey: "Jefe"}
+
+	got, err := client.generateSignature("what do ya want for nothing?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "5BDCC146BF60754E6A042426089575C75A003F089D2739839DEC58B964EC3843"
+	if got != want {
+		t.Errorf("generateSignature() = %s, want %s", got, want)
+	}
+}
+
+// Serve a number of user transactions by offset, checking requests are signed
+func userTransactionsServer(t *testing.T, count int) *int {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// Version 2 authentication signs every part of the request after the API key
+		body, _ := ioutil.ReadAll(r.Body)
+		message := "BITSTAMP key" + r.Method + r.Host + r.URL.Path + r.URL.RawQuery + r.Header.Get("Content-Type") +
+			r.Header.Get("X-Auth-Nonce") + r.Header.Get("X-Auth-Timestamp") + "v2" + string(body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(message))
+		if r.Header.Get("X-Auth") != "BITSTAMP key" || r.Header.Get("X-Auth-Version") != "v2" || r.Header.Get("X-Auth-Signature") != strings.ToUpper(hex.EncodeToString(mac.Sum(nil))) {
+			t.Errorf("request %s is not signed", r.URL)
+		}
+
+		values, _ := url.ParseQuery(string(body))
+		offset, _ := strconv.Atoi(values.Get("offset"))
+		limit, _ := strconv.Atoi(values.Get("limit"))
+
+		page := []UserTransaction{}
+		for i := offset; i < offset+limit && i < count; i++ {
+			page = append(page, UserTransaction{"id": float64(i)})
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+
+	viper.Set("exchanges.bitstamp.apiBaseURL", server.URL)
+	viper.Set("exchanges.bitstamp.apiKey", "key")
+	viper.Set("exchanges.bitstamp.secretKey", "secret")
+	viper.Set("tracklet.maxRetries", 0)
+	t.Cleanup(func() {
+		for _, key := range []string{"exchanges.bitstamp.apiBaseURL", "exchanges.bitstamp.apiKey", "exchanges.bitstamp.secretKey", "tracklet.maxRetries"} {
+			viper.Set(key, nil)
+		}
+	})
+
+	return &requests
+}
+
+func TestGetUserTransactionsPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        int
+		wantRequests int
+	}{
+		{name: "empty", count: 0, wantRequests: 1},
+		{name: "single page", count: 10, wantRequests: 1},
+		{name: "last partial page", count: pageSize + 1, wantRequests: 2},
+		// A full last page is followed by an empty one
+		{name: "exact pages", count: 2 * pageSize, wantRequests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := userTransactionsServer(t, tt.count)
+
+			userTransactions, err := GetUserTransactions()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", *requests, tt.wantRequests)
+			}
+			if len(*userTransactions) != tt.count {
+				t.Errorf("got %d transactions, want %d", len(*userTransactions), tt.count)
+			}
+		})
+	}
+}
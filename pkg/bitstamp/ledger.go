@@ -0,0 +1,173 @@
+// Handles conversion of Bitstamp data to ledger transactions
+package bitstamp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const source = "bitstamp"
+
+const (
+	typeDeposit       = "0"
+	typeWithdrawal    = "1"
+	typeMarketTrade   = "2"
+	typeStakingReward = "27"
+	typeReferral      = "32"
+)
+
+// Fields of a user transaction that are not currency amounts
+var metadataFields = map[string]bool{
+	"id":       true,
+	"datetime": true,
+	"type":     true,
+	"fee":      true,
+	"order_id": true,
+}
+
+// Parse a Bitstamp number, sent either as a JSON string or number
+func parseFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		if v == "" {
+			return 0
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			log.Errorf("Could not convert string to float: %v", err)
+			return 0
+		}
+		return f
+	default:
+		return 0
+	}
+}
+
+// Parse a Bitstamp UTC datetime to Unix milliseconds
+func parseTime(value interface{}) int64 {
+	t, err := time.Parse("2006-01-02 15:04:05.999999", fmt.Sprint(value))
+	if err != nil {
+		log.Errorf("Could not parse time: %v", err)
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+// Return the non zero currency amounts of a user transaction and its trading pair if any
+func (u UserTransaction) amounts() (map[string]float64, string) {
+	amounts := make(map[string]float64)
+	pair := ""
+
+	for field, value := range u {
+		if metadataFields[field] {
+			continue
+		}
+
+		if strings.Contains(field, "_") {
+			pair = field
+			continue
+		}
+
+		if amount := parseFloat(value); amount != 0 {
+			amounts[strings.ToUpper(field)] = amount
+		}
+	}
+
+	return amounts, pair
+}
+
+// Convert a market trade to a ledger transaction
+func tradeToTransaction(id string, u UserTransaction) (ledger.Transaction, bool) {
+	amounts, pair := u.amounts()
+	assets := strings.SplitN(strings.ToUpper(pair), "_", 2)
+	if len(assets) != 2 {
+		return ledger.Transaction{}, false
+	}
+	baseAsset, quoteAsset := assets[0], assets[1]
+
+	txType := ledger.TypeBuy
+	if amounts[baseAsset] < 0 {
+		txType = ledger.TypeSell
+	}
+
+	return ledger.Transaction{
+		ID:          id,
+		Source:      source,
+		Type:        txType,
+		Time:        parseTime(u["datetime"]),
+		Asset:       baseAsset,
+		Amount:      math.Abs(amounts[baseAsset]),
+		QuoteAsset:  quoteAsset,
+		QuoteAmount: math.Abs(amounts[quoteAsset]),
+		FeeAsset:    quoteAsset,
+		FeeAmount:   parseFloat(u["fee"]),
+	}, true
+}
+
+// Convert a single currency movement to a ledger transaction
+func movementToTransaction(id string, u UserTransaction, txType ledger.Type) (ledger.Transaction, bool) {
+	amounts, _ := u.amounts()
+	if len(amounts) != 1 {
+		return ledger.Transaction{}, false
+	}
+
+	transaction := ledger.Transaction{
+		ID:     id,
+		Source: source,
+		Type:   txType,
+		Time:   parseTime(u["datetime"]),
+	}
+
+	for asset, amount := range amounts {
+		transaction.Asset = asset
+		transaction.Amount = math.Abs(amount)
+
+		if fee := parseFloat(u["fee"]); fee > 0 {
+			transaction.FeeAsset = asset
+			transaction.FeeAmount = fee
+		}
+	}
+
+	return transaction, true
+}
+
+// Convert all fetched Bitstamp data to ledger transactions
+func (b *Bitstamp) toLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+	if b.UserTransactions == nil {
+		return transactions
+	}
+
+	for _, u := range *b.UserTransactions {
+		id := fmt.Sprintf("%.0f", parseFloat(u["id"]))
+
+		var transaction ledger.Transaction
+		var ok bool
+
+		switch fmt.Sprint(u["type"]) {
+		case typeDeposit:
+			transaction, ok = movementToTransaction(id, u, ledger.TypeDeposit)
+		case typeWithdrawal:
+			transaction, ok = movementToTransaction(id, u, ledger.TypeWithdraw)
+		case typeMarketTrade:
+			transaction, ok = tradeToTransaction(id, u)
+		case typeStakingReward, typeReferral:
+			transaction, ok = movementToTransaction(id, u, ledger.TypeIncome)
+		}
+
+		if ok {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	return transactions
+}
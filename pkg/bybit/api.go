@@ -0,0 +1,181 @@
+// Handles Bybit API endpoints logic
+package bybit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+)
+
+const (
+	instrumentsEndpoint    = "/v5/market/instruments-info"
+	executionsEndpoint     = "/v5/execution/list"
+	transactionLogEndpoint = "/v5/account/transaction-log"
+	maxTimeRange           = 7 // Days, history endpoints reject wider ranges
+)
+
+// Product categories of a unified account
+var categories = []string{"spot", "linear", "inverse", "option"}
+
+type Instruments struct {
+	List []struct {
+		Symbol    string `json:"symbol"`
+		BaseCoin  string `json:"baseCoin"`
+		QuoteCoin string `json:"quoteCoin"`
+	} `json:"list"`
+}
+
+// Get spot trading pairs available on Bybit
+func GetInstruments() (*Instruments, error) {
+	client := NewClient()
+	params := map[string]string{
+		"category": "spot",
+	}
+
+	body, err := client.RequestWithRetries(instrumentsEndpoint, params)
+	if err != nil {
+		return nil, fmt.Errorf("could not request instruments endpoint: %w", err)
+	}
+
+	instruments := Instruments{}
+	if err := json.Unmarshal(body, &instruments); err != nil {
+		return nil, fmt.Errorf("could not unmarshal instruments: %w", err)
+	}
+
+	return &instruments, nil
+}
+
+// Request every cursor page of an endpoint over the configured history, returning the raw list items
+func getAllPages(endpoint string, baseParams map[string]string) ([]json.RawMessage, error) {
+	client := NewClient()
+	items := []json.RawMessage{}
+
+	for _, dateRange := range utils.GetDateRanges(client.MaxHistory, maxTimeRange) {
+		cursor := ""
+
+		for {
+			params := map[string]string{
+				"startTime": fmt.Sprintf("%d", dateRange.StartDate),
+				"endTime":   fmt.Sprintf("%d", dateRange.EndDate),
+			}
+			for key, value := range baseParams {
+				params[key] = value
+			}
+			if cursor != "" {
+				params["cursor"] = cursor
+			}
+
+			body, err := client.RequestWithRetries(endpoint, params)
+			if err != nil {
+				return nil, err
+			}
+
+			page := struct {
+				List           []json.RawMessage `json:"list"`
+				NextPageCursor string            `json:"nextPageCursor"`
+			}{}
+			if err := json.Unmarshal(body, &page); err != nil {
+				return nil, fmt.Errorf("could not unmarshal page: %w", err)
+			}
+
+			items = append(items, page.List...)
+
+			if len(page.List) == 0 || page.NextPageCursor == "" {
+				break
+			}
+			cursor = page.NextPageCursor
+		}
+	}
+
+	return items, nil
+}
+
+// Decode raw list items into a typed slice
+func decodeItems(items []json.RawMessage, v interface{}) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+type Execution struct {
+	Symbol      string `json:"symbol"`
+	Category    string `json:"category"`
+	OrderID     string `json:"orderId"`
+	ExecID      string `json:"execId"`
+	Side        string `json:"side"`
+	ExecPrice   string `json:"execPrice"`
+	ExecQty     string `json:"execQty"`
+	ExecValue   string `json:"execValue"`
+	ExecFee     string `json:"execFee"`
+	FeeCurrency string `json:"feeCurrency"`
+	ExecType    string `json:"execType"`
+	ExecTime    string `json:"execTime"`
+}
+
+// Get unified account executions for every product category
+func GetExecutions() (*[]Execution, error) {
+	executions := []Execution{}
+
+	for _, category := range categories {
+		items, err := getAllPages(executionsEndpoint, map[string]string{
+			"category": category,
+			"limit":    "100",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not request executions endpoint: %w", err)
+		}
+
+		categoryExecutions := []Execution{}
+		if err := decodeItems(items, &categoryExecutions); err != nil {
+			return nil, fmt.Errorf("could not unmarshal executions: %w", err)
+		}
+
+		for i := range categoryExecutions {
+			categoryExecutions[i].Category = category
+		}
+		executions = append(executions, categoryExecutions...)
+	}
+
+	return &executions, nil
+}
+
+type TransactionLog struct {
+	ID              string `json:"id"`
+	Symbol          string `json:"symbol"`
+	Category        string `json:"category"`
+	Side            string `json:"side"`
+	TransactionTime string `json:"transactionTime"`
+	Type            string `json:"type"`
+	Qty             string `json:"qty"`
+	Currency        string `json:"currency"`
+	TradePrice      string `json:"tradePrice"`
+	Funding         string `json:"funding"`
+	Fee             string `json:"fee"`
+	CashFlow        string `json:"cashFlow"`
+	Change          string `json:"change"`
+	CashBalance     string `json:"cashBalance"`
+	TradeID         string `json:"tradeId"`
+	OrderID         string `json:"orderId"`
+}
+
+// Get unified account transaction log (settlements, funding, transfers, interests...)
+func GetTransactionLog() (*[]TransactionLog, error) {
+	items, err := getAllPages(transactionLogEndpoint, map[string]string{
+		"accountType": "UNIFIED",
+		"limit":       "50",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not request transaction log endpoint: %w", err)
+	}
+
+	transactionLog := []TransactionLog{}
+	if err := decodeItems(items, &transactionLog); err != nil {
+		return nil, fmt.Errorf("could not unmarshal transaction log: %w", err)
+	}
+
+	return &transactionLog, nil
+}
@@ -0,0 +1,100 @@
+// Handles Bybit package logic to fetch all data
+package bybit
+
+import (
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type Bybit struct {
+	Instruments    *Instruments
+	Executions     *[]Execution
+	TransactionLog *[]TransactionLog
+}
+
+// Create a new Bybit object
+func New() *Bybit {
+	return &Bybit{}
+}
+
+// Create a new Wallet object computed from the Bybit ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
+// Write all fetched data to files
+func (b *Bybit) saveDataToFile() error {
+	if err := utils.WriteToFile("bybit_instruments", b.Instruments); err != nil {
+		log.Errorf("Could not write data to bybit_instruments: %v", err)
+	} else {
+		metrics.Synced(source, "bybit_instruments")
+	}
+
+	if err := utils.WriteToFile("bybit_executions", b.Executions); err != nil {
+		log.Errorf("Could not write data to bybit_executions: %v", err)
+	} else {
+		metrics.Synced(source, "bybit_executions")
+	}
+
+	if err := utils.WriteToFile("bybit_transaction_log", b.TransactionLog); err != nil {
+		log.Errorf("Could not write data to bybit_transaction_log: %v", err)
+	} else {
+		metrics.Synced(source, "bybit_transaction_log")
+	}
+
+	if err := ledger.Save(source, b.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve all unified account data from Bybit
+func (b *Bybit) ProcessBybitData(verbose bool) error {
+	log.Info("Starting process Bybit data...")
+
+	// EXCHANGE'S SPOT INSTRUMENTS
+	log.Info("Fetching instruments data...")
+	instruments, err := GetInstruments()
+	if err != nil {
+		return err
+	}
+
+	b.Instruments = instruments
+
+	// EXECUTIONS
+	log.Info("Fetching executions data...")
+	executions, err := GetExecutions()
+	if err != nil {
+		return err
+	}
+
+	b.Executions = executions
+
+	if verbose {
+		if err := utils.OutputResult(b.Executions); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// TRANSACTION LOG
+	log.Info("Fetching transaction log data...")
+	transactionLog, err := GetTransactionLog()
+	if err != nil {
+		return err
+	}
+
+	b.TransactionLog = transactionLog
+
+	if verbose {
+		if err := utils.OutputResult(b.TransactionLog); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	return b.saveDataToFile()
+}
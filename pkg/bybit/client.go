@@ -0,0 +1,156 @@
+// Handles HTTP requests logic
+package bybit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const recvWindow = "10000"
+
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	APIKey     string
+	SecretKey  string
+	RetryDelay time.Duration
+	MaxRetries int
+	MaxHistory int
+}
+
+type apiResponse struct {
+	RetCode int             `json:"retCode"`
+	RetMsg  string          `json:"retMsg"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// Create a new Client object
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: time.Second * viper.GetDuration("tracklet.timeout"),
+		},
+		BaseURL:    viper.GetString("exchanges.bybit.apiBaseURL"),
+		APIKey:     viper.GetString("exchanges.bybit.apiKey"),
+		SecretKey:  viper.GetString("exchanges.bybit.secretKey"),
+		RetryDelay: viper.GetDuration("tracklet.retryDelay"),
+		MaxRetries: viper.GetInt("tracklet.maxRetries"),
+		MaxHistory: viper.GetInt("tracklet.maxHistory"),
+	}
+}
+
+// Generate a HMAC signed message for authorizing API requests
+func (c *Client) generateSignature(message string) (string, error) {
+	mac := hmac.New(sha256.New, []byte(c.SecretKey))
+	_, err := mac.Write([]byte(message))
+	if err != nil {
+		return "", fmt.Errorf("could not generate hmac: %w", err)
+	}
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Build http query string
+func (c *Client) buildQueryString(q url.Values, params map[string]string) url.Values {
+	if len(params) == 0 {
+		return nil
+	}
+	for elem := range params {
+		q.Add(elem, params[elem])
+	}
+
+	return q
+}
+
+// HTTP get request for a given Bybit API endpoint, returning its result
+func (c *Client) request(endpoint string, parameters map[string]string) ([]byte, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", c.BaseURL, endpoint), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+
+	q := req.URL.Query()
+
+	queryString := c.buildQueryString(q, parameters)
+	if queryString != nil {
+		req.URL.RawQuery = queryString.Encode()
+	}
+
+	timestamp := fmt.Sprint(time.Now().UnixMilli())
+	signature, err := c.generateSignature(fmt.Sprintf("%s%s%s%s", timestamp, c.APIKey, recvWindow, req.URL.RawQuery))
+	if err != nil {
+		return nil, fmt.Errorf("error while generating signature: %w", err)
+	}
+
+	req.Header.Add("X-BAPI-API-KEY", c.APIKey)
+	req.Header.Add("X-BAPI-SIGN", signature)
+	req.Header.Add("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Add("X-BAPI-RECV-WINDOW", recvWindow)
+
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non valid HTTP status code : %s", response.Status)
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading body: %w", err)
+	}
+
+	bybitResponse := apiResponse{}
+	if err := json.Unmarshal(body, &bybitResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response: %w", err)
+	}
+
+	if bybitResponse.RetCode != 0 {
+		return nil, fmt.Errorf("api error %d: %s", bybitResponse.RetCode, bybitResponse.RetMsg)
+	}
+
+	return bybitResponse.Result, nil
+}
+
+// Retry mechanism for HTTP requests
+func (c *Client) RequestWithRetries(endpoint string, parameters map[string]string) ([]byte, error) {
+	var body []byte
+	var err error
+	retries := 0
+
+	for {
+		metrics.Request(source)
+		body, err = c.request(endpoint, parameters)
+		if body != nil {
+			break
+		}
+
+		if retries == c.MaxRetries {
+			log.Warn("Max retries exceeded...")
+			break
+		}
+
+		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
+		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
+		time.Sleep(time.Second * c.RetryDelay)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not request '%s' endpoint: %w", endpoint, err)
+	}
+
+	return body, err
+}
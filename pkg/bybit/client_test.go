@@ -0,0 +1,124 @@
+package bybit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// HMAC-SHA256 test case 2 of RFC 4231, Bybit signatures being hex encoded
+func TestGenerateSignature(t *testing.T) {
+	client := &Client{SecretKey: "Jefe"}
+
+	got, err := client.generateSignature("what do ya want for nothing?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("generateSignature() = %s, want %s", got, want)
+	}
+}
+
+// Serve pages of a transaction log by cursor, checking requests are signed
+func transactionLogServer(t *testing.T, pages map[string]string) *int {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// GET requests sign timestamp, API key, receive window and query string
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(r.Header.Get("X-BAPI-TIMESTAMP") + "key" + r.Header.Get("X-BAPI-RECV-WINDOW") + r.URL.RawQuery))
+		if r.Header.Get("X-BAPI-API-KEY") != "key" || r.Header.Get("X-BAPI-SIGN") != hex.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("request %s is not signed", r.URL)
+		}
+
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+			page = `{"list": []}`
+		}
+		_ = json.NewEncoder(w).Encode(apiResponse{Result: json.RawMessage(page)})
+	}))
+	t.Cleanup(server.Close)
+
+	viper.Set("exchanges.bybit.apiBaseURL", server.URL)
+	viper.Set("exchanges.bybit.apiKey", "key")
+	viper.Set("exchanges.bybit.secretKey", "secret")
+	viper.Set("tracklet.maxRetries", 0)
+	// A single time range is requested
+	viper.Set("tracklet.maxHistory", maxTimeRange/2)
+	t.Cleanup(func() {
+		for _, key := range []string{"exchanges.bybit.apiBaseURL", "exchanges.bybit.apiKey", "exchanges.bybit.secretKey", "tracklet.maxRetries", "tracklet.maxHistory"} {
+			viper.Set(key, nil)
+		}
+	})
+
+	return &requests
+}
+
+func TestGetTransactionLogPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		pages        map[string]string
+		wantRequests int
+		wantItems    int
+	}{
+		{
+			name:         "single page",
+			pages:        map[string]string{"": `{"list": [{"id": "1"}, {"id": "2"}], "nextPageCursor": ""}`},
+			wantRequests: 1,
+			wantItems:    2,
+		},
+		{
+			name: "cursor pages",
+			pages: map[string]string{
+				"":   `{"list": [{"id": "1"}, {"id": "2"}], "nextPageCursor": "c1"}`,
+				"c1": `{"list": [{"id": "3"}], "nextPageCursor": ""}`,
+			},
+			wantRequests: 2,
+			wantItems:    3,
+		},
+		{
+			// The last page may still give a cursor, an empty page ending the history
+			name: "empty last page",
+			pages: map[string]string{
+				"":   `{"list": [{"id": "1"}], "nextPageCursor": "c1"}`,
+				"c1": `{"list": [], "nextPageCursor": "c2"}`,
+			},
+			wantRequests: 2,
+			wantItems:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := transactionLogServer(t, tt.pages)
+
+			transactionLog, err := GetTransactionLog()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", *requests, tt.wantRequests)
+			}
+			if len(*transactionLog) != tt.wantItems {
+				t.Errorf("got %d items, want %d", len(*transactionLog), tt.wantItems)
+			}
+			for i, item := range *transactionLog {
+				if item.ID != fmt.Sprint(i+1) {
+					t.Errorf("item %d has id %s, want %d", i, item.ID, i+1)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Handles conversion of Bybit data to ledger transactions
+package bybit
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const source = "bybit"
+
+// Parse a Bybit decimal string, defaulting to zero
+func parseFloat(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Errorf("Could not convert string to float: %v", err)
+		return 0
+	}
+
+	return f
+}
+
+// Parse a Bybit Unix milliseconds string
+func parseTime(value string) int64 {
+	t, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Errorf("Could not parse time: %v", err)
+		return 0
+	}
+
+	return t
+}
+
+// Convert a spot execution to a ledger transaction
+func executionToTransaction(execution Execution, instruments map[string][2]string) (ledger.Transaction, bool) {
+	assets, ok := instruments[execution.Symbol]
+	if !ok {
+		log.Warnf("Unknown Bybit symbol '%s'", execution.Symbol)
+		return ledger.Transaction{}, false
+	}
+	baseAsset, quoteAsset := assets[0], assets[1]
+
+	txType := ledger.TypeBuy
+	feeAsset := baseAsset
+	if execution.Side == "Sell" {
+		txType = ledger.TypeSell
+		feeAsset = quoteAsset
+	}
+	if execution.FeeCurrency != "" {
+		feeAsset = execution.FeeCurrency
+	}
+
+	return ledger.Transaction{
+		ID:          execution.ExecID,
+		Source:      source,
+		Type:        txType,
+		Time:        parseTime(execution.ExecTime),
+		Asset:       baseAsset,
+		Amount:      parseFloat(execution.ExecQty),
+		QuoteAsset:  quoteAsset,
+		QuoteAmount: parseFloat(execution.ExecValue),
+		FeeAsset:    feeAsset,
+		FeeAmount:   parseFloat(execution.ExecFee),
+	}, true
+}
+
+// Convert a transaction log entry to a ledger transaction
+// Spot trades are skipped as they are already covered by executions
+// Only the Unified account is tracked, transfers from and to the Funding account, where on-chain deposits
+// and withdrawals land, count as its deposits and withdrawals
+func transactionLogToTransaction(entry TransactionLog) (ledger.Transaction, bool) {
+	change := parseFloat(entry.Change)
+	if change == 0 {
+		return ledger.Transaction{}, false
+	}
+
+	transaction := ledger.Transaction{
+		ID:     entry.ID,
+		Source: source,
+		Time:   parseTime(entry.TransactionTime),
+		Asset:  entry.Currency,
+		Amount: math.Abs(change),
+	}
+
+	switch entry.Type {
+	case "TRANSFER_IN":
+		transaction.Type = ledger.TypeDeposit
+	case "TRANSFER_OUT":
+		transaction.Type = ledger.TypeWithdraw
+	case "INTEREST", "BONUS", "FEE_REFUND", "AIRDRP":
+		transaction.Type = ledger.TypeIncome
+	case "TRADE", "SETTLEMENT", "DELIVERY", "LIQUIDATION":
+		// Derivatives do not move the underlying asset, only their realized pnl and fees matter
+		if entry.Category == "spot" {
+			return transaction, false
+		}
+		transaction.Type = ledger.TypeIncome
+		if change < 0 {
+			transaction.Type = ledger.TypeFee
+		}
+	default:
+		return transaction, false
+	}
+
+	return transaction, true
+}
+
+// Convert all fetched Bybit data to ledger transactions
+func (b *Bybit) toLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+
+	instruments := make(map[string][2]string)
+	if b.Instruments != nil {
+		for _, instrument := range b.Instruments.List {
+			instruments[instrument.Symbol] = [2]string{instrument.BaseCoin, instrument.QuoteCoin}
+		}
+	}
+
+	if b.Executions != nil {
+		for _, execution := range *b.Executions {
+			if execution.Category != "spot" || execution.ExecType != "Trade" {
+				continue
+			}
+
+			if transaction, ok := executionToTransaction(execution, instruments); ok {
+				transactions = append(transactions, transaction)
+			}
+		}
+	}
+
+	if b.TransactionLog != nil {
+		for _, entry := range *b.TransactionLog {
+			if transaction, ok := transactionLogToTransaction(entry); ok {
+				transactions = append(transactions, transaction)
+			}
+		}
+	}
+
+	return transactions
+}
@@ -0,0 +1,263 @@
+// Handles Coinbase API endpoints logic
+package coinbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	accountsEndpoint     = "/api/v3/brokerage/accounts"
+	fillsEndpoint        = "/api/v3/brokerage/orders/historical/fills"
+	transactionsEndpoint = "/v2/accounts/%s/transactions"
+)
+
+type Balance struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+type Account struct {
+	UUID             string  `json:"uuid"`
+	Name             string  `json:"name"`
+	Currency         string  `json:"currency"`
+	AvailableBalance Balance `json:"available_balance"`
+	Hold             Balance `json:"hold"`
+	Type             string  `json:"type"`
+}
+
+type Accounts struct {
+	Accounts []Account `json:"accounts"`
+	HasNext  bool      `json:"has_next"`
+	Cursor   string    `json:"cursor"`
+}
+
+// Get Coinbase accounts
+func GetAccounts() (*Accounts, error) {
+	client := NewClient()
+	accounts := Accounts{}
+	cursor := ""
+
+	for {
+		params := map[string]string{
+			"limit": "250",
+		}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		body, err := client.RequestWithRetries(accountsEndpoint, params)
+		if err != nil {
+			return nil, fmt.Errorf("could not request accounts endpoint: %w", err)
+		}
+
+		accountsPage := Accounts{}
+		if err := json.Unmarshal(body, &accountsPage); err != nil {
+			return nil, fmt.Errorf("could not unmarshal accounts: %w", err)
+		}
+
+		accounts.Accounts = append(accounts.Accounts, accountsPage.Accounts...)
+
+		if !accountsPage.HasNext || accountsPage.Cursor == "" {
+			break
+		}
+		cursor = accountsPage.Cursor
+	}
+
+	return &accounts, nil
+}
+
+type Fill struct {
+	EntryID     string `json:"entry_id"`
+	TradeID     string `json:"trade_id"`
+	OrderID     string `json:"order_id"`
+	TradeTime   string `json:"trade_time"`
+	TradeType   string `json:"trade_type"`
+	Price       string `json:"price"`
+	Size        string `json:"size"`
+	Commission  string `json:"commission"`
+	ProductID   string `json:"product_id"`
+	SizeInQuote bool   `json:"size_in_quote"`
+	Side        string `json:"side"`
+}
+
+type Fills struct {
+	Fills  []Fill `json:"fills"`
+	Cursor string `json:"cursor"`
+}
+
+// Get Coinbase Advanced Trade fills history
+func GetFills() (*[]Fill, error) {
+	client := NewClient()
+	fills := []Fill{}
+	cursor := ""
+	startTime := time.Now().AddDate(0, 0, -client.MaxHistory).UTC().Format(time.RFC3339)
+
+	for {
+		params := map[string]string{
+			"start_sequence_timestamp": startTime,
+			"limit":                    "1000",
+		}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		body, err := client.RequestWithRetries(fillsEndpoint, params)
+		if err != nil {
+			return nil, fmt.Errorf("could not request fills endpoint: %w", err)
+		}
+
+		fillsPage := Fills{}
+		if err := json.Unmarshal(body, &fillsPage); err != nil {
+			return nil, fmt.Errorf("could not unmarshal fills: %w", err)
+		}
+
+		fills = append(fills, fillsPage.Fills...)
+
+		if len(fillsPage.Fills) == 0 || fillsPage.Cursor == "" {
+			break
+		}
+		cursor = fillsPage.Cursor
+	}
+
+	return &fills, nil
+}
+
+type Money struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type Transaction struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Status       string `json:"status"`
+	Amount       Money  `json:"amount"`
+	NativeAmount Money  `json:"native_amount"`
+	CreatedAt    string `json:"created_at"`
+	Network      struct {
+		Hash           string `json:"hash"`
+		TransactionFee Money  `json:"transaction_fee"`
+	} `json:"network"`
+}
+
+type Transactions struct {
+	Pagination struct {
+		NextURI string `json:"next_uri"`
+	} `json:"pagination"`
+	Data []Transaction `json:"data"`
+}
+
+// Get completed transactions of every Coinbase account, newest first
+func GetTransactions(accounts *Accounts) (*[]Transaction, error) {
+	client := NewClient()
+	transactions := []Transaction{}
+	oldest := time.Now().AddDate(0, 0, -client.MaxHistory)
+
+	for _, account := range accounts.Accounts {
+		startingAfter := ""
+
+		for {
+			params := map[string]string{
+				"limit": "100",
+			}
+			if startingAfter != "" {
+				params["starting_after"] = startingAfter
+			}
+
+			body, err := client.RequestWithRetries(fmt.Sprintf(transactionsEndpoint, account.UUID), params)
+			if err != nil {
+				return nil, fmt.Errorf("could not request transactions endpoint: %w", err)
+			}
+
+			transactionsPage := Transactions{}
+			if err := json.Unmarshal(body, &transactionsPage); err != nil {
+				return nil, fmt.Errorf("could not unmarshal transactions: %w", err)
+			}
+
+			reachedOldest := false
+			for _, tx := range transactionsPage.Data {
+				createdAt, err := time.Parse(time.RFC3339, tx.CreatedAt)
+				if err == nil && createdAt.Before(oldest) {
+					reachedOldest = true
+					break
+				}
+
+				if tx.Status == "completed" {
+					transactions = append(transactions, tx)
+				}
+			}
+
+			startingAfter = nextStartingAfter(transactionsPage.Pagination.NextURI)
+			if reachedOldest || startingAfter == "" {
+				break
+			}
+		}
+	}
+
+	return &transactions, nil
+}
+
+// Extract the pagination cursor from a Coinbase v2 next page URI
+func nextStartingAfter(nextURI string) string {
+	if nextURI == "" {
+		return ""
+	}
+
+	u, err := url.Parse(nextURI)
+	if err != nil {
+		return ""
+	}
+
+	return u.Query().Get("starting_after")
+}
+
+// Signed amount of a transaction, negative values being outflows
+func (t Transaction) amount() float64 {
+	amount, err := strconv.ParseFloat(t.Amount.Amount, 64)
+	if err != nil {
+		return 0
+	}
+
+	return amount
+}
+
+// Keep fiat deposits and incoming crypto sends
+func FilterDeposits(transactions *[]Transaction) *[]Transaction {
+	deposits := []Transaction{}
+	for _, tx := range *transactions {
+		if tx.Type == "fiat_deposit" || (tx.Type == "send" && tx.amount() > 0) {
+			deposits = append(deposits, tx)
+		}
+	}
+
+	return &deposits
+}
+
+// Keep fiat withdrawals and outgoing crypto sends
+func FilterWithdrawals(transactions *[]Transaction) *[]Transaction {
+	withdrawals := []Transaction{}
+	for _, tx := range *transactions {
+		if tx.Type == "fiat_withdrawal" || (tx.Type == "send" && tx.amount() < 0) {
+			withdrawals = append(withdrawals, tx)
+		}
+	}
+
+	return &withdrawals
+}
+
+// Keep staking, inflation and interest rewards
+func FilterRewards(transactions *[]Transaction) *[]Transaction {
+	rewards := []Transaction{}
+	for _, tx := range *transactions {
+		switch tx.Type {
+		case "staking_reward", "inflation_reward", "interest":
+			rewards = append(rewards, tx)
+		}
+	}
+
+	return &rewards
+}
@@ -0,0 +1,254 @@
+// Handles HTTP requests logic
+package coinbase
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const apiVersion = "2022-04-01"
+
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	APIKey     string
+	SecretKey  string
+	RetryDelay time.Duration
+	MaxRetries int
+	MaxHistory int
+}
+
+// Create a new Client object
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: time.Second * viper.GetDuration("tracklet.timeout"),
+		},
+		BaseURL:    viper.GetString("exchanges.coinbase.apiBaseURL"),
+		APIKey:     viper.GetString("exchanges.coinbase.apiKey"),
+		SecretKey:  strings.ReplaceAll(viper.GetString("exchanges.coinbase.secretKey"), `\n`, "\n"),
+		RetryDelay: viper.GetDuration("tracklet.retryDelay"),
+		MaxRetries: viper.GetInt("tracklet.maxRetries"),
+		MaxHistory: viper.GetInt("tracklet.maxHistory"),
+	}
+}
+
+// Coinbase Developer Platform keys come with an EC private key instead of a HMAC secret
+func (c *Client) isCDPKey() bool {
+	return strings.Contains(c.SecretKey, "PRIVATE KEY")
+}
+
+// Generate a HMAC signature for authorizing legacy API key requests
+func (c *Client) generateSignature(message string) (string, error) {
+	mac := hmac.New(sha256.New, []byte(c.SecretKey))
+	_, err := mac.Write([]byte(message))
+	if err != nil {
+		return "", fmt.Errorf("could not generate hmac: %w", err)
+	}
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Parse the EC private key of a Coinbase Developer Platform API key
+func (c *Client) parsePrivateKey() (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(c.SecretKey))
+	if block == nil {
+		return nil, errors.New("could not decode PEM private key")
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key: %w", err)
+	}
+
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an EC key")
+	}
+
+	return ecKey, nil
+}
+
+// Generate a ES256 signed JWT for authorizing Coinbase Developer Platform API key requests
+func (c *Client) generateJWT(method string, host string, path string) (string, error) {
+	key, err := c.parsePrivateKey()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	now := time.Now().Unix()
+	header, err := json.Marshal(map[string]string{
+		"alg":   "ES256",
+		"typ":   "JWT",
+		"kid":   c.APIKey,
+		"nonce": hex.EncodeToString(nonce),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not marshal jwt header: %w", err)
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"sub": c.APIKey,
+		"iss": "cdp",
+		"nbf": now,
+		"exp": now + 120,
+		"uri": fmt.Sprintf("%s %s%s", method, host, path),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not marshal jwt claims: %w", err)
+	}
+
+	signingInput := fmt.Sprintf("%s.%s",
+		base64.RawURLEncoding.EncodeToString(header),
+		base64.RawURLEncoding.EncodeToString(claims),
+	)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("could not sign jwt: %w", err)
+	}
+
+	// ES256 signatures are the fixed size concatenation of r and s
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return fmt.Sprintf("%s.%s", signingInput, base64.RawURLEncoding.EncodeToString(signature)), nil
+}
+
+// Build http query string
+func (c *Client) buildQueryString(q url.Values, params map[string]string) url.Values {
+	if len(params) == 0 {
+		return nil
+	}
+	for elem := range params {
+		q.Add(elem, params[elem])
+	}
+
+	return q
+}
+
+// Add authentication headers to a request
+func (c *Client) authenticate(req *http.Request) error {
+	if c.isCDPKey() {
+		token, err := c.generateJWT(req.Method, req.URL.Host, req.URL.Path)
+		if err != nil {
+			return fmt.Errorf("error while generating jwt: %w", err)
+		}
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+		return nil
+	}
+
+	// v2 endpoints sign the query string along with the path, Advanced Trade ones only the path
+	requestPath := req.URL.Path
+	if strings.HasPrefix(requestPath, "/v2/") && req.URL.RawQuery != "" {
+		requestPath = fmt.Sprintf("%s?%s", requestPath, req.URL.RawQuery)
+	}
+
+	timestamp := fmt.Sprint(time.Now().Unix())
+	signature, err := c.generateSignature(fmt.Sprintf("%s%s%s", timestamp, req.Method, requestPath))
+	if err != nil {
+		return fmt.Errorf("error while generating signature: %w", err)
+	}
+
+	req.Header.Add("CB-ACCESS-KEY", c.APIKey)
+	req.Header.Add("CB-ACCESS-SIGN", signature)
+	req.Header.Add("CB-ACCESS-TIMESTAMP", timestamp)
+
+	return nil
+}
+
+// HTTP get request for a given Coinbase API endpoint
+func (c *Client) request(endpoint string, parameters map[string]string) ([]byte, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", c.BaseURL, endpoint), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+
+	q := req.URL.Query()
+
+	queryString := c.buildQueryString(q, parameters)
+	if queryString != nil {
+		req.URL.RawQuery = queryString.Encode()
+	}
+
+	if err := c.authenticate(req); err != nil {
+		return nil, err
+	}
+	req.Header.Add("CB-VERSION", apiVersion)
+
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non valid HTTP status code : %s", response.Status)
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading body: %w", err)
+	}
+
+	return body, nil
+}
+
+// Retry mechanism for HTTP requests
+func (c *Client) RequestWithRetries(endpoint string, parameters map[string]string) ([]byte, error) {
+	var body []byte
+	var err error
+	retries := 0
+
+	for {
+		metrics.Request(source)
+		body, err = c.request(endpoint, parameters)
+		if body != nil {
+			break
+		}
+
+		if retries == c.MaxRetries {
+			log.Warn("Max retries exceeded...")
+			break
+		}
+
+		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
+		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
+		time.Sleep(time.Second * c.RetryDelay)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not request '%s' endpoint: %w", endpoint, err)
+	}
+
+	return body, err
+}
@@ -0,0 +1,215 @@
+package coinbase
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Point the client at a test server
+func setTestServer(t *testing.T, handler http.HandlerFunc, secretKey string) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	viper.Set("exchanges.coinbase.apiBaseURL", server.URL)
+	viper.Set("exchanges.coinbase.apiKey", "key")
+	viper.Set("exchanges.coinbase.secretKey", secretKey)
+	viper.Set("tracklet.maxRetries", 0)
+	viper.Set("tracklet.maxHistory", 365)
+	t.Cleanup(func() {
+		for _, key := range []string{"exchanges.coinbase.apiBaseURL", "exchanges.coinbase.apiKey", "exchanges.coinbase.secretKey", "tracklet.maxRetries", "tracklet.maxHistory"} {
+			viper.Set(key, nil)
+		}
+	})
+}
+
+// HMAC-SHA256 test case 2 of RFC 4231, Coinbase signatures being hex encoded
+func TestGenerateSignature(t *testing.T) {
+	client := &Client{SecretKey: "Jefe"}
+
+	got, err := client.generateSignature("what do ya want for nothing?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("generateSignature() = %s, want %s", got, want)
+	}
+}
+
+// Legacy keys sign timestamp, method and request path, the query string only for v2 endpoints
+func TestAuthenticateLegacyKey(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		signed   string
+	}{
+		{endpoint: "/v2/accounts/a1/transactions", signed: "/v2/accounts/a1/transactions?limit=100"},
+		{endpoint: "/api/v3/brokerage/accounts", signed: "/api/v3/brokerage/accounts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			setTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mac := hmac.New(sha256.New, []byte("secret"))
+				mac.Write([]byte(r.Header.Get("CB-ACCESS-TIMESTAMP") + "GET" + tt.signed))
+				if r.Header.Get("CB-ACCESS-KEY") != "key" || r.Header.Get("CB-ACCESS-SIGN") != hex.EncodeToString(mac.Sum(nil)) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				fmt.Fprint(w, "{}")
+			}, "secret")
+
+			if _, err := NewClient().RequestWithRetries(tt.endpoint, map[string]string{"limit": "100"}); err != nil {
+				t.Errorf("request was not signed as expected: %v", err)
+			}
+		})
+	}
+}
+
+// Developer Platform keys sign an ES256 JWT naming the request method, host and path
+func TestAuthenticateCDPKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretKey := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+
+	setTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		if len(parts) != 3 {
+			t.Errorf("invalid JWT %q", r.Header.Get("Authorization"))
+			return
+		}
+
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if len(signature) != 64 || !ecdsa.Verify(&key.PublicKey, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+			t.Error("JWT signature does not verify")
+		}
+
+		header, claims := map[string]string{}, map[string]interface{}{}
+		data, _ := base64.RawURLEncoding.DecodeString(parts[0])
+		_ = json.Unmarshal(data, &header)
+		data, _ = base64.RawURLEncoding.DecodeString(parts[1])
+		_ = json.Unmarshal(data, &claims)
+
+		if header["alg"] != "ES256" || header["kid"] != "key" {
+			t.Errorf("unexpected JWT header %v", header)
+		}
+		if want := fmt.Sprintf("GET %s%s", r.Host, r.URL.Path); claims["uri"] != want || claims["sub"] != "key" {
+			t.Errorf("unexpected JWT claims %v, want uri %q", claims, want)
+		}
+		fmt.Fprint(w, "{}")
+	}, secretKey)
+
+	if _, err := NewClient().RequestWithRetries(accountsEndpoint, map[string]string{"limit": "250"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAccountsPagination(t *testing.T) {
+	requests := 0
+	setTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"accounts": [{"uuid": "a1"}], "has_next": true, "cursor": "c1"}`)
+		case "c1":
+			fmt.Fprint(w, `{"accounts": [{"uuid": "a2"}], "has_next": false, "cursor": "c2"}`)
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}, "secret")
+
+	accounts, err := GetAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || len(accounts.Accounts) != 2 {
+		t.Errorf("got %d accounts in %d requests, want 2 in 2", len(accounts.Accounts), requests)
+	}
+}
+
+func TestGetFillsPagination(t *testing.T) {
+	requests := 0
+	setTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"fills": [{"entry_id": "f1"}, {"entry_id": "f2"}], "cursor": "c1"}`)
+		case "c1":
+			// The last page may still give a cursor, an empty page ending the history
+			fmt.Fprint(w, `{"fills": [], "cursor": "c2"}`)
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}, "secret")
+
+	fills, err := GetFills()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || len(*fills) != 2 {
+		t.Errorf("got %d fills in %d requests, want 2 in 2", len(*fills), requests)
+	}
+}
+
+func TestGetTransactionsPagination(t *testing.T) {
+	recent := time.Now().AddDate(0, 0, -1).UTC().Format(time.RFC3339)
+	old := time.Now().AddDate(-2, 0, 0).UTC().Format(time.RFC3339)
+
+	requests := map[string]int{}
+	setTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		page := fmt.Sprintf("%s?%s", r.URL.Path, r.URL.Query().Get("starting_after"))
+
+		switch page {
+		case "/v2/accounts/a1/transactions?":
+			fmt.Fprintf(w, `{"pagination": {"next_uri": "%s?limit=100&starting_after=t2"}, "data": [{"id": "t1", "status": "completed", "created_at": "%s"}, {"id": "t2", "status": "pending", "created_at": "%s"}]}`, r.URL.Path, recent, recent)
+		case "/v2/accounts/a1/transactions?t2":
+			fmt.Fprintf(w, `{"pagination": {"next_uri": null}, "data": [{"id": "t3", "status": "completed", "created_at": "%s"}]}`, recent)
+		case "/v2/accounts/a2/transactions?":
+			// History older than the maximum is not requested
+			fmt.Fprintf(w, `{"pagination": {"next_uri": "%s?limit=100&starting_after=t5"}, "data": [{"id": "t4", "status": "completed", "created_at": "%s"}, {"id": "t5", "status": "completed", "created_at": "%s"}]}`, r.URL.Path, recent, old)
+		default:
+			t.Errorf("unexpected page %q", page)
+		}
+	}, "secret")
+
+	transactions, err := GetTransactions(&Accounts{Accounts: []Account{{UUID: "a1"}, {UUID: "a2"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	for _, tx := range *transactions {
+		ids = append(ids, tx.ID)
+	}
+	if strings.Join(ids, ",") != "t1,t3,t4" {
+		t.Errorf("got transactions %v, want t1, t3 and t4", ids)
+	}
+	if requests["/v2/accounts/a1/transactions"] != 2 || requests["/v2/accounts/a2/transactions"] != 1 {
+		t.Errorf("got requests %v, want 2 for a1 and 1 for a2", requests)
+	}
+}
@@ -0,0 +1,122 @@
+// Handles Coinbase package logic to fetch all data
+package coinbase
+
+import (
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type Coinbase struct {
+	Accounts        *Accounts
+	Fills           *[]Fill
+	DepositHistory  *[]Transaction
+	WithdrawHistory *[]Transaction
+	RewardHistory   *[]Transaction
+}
+
+// Create a new Coinbase object
+func New() *Coinbase {
+	return &Coinbase{}
+}
+
+// Create a new Wallet object computed from the Coinbase ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
+// Write all fetched data to files
+func (c *Coinbase) saveDataToFile() error {
+	if err := utils.WriteToFile("coinbase_accounts", c.Accounts); err != nil {
+		log.Errorf("Could not write data to coinbase_accounts: %v", err)
+	} else {
+		metrics.Synced(source, "coinbase_accounts")
+	}
+
+	if err := utils.WriteToFile("coinbase_fills", c.Fills); err != nil {
+		log.Errorf("Could not write data to coinbase_fills: %v", err)
+	} else {
+		metrics.Synced(source, "coinbase_fills")
+	}
+
+	if err := utils.WriteToFile("coinbase_deposit_history", c.DepositHistory); err != nil {
+		log.Errorf("Could not write data to coinbase_deposit_history: %v", err)
+	} else {
+		metrics.Synced(source, "coinbase_deposit_history")
+	}
+
+	if err := utils.WriteToFile("coinbase_withdraw_history", c.WithdrawHistory); err != nil {
+		log.Errorf("Could not write data to coinbase_withdraw_history: %v", err)
+	} else {
+		metrics.Synced(source, "coinbase_withdraw_history")
+	}
+
+	if err := utils.WriteToFile("coinbase_reward_history", c.RewardHistory); err != nil {
+		log.Errorf("Could not write data to coinbase_reward_history: %v", err)
+	} else {
+		metrics.Synced(source, "coinbase_reward_history")
+	}
+
+	if err := ledger.Save(source, c.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve all account data from Coinbase
+func (c *Coinbase) ProcessCoinbaseData(verbose bool) error {
+	log.Info("Starting process Coinbase data...")
+
+	// EXCHANGE'S ACCOUNTS
+	log.Info("Fetching accounts data...")
+	accounts, err := GetAccounts()
+	if err != nil {
+		return err
+	}
+
+	c.Accounts = accounts
+
+	if verbose {
+		if err := utils.OutputResult(c.Accounts); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// FILLS HISTORY
+	log.Info("Fetching fills history data...")
+	fills, err := GetFills()
+	if err != nil {
+		return err
+	}
+
+	c.Fills = fills
+
+	if verbose {
+		if err := utils.OutputResult(c.Fills); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// ACCOUNTS TRANSACTIONS
+	log.Info("Fetching accounts transactions data...")
+	transactions, err := GetTransactions(c.Accounts)
+	if err != nil {
+		return err
+	}
+
+	c.DepositHistory = FilterDeposits(transactions)
+	c.WithdrawHistory = FilterWithdrawals(transactions)
+	c.RewardHistory = FilterRewards(transactions)
+
+	if verbose {
+		if err := utils.OutputResult(transactions); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	return c.saveDataToFile()
+}
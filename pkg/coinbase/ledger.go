@@ -0,0 +1,130 @@
+// Handles conversion of Coinbase data to ledger transactions
+package coinbase
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const source = "coinbase"
+
+// Parse a Coinbase decimal string, defaulting to zero
+func parseFloat(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Errorf("Could not convert string to float: %v", err)
+		return 0
+	}
+
+	return f
+}
+
+// Parse a Coinbase RFC3339 date to Unix milliseconds
+func parseTime(value string) int64 {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Errorf("Could not parse time: %v", err)
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+// Convert an Advanced Trade fill to a ledger transaction
+func fillToTransaction(fill Fill) ledger.Transaction {
+	assets := strings.SplitN(fill.ProductID, "-", 2)
+	baseAsset, quoteAsset := assets[0], ""
+	if len(assets) == 2 {
+		quoteAsset = assets[1]
+	}
+
+	price := parseFloat(fill.Price)
+	size := parseFloat(fill.Size)
+	baseAmount, quoteAmount := size, size*price
+	if fill.SizeInQuote && price != 0 {
+		baseAmount, quoteAmount = size/price, size
+	}
+
+	txType := ledger.TypeBuy
+	if fill.Side == "SELL" {
+		txType = ledger.TypeSell
+	}
+
+	return ledger.Transaction{
+		ID:          fill.EntryID,
+		Source:      source,
+		Type:        txType,
+		Time:        parseTime(fill.TradeTime),
+		Asset:       baseAsset,
+		Amount:      baseAmount,
+		QuoteAsset:  quoteAsset,
+		QuoteAmount: quoteAmount,
+		FeeAsset:    quoteAsset,
+		FeeAmount:   parseFloat(fill.Commission),
+	}
+}
+
+// Convert a v2 account transaction to a ledger transaction
+func transactionToTransaction(tx Transaction, txType ledger.Type) ledger.Transaction {
+	amount := math.Abs(tx.amount())
+	fee := parseFloat(tx.Network.TransactionFee.Amount)
+
+	transaction := ledger.Transaction{
+		ID:     tx.ID,
+		Source: source,
+		Type:   txType,
+		Time:   parseTime(tx.CreatedAt),
+		Asset:  tx.Amount.Currency,
+		Amount: amount,
+		TxHash: tx.Network.Hash,
+	}
+
+	// Outgoing sends amount includes the network fee
+	if txType == ledger.TypeWithdraw && fee > 0 {
+		transaction.Amount = amount - fee
+		transaction.FeeAsset = tx.Network.TransactionFee.Currency
+		transaction.FeeAmount = fee
+	}
+
+	return transaction
+}
+
+// Convert all fetched Coinbase data to ledger transactions
+func (c *Coinbase) toLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+
+	if c.Fills != nil {
+		for _, fill := range *c.Fills {
+			transactions = append(transactions, fillToTransaction(fill))
+		}
+	}
+
+	if c.DepositHistory != nil {
+		for _, tx := range *c.DepositHistory {
+			transactions = append(transactions, transactionToTransaction(tx, ledger.TypeDeposit))
+		}
+	}
+
+	if c.WithdrawHistory != nil {
+		for _, tx := range *c.WithdrawHistory {
+			transactions = append(transactions, transactionToTransaction(tx, ledger.TypeWithdraw))
+		}
+	}
+
+	if c.RewardHistory != nil {
+		for _, tx := range *c.RewardHistory {
+			transactions = append(transactions, transactionToTransaction(tx, ledger.TypeIncome))
+		}
+	}
+
+	return transactions
+}
@@ -0,0 +1,160 @@
+// Handles cron-style schedules
+package daemon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule of a job, as the minute, hour, day of month, month and day of week fields of a crontab line
+type Schedule struct {
+	spec     string
+	minutes  map[int]bool
+	hours    map[int]bool
+	days     map[int]bool
+	months   map[int]bool
+	weekdays map[int]bool
+	// Days of month and of week match either one when both are restricted, as cron does
+	// A field starting with `*`, such as `*/2`, does not count as restricted, both having to match then
+	anyDay     bool
+	anyWeekday bool
+}
+
+var scheduleMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// Parse a cron-style schedule, such as `*/15 * * * *`, `0 8-20/4 * * 1-5` or `@hourly`
+func ParseSchedule(spec string) (*Schedule, error) {
+	expanded := strings.TrimSpace(spec)
+	if macro, ok := scheduleMacros[expanded]; ok {
+		expanded = macro
+	}
+
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule '%s', expected 5 fields", spec)
+	}
+
+	s := &Schedule{spec: spec, anyDay: strings.HasPrefix(fields[2], "*"), anyWeekday: strings.HasPrefix(fields[4], "*")}
+	bounds := []struct {
+		field    *map[int]bool
+		min, max int
+	}{
+		{&s.minutes, 0, 59},
+		{&s.hours, 0, 23},
+		{&s.days, 1, 31},
+		{&s.months, 1, 12},
+		{&s.weekdays, 0, 7},
+	}
+	for i, b := range bounds {
+		values, err := parseField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule '%s': %w", spec, err)
+		}
+		*b.field = values
+	}
+
+	// Sunday is either 0 or 7
+	if s.weekdays[7] {
+		s.weekdays[0] = true
+	}
+
+	return s, nil
+}
+
+// Parse a comma separated list of `*`, values or ranges, each with an optional `/step`
+func parseField(field string, min int, max int) (map[int]bool, error) {
+	values := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step in '%s'", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		start, end := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			from, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid range '%s'", rangePart)
+			}
+			to, err := strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid range '%s'", rangePart)
+			}
+			start, end = from, to
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value '%s'", rangePart)
+			}
+			start, end = value, value
+			// A single value with a step runs from it up to the maximum
+			if step > 1 {
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("'%s' out of range %d-%d", part, min, max)
+		}
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+// Check if a time matches the schedule, to the minute
+func (s *Schedule) matches(t time.Time) bool {
+	if !s.minutes[t.Minute()] || !s.hours[t.Hour()] || !s.months[int(t.Month())] {
+		return false
+	}
+
+	day, weekday := s.days[t.Day()], s.weekdays[int(t.Weekday())]
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+
+	return day || weekday
+}
+
+// Return the first time matching the schedule after a given time, or the zero time when none does within 5 years
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !s.months[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.hours[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.matches(t):
+			return t
+		default:
+			t = t.Add(time.Minute)
+		}
+	}
+
+	return time.Time{}
+}
+
+// Return the schedule as configured
+func (s *Schedule) String() string {
+	return s.spec
+}
@@ -0,0 +1,193 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+		"@yearly",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2024-01-01 is a Monday
+	after := time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want []time.Time
+	}{
+		{
+			spec: "* * * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 1, 10, 8, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 10, 9, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "*/15 * * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "5/20 * * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 1, 10, 25, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 11, 5, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 8-20/4 * * 1-5",
+			want: []time.Time{
+				time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "30 9 * * 1,3,5",
+			want: []time.Time{
+				time.Date(2024, 1, 3, 9, 30, 0, 0, time.UTC),
+				time.Date(2024, 1, 5, 9, 30, 0, 0, time.UTC),
+				time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 * * 7",
+			want: []time.Time{
+				time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 * * 0",
+			want: []time.Time{
+				time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@hourly",
+			want: []time.Time{
+				time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@daily",
+			want: []time.Time{
+				time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@weekly",
+			want: []time.Time{
+				time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@monthly",
+			want: []time.Time{
+				time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Day 31 is skipped by months without it
+			spec: "0 12 31 * *",
+			want: []time.Time{
+				time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Both days restricted match either the 15th or Fridays
+			spec: "0 0 15 * 5",
+			want: []time.Time{
+				time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// A day of month starting with `*` does not widen days to either one, odd days being Fridays
+			spec: "0 0 */2 * 5",
+			want: []time.Time{
+				time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 9, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Days of month and of week both starting with `*` must both match, odd days on Sundays, Wednesdays or Saturdays
+			spec: "0 0 */2 * */3",
+			want: []time.Time{
+				time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 1 6 *",
+			want: []time.Time{
+				time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+
+			from := after
+			for _, want := range tt.want {
+				got := schedule.Next(from)
+				if !got.Equal(want) {
+					t.Fatalf("Next(%s) = %s, want %s", from, got, want)
+				}
+				from = got
+			}
+		})
+	}
+}
+
+func TestScheduleNextNever(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := schedule.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next() = %s, want the zero time for a day that never comes", got)
+	}
+}
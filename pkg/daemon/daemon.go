@@ -0,0 +1,351 @@
+// Handles the background daemon syncing sources and refreshing wallets on schedules
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/alerts"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Jobs run by the daemon
+const (
+	JobSync   = "sync"
+	JobPrices = "prices"
+)
+
+// Days fetched again before the last sync, so that transactions settled late are not missed
+const syncOverlapDays = 1
+
+type Daemon struct {
+	schedules map[string]*Schedule
+	sources   []syncSource
+	logFile   string
+	status    *Status
+}
+
+// Create a new Daemon object from `daemon` configuration
+func New() (*Daemon, error) {
+	d := &Daemon{schedules: make(map[string]*Schedule)}
+
+	for job, key := range map[string]string{JobSync: "daemon.syncSchedule", JobPrices: "daemon.priceSchedule"} {
+		schedule, err := ParseSchedule(viper.GetString(key))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse '%s': %w", key, err)
+		}
+		d.schedules[job] = schedule
+	}
+
+	sources, err := configuredSources(viper.GetStringSlice("daemon.sources"))
+	if err != nil {
+		return nil, err
+	}
+	d.sources = sources
+
+	logFile, err := logFilePath()
+	if err != nil {
+		return nil, err
+	}
+	d.logFile = logFile
+
+	return d, nil
+}
+
+// Return the sources to sync, the given ones or every configured one when none is given
+func configuredSources(names []string) ([]syncSource, error) {
+	if len(names) == 0 {
+		sources := []syncSource{}
+		for _, source := range syncSources {
+			if source.configured() {
+				sources = append(sources, source)
+			}
+		}
+
+		return sources, nil
+	}
+
+	sources := []syncSource{}
+	for _, name := range names {
+		found := false
+		for _, source := range syncSources {
+			if strings.EqualFold(source.name, name) {
+				sources = append(sources, source)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown source '%s' in 'daemon.sources'", name)
+		}
+	}
+
+	return sources, nil
+}
+
+// Return the path of the daemon log file, set in configuration
+func logFilePath() (string, error) {
+	if file := viper.GetString("daemon.logFile"); file != "" {
+		return os.ExpandEnv(file), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get user homedir: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".tracklet", "daemon.log"), nil
+}
+
+// Send logs to the log file as well
+func (d *Daemon) openLog() (*os.File, error) {
+	file, err := os.OpenFile(d.logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open log file: %w", err)
+	}
+	log.SetOutput(io.MultiWriter(os.Stderr, file))
+
+	return file, nil
+}
+
+// Restore the status of sources from a previous run, for syncs to go on from where they stopped
+func (d *Daemon) initStatus() {
+	d.status = newStatus()
+	if previous, err := LoadStatus(); err == nil {
+		d.status.Sources = previous.Sources
+	}
+
+	d.status.PID = os.Getpid()
+	d.status.Running = true
+	d.status.Started = time.Now().UnixMilli()
+	d.status.LogFile = d.logFile
+	for job, schedule := range d.schedules {
+		d.status.Jobs[job] = &JobStatus{Schedule: schedule.String()}
+	}
+	for _, source := range d.sources {
+		if d.status.Sources[source.name] == nil {
+			d.status.Sources[source.name] = &SourceStatus{}
+		}
+	}
+}
+
+// Save the status, logging failures
+func (d *Daemon) saveStatus() {
+	d.status.Updated = time.Now().UnixMilli()
+	if err := d.status.save(); err != nil {
+		log.Errorf("Could not save status: %v", err)
+	}
+}
+
+// Run the daemon until interrupted, syncing once at start then on schedules
+func (d *Daemon) Run() error {
+	file, err := d.openLog()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if len(d.sources) == 0 {
+		log.Warn("No source configured, only prices will be refreshed")
+	}
+	names := []string{}
+	for _, source := range d.sources {
+		names = append(names, source.name)
+	}
+	log.Infof("Starting daemon for %s, logging to %s", strings.Join(names, ", "), d.logFile)
+
+	d.initStatus()
+	d.saveStatus()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	d.runJob(JobSync, d.sync)
+
+	for {
+		job, next := d.nextJob()
+		if next.IsZero() {
+			return fmt.Errorf("no schedule ever runs")
+		}
+		d.saveStatus()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case sig := <-stop:
+			timer.Stop()
+			log.Infof("Received %s, stopping daemon", sig)
+			d.status.Running = false
+			d.saveStatus()
+
+			return nil
+		case <-timer.C:
+		}
+
+		if job == JobSync {
+			d.runJob(JobSync, d.sync)
+		} else {
+			d.runJob(JobPrices, d.refresh)
+		}
+	}
+}
+
+// Return the job running next and when, a sync taking precedence over a refresh it includes
+func (d *Daemon) nextJob() (string, time.Time) {
+	now := time.Now()
+	syncNext := d.schedules[JobSync].Next(now)
+	pricesNext := d.schedules[JobPrices].Next(now)
+	d.status.Jobs[JobSync].NextRun = syncNext.UnixMilli()
+	d.status.Jobs[JobPrices].NextRun = pricesNext.UnixMilli()
+
+	if syncNext.IsZero() || (!pricesNext.IsZero() && pricesNext.Before(syncNext)) {
+		return JobPrices, pricesNext
+	}
+
+	return JobSync, syncNext
+}
+
+// Run a job, recording its outcome, any failure or panic being logged rather than stopping the daemon
+func (d *Daemon) runJob(name string, job func() error) {
+	status := d.status.Jobs[name]
+	status.LastRun = time.Now().UnixMilli()
+	status.Runs++
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		return job()
+	}()
+
+	status.LastError = ""
+	if err != nil {
+		log.Errorf("Job '%s' failed: %v", name, err)
+		status.LastError = err.Error()
+	}
+
+	if err := metrics.Flush(); err != nil {
+		log.Warnf("Could not save fetcher metrics: %v", err)
+	}
+	d.saveStatus()
+}
+
+// Return the days of history to fetch for a source, those since its last sync when it can be synced incrementally
+func (d *Daemon) window(source syncSource) int {
+	maxHistory := viper.GetInt("tracklet.maxHistory")
+
+	lastSync := d.status.Sources[source.name].LastSync
+	if !source.incremental || lastSync == 0 {
+		return maxHistory
+	}
+
+	days := int(time.Since(time.UnixMilli(lastSync)).Hours()/24) + 1 + syncOverlapDays
+	if days > maxHistory {
+		return maxHistory
+	}
+
+	return days
+}
+
+// Sync a source, fetching only the history window from the API
+func (d *Daemon) syncSource(source syncSource) (err error) {
+	status := d.status.Sources[source.name]
+	status.LastAttempt = time.Now().UnixMilli()
+	status.LastWindow = d.window(source)
+
+	maxHistory := viper.GetInt("tracklet.maxHistory")
+	viper.Set("tracklet.maxHistory", status.LastWindow)
+	defer viper.Set("tracklet.maxHistory", maxHistory)
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	log.Infof("Syncing %s, %d days of history", source.name, status.LastWindow)
+
+	return source.sync()
+}
+
+// Sync every source then refresh wallets, sources failing being synced again on the next run
+func (d *Daemon) sync() error {
+	failed := []string{}
+	for _, source := range d.sources {
+		status := d.status.Sources[source.name]
+		if err := d.syncSource(source); err != nil {
+			log.Errorf("Could not sync %s: %v", source.name, err)
+			status.LastError = err.Error()
+			status.ConsecutiveFailures++
+			failed = append(failed, source.name)
+			continue
+		}
+
+		status.LastSync = status.LastAttempt
+		status.LastError = ""
+		status.ConsecutiveFailures = 0
+	}
+	d.saveStatus()
+
+	refreshErr := d.refresh()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not sync %s", strings.Join(failed, ", "))
+	}
+
+	return refreshErr
+}
+
+// Calculate wallets of every synced source with current prices and save their snapshots, then evaluate alerts
+func (d *Daemon) refresh() error {
+	ledgers, err := ledger.Sources()
+	if err != nil {
+		return fmt.Errorf("could not list sources: %w", err)
+	}
+	synced := make(map[string]bool)
+	for _, source := range ledgers {
+		synced[source] = true
+	}
+
+	failed := []string{}
+	for _, source := range d.sources {
+		if !synced[source.name] {
+			continue
+		}
+
+		log.Infof("Refreshing %s wallet", source.name)
+		wallet := source.wallet()
+		if err := wallet.Calculate(); err != nil {
+			log.Errorf("Could not calculate %s wallet: %v", source.name, err)
+			failed = append(failed, source.name+" wallet")
+			continue
+		}
+		if err := wallet.Save(); err != nil {
+			log.Errorf("Could not save %s wallet: %v", source.name, err)
+			failed = append(failed, source.name+" wallet")
+		}
+	}
+
+	if err := alerts.AfterRefresh(); err != nil {
+		log.Errorf("Could not evaluate alerts: %v", err)
+		failed = append(failed, "alerts")
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not refresh %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+// Handles the sources synced by the daemon
+package daemon
+
+import (
+	"github.com/eliasbokreta/tracklet/pkg/binance"
+	"github.com/eliasbokreta/tracklet/pkg/bitcoin"
+	"github.com/eliasbokreta/tracklet/pkg/bitpanda"
+	"github.com/eliasbokreta/tracklet/pkg/bitstamp"
+	"github.com/eliasbokreta/tracklet/pkg/bybit"
+	"github.com/eliasbokreta/tracklet/pkg/coinbase"
+	"github.com/eliasbokreta/tracklet/pkg/evm"
+	"github.com/eliasbokreta/tracklet/pkg/kraken"
+	"github.com/eliasbokreta/tracklet/pkg/kucoin"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/manual"
+	"github.com/eliasbokreta/tracklet/pkg/okx"
+	"github.com/spf13/viper"
+)
+
+// A wallet calculated from synced data and saved along with a snapshot
+type wallet interface {
+	Calculate() error
+	Save() error
+}
+
+// A source the daemon can sync
+type syncSource struct {
+	name string
+	// Check if the source is set up in configuration
+	configured func() bool
+	sync       func() error
+	wallet     func() wallet
+	// Whether only the history since the last sync can be fetched, the wallet being calculated from the ledger
+	incremental bool
+}
+
+// Check if an exchange API key is configured
+func hasAPIKey(exchange string) func() bool {
+	return func() bool {
+		return viper.GetString("exchanges."+exchange+".apiKey") != ""
+	}
+}
+
+// Return a function calculating the ledger wallet of a source
+func ledgerWallet(source string) func() wallet {
+	return func() wallet {
+		return ledger.NewWallet(source)
+	}
+}
+
+// Every source the daemon knows, in sync order
+var syncSources = []syncSource{
+	{
+		name:        "binance",
+		configured:  hasAPIKey("binance"),
+		sync:        func() error { return binance.New().ProcessBinanceData(false) },
+		wallet:      ledgerWallet("binance"),
+		incremental: true,
+	},
+	{
+		name:        "kucoin",
+		configured:  hasAPIKey("kucoin"),
+		sync:        func() error { return kucoin.New().ProcessKucoinData(false) },
+		wallet:      ledgerWallet("kucoin"),
+		incremental: true,
+	},
+	{
+		name:        "coinbase",
+		configured:  hasAPIKey("coinbase"),
+		sync:        func() error { return coinbase.New().ProcessCoinbaseData(false) },
+		wallet:      ledgerWallet("coinbase"),
+		incremental: true,
+	},
+	{
+		name:        "kraken",
+		configured:  hasAPIKey("kraken"),
+		sync:        func() error { return kraken.New().ProcessKrakenData(false) },
+		wallet:      ledgerWallet("kraken"),
+		incremental: true,
+	},
+	{
+		name:        "bitstamp",
+		configured:  hasAPIKey("bitstamp"),
+		sync:        func() error { return bitstamp.New().ProcessBitstampData(false) },
+		wallet:      ledgerWallet("bitstamp"),
+		incremental: true,
+	},
+	{
+		name:        "bitpanda",
+		configured:  hasAPIKey("bitpanda"),
+		sync:        func() error { return bitpanda.New().ProcessBitpandaData(false) },
+		wallet:      ledgerWallet("bitpanda"),
+		incremental: true,
+	},
+	{
+		name:        "okx",
+		configured:  hasAPIKey("okx"),
+		sync:        func() error { return okx.New().ProcessOKXData(false) },
+		wallet:      ledgerWallet("okx"),
+		incremental: true,
+	},
+	{
+		name:        "bybit",
+		configured:  hasAPIKey("bybit"),
+		sync:        func() error { return bybit.New().ProcessBybitData(false) },
+		wallet:      ledgerWallet("bybit"),
+		incremental: true,
+	},
+	{
+		name:       "bitcoin",
+		configured: func() bool { return len(viper.GetStringSlice("wallets.bitcoin.xpubs")) > 0 },
+		sync:       func() error { return bitcoin.New().ProcessBitcoinData(false) },
+		wallet:     ledgerWallet("bitcoin"),
+		// Addresses are scanned from their first transaction
+		incremental: false,
+	},
+	{
+		name:       "evm",
+		configured: func() bool { return len(viper.GetStringMap("wallets.evm.chains")) > 0 },
+		sync:       func() error { return evm.New().ProcessEVMData(false) },
+		wallet:     ledgerWallet("evm"),
+		// Accounts are read from their first transaction
+		incremental: false,
+	},
+	{
+		name: "manual",
+		configured: func() bool {
+			entries, err := manual.Load()
+			return err == nil && len(entries) > 0
+		},
+		sync:        manual.Sync,
+		wallet:      ledgerWallet("manual"),
+		incremental: false,
+	},
+}
@@ -0,0 +1,85 @@
+// Handles the daemon status, shared with other commands through a data file
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+)
+
+const statusFile = "daemon_status"
+
+// State of the daemon jobs and of every source it syncs, times being Unix milliseconds
+type Status struct {
+	PID     int                      `json:"pid"`
+	Running bool                     `json:"running"`
+	Started int64                    `json:"started"`
+	Updated int64                    `json:"updated"`
+	LogFile string                   `json:"logFile"`
+	Jobs    map[string]*JobStatus    `json:"jobs"`
+	Sources map[string]*SourceStatus `json:"sources"`
+}
+
+type JobStatus struct {
+	Schedule  string `json:"schedule"`
+	LastRun   int64  `json:"lastRun,omitempty"`
+	NextRun   int64  `json:"nextRun,omitempty"`
+	Runs      int    `json:"runs"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+type SourceStatus struct {
+	LastSync    int64 `json:"lastSync,omitempty"`
+	LastAttempt int64 `json:"lastAttempt,omitempty"`
+	// Days of history fetched by the last attempt, all of the history window for a full sync
+	LastWindow          int    `json:"lastWindow,omitempty"`
+	LastError           string `json:"lastError,omitempty"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+}
+
+// Create an empty status
+func newStatus() *Status {
+	return &Status{
+		Jobs:    make(map[string]*JobStatus),
+		Sources: make(map[string]*SourceStatus),
+	}
+}
+
+// Load the status saved by the daemon, checking whether its process is still running
+func LoadStatus() (*Status, error) {
+	if !utils.FileExists(fmt.Sprintf("%s.json", statusFile)) {
+		return nil, fmt.Errorf("no daemon status found, start it with `tracklet daemon`")
+	}
+	data := utils.LoadFromFile(fmt.Sprintf("%s.json", statusFile))
+
+	status := newStatus()
+	if err := json.Unmarshal(data, status); err != nil {
+		return nil, fmt.Errorf("could not unmarshal daemon status: %w", err)
+	}
+
+	status.Running = status.Running && processAlive(status.PID)
+
+	return status, nil
+}
+
+// Save the status to file
+func (s *Status) save() error {
+	if err := utils.WriteToFile(statusFile, s); err != nil {
+		return fmt.Errorf("could not save daemon status: %w", err)
+	}
+
+	return nil
+}
+
+// Check if a process exists
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	return process.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,517 @@
+// Handles the interactive terminal dashboard
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/alerts"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Views shown by tabs
+const (
+	viewHoldings = iota
+	viewAllocation
+	viewExchanges
+	viewTransactions
+)
+
+var viewNames = []string{"Holdings", "Allocation", "Exchanges", "Transactions"}
+
+const defaultRefreshInterval = 300
+
+// Width of allocation bars at 100%
+const barWidth = 40
+
+// Lines taken by the title, tabs, blank line and help
+const chromeHeight = 4
+
+type Dashboard struct {
+	coinList  *coingecko.CoinList
+	portfolio *portfolio
+	refresh   time.Duration
+	view      int
+	selected  int
+	offset    int
+	// Exchange shown, all of them when empty
+	source string
+	filter string
+	// Filter being typed
+	editing bool
+	// Asset drilled into, none when empty
+	detail  string
+	status  string
+	loading bool
+}
+
+// Result of a portfolio load running in background
+type loaded struct {
+	portfolio *portfolio
+	// Alerts notified against the loaded portfolio
+	alerts []string
+	err    error
+}
+
+// Create a new Dashboard object, prices being refreshed every `dashboard.refreshInterval` seconds
+func New() *Dashboard {
+	refresh := viper.GetInt("dashboard.refreshInterval")
+	if refresh <= 0 {
+		log.Warnf("Invalid dashboard refresh interval %d, using %d seconds", refresh, defaultRefreshInterval)
+		refresh = defaultRefreshInterval
+	}
+
+	return &Dashboard{
+		refresh: time.Duration(refresh) * time.Second,
+	}
+}
+
+// Load the portfolio in background, sending it to a channel
+func (d *Dashboard) load(results chan<- loaded) {
+	if d.loading {
+		return
+	}
+	d.loading = true
+	d.status = "Refreshing prices..."
+
+	go func() {
+		p, err := loadPortfolio(d.coinList)
+		if err != nil {
+			results <- loaded{err: err}
+			return
+		}
+		results <- loaded{portfolio: p, alerts: evaluateAlerts(p)}
+	}()
+}
+
+// Evaluate alert rules against refreshed prices, returning the text of those notified
+// Alerts are shown in the status line rather than printed over the screen
+func evaluateAlerts(p *portfolio) []string {
+	if !alerts.Configured() {
+		return nil
+	}
+
+	sinks, err := alerts.LoadSinks()
+	if err != nil {
+		return []string{fmt.Sprintf("Could not load alert sinks: %v", err)}
+	}
+	results, err := alerts.EvaluatePortfolio(p.alertsPortfolio(), alerts.WithoutStdout(sinks))
+	if err != nil {
+		return []string{fmt.Sprintf("Could not evaluate alerts: %v", err)}
+	}
+
+	notified := []string{}
+	for _, result := range results {
+		if result.Notified {
+			notified = append(notified, fmt.Sprintf("[%s] %s", result.Rule, result.Text))
+		}
+	}
+
+	return notified
+}
+
+// Show the dashboard until quit, logs being discarded not to break the screen
+func (d *Dashboard) Run() error {
+	log.Info("Getting Coingecko coin list")
+	coinList, err := coingecko.GetCoinList()
+	if err != nil {
+		return fmt.Errorf("could not get coin list: %w", err)
+	}
+	d.coinList = coinList
+
+	terminal, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer terminal.close()
+
+	output := log.StandardLogger().Out
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(output)
+
+	keys := make(chan rune)
+	go readKeys(keys)
+
+	results := make(chan loaded)
+	d.load(results)
+
+	ticker := time.NewTicker(d.refresh)
+	defer ticker.Stop()
+	// Redraw regularly to follow terminal resizes
+	redraw := time.NewTicker(time.Second)
+	defer redraw.Stop()
+
+	for {
+		_, height := terminal.size()
+		terminal.draw(d.render(height))
+
+		select {
+		case key, ok := <-keys:
+			if !ok || !d.handleKey(key, results) {
+				return nil
+			}
+		case result := <-results:
+			d.loading = false
+			if result.err != nil {
+				d.status = fmt.Sprintf("Could not refresh: %v", result.err)
+				continue
+			}
+			d.portfolio = result.portfolio
+			d.status = strings.Join(result.alerts, "  ")
+		case <-ticker.C:
+			d.load(results)
+		case <-redraw.C:
+		}
+	}
+}
+
+// Apply a key, returning false to quit
+func (d *Dashboard) handleKey(key rune, results chan<- loaded) bool {
+	if d.editing {
+		switch key {
+		case keyEnter:
+			d.editing = false
+		case keyEscape:
+			d.editing, d.filter = false, ""
+		case keyBackspace:
+			if len(d.filter) > 0 {
+				d.filter = string([]rune(d.filter)[:len([]rune(d.filter))-1])
+			}
+		case keyCtrlC:
+			return false
+		default:
+			if key >= ' ' && key <= 0x10FFFF {
+				d.filter += string(key)
+			}
+		}
+		d.selected, d.offset = 0, 0
+		return true
+	}
+
+	switch key {
+	case 'q', keyCtrlC:
+		return false
+	case keyTab:
+		d.showView((d.view + 1) % len(viewNames))
+	case '1', '2', '3', '4':
+		d.showView(int(key - '1'))
+	case keyUp, 'k':
+		d.selected--
+	case keyDown, 'j':
+		d.selected++
+	case keyPageUp:
+		d.selected -= 10
+	case keyPageDown:
+		d.selected += 10
+	case keyEnter:
+		d.drillDown()
+	case keyEscape, keyBackspace:
+		if d.detail != "" {
+			d.detail, d.selected, d.offset = "", 0, 0
+		} else {
+			d.filter = ""
+		}
+	case '/':
+		d.editing = true
+	case 'e':
+		d.nextSource()
+	case 'r':
+		d.load(results)
+	}
+
+	return true
+}
+
+// Switch to a view, leaving any drill down
+func (d *Dashboard) showView(view int) {
+	d.view, d.detail, d.selected, d.offset = view, "", 0, 0
+}
+
+// Show the next exchange, all of them coming after the last one
+func (d *Dashboard) nextSource() {
+	if d.portfolio == nil {
+		return
+	}
+
+	sources := append([]string{""}, d.portfolio.sources()...)
+	for i, source := range sources {
+		if source == d.source {
+			d.source = sources[(i+1)%len(sources)]
+			break
+		}
+	}
+	d.selected, d.offset = 0, 0
+}
+
+// Open the selected row: an asset shows its transactions and lots, an exchange filters other views
+func (d *Dashboard) drillDown() {
+	if d.portfolio == nil || d.detail != "" {
+		return
+	}
+
+	_, _, keys := d.body()
+	if d.selected < 0 || d.selected >= len(keys) {
+		return
+	}
+
+	if d.view == viewExchanges {
+		d.source = keys[d.selected]
+		d.showView(viewHoldings)
+		return
+	}
+	d.detail, d.selected, d.offset = keys[d.selected], 0, 0
+}
+
+// Return lines above the table, the table and the key of each row for the current view
+func (d *Dashboard) body() ([]string, *utils.Table, []string) {
+	p := d.portfolio
+	if d.detail != "" {
+		return d.detailBody()
+	}
+
+	table := &utils.Table{Numeric: map[int]bool{}}
+	keys := []string{}
+
+	switch d.view {
+	case viewHoldings:
+		table.Header = []string{"Asset", "Name", "Quantity", "Price", "Value", "Allocation", "Cost", "PnL", "PnL %"}
+		table.Numeric = map[int]bool{2: true, 3: true, 4: true, 5: true, 6: true, 7: true, 8: true}
+		assets := p.assets(d.source, d.filter)
+		total := 0.0
+		for _, a := range assets {
+			total += a.value
+		}
+		for _, a := range assets {
+			cost, pnl, pnlPercent := "-", "-", "-"
+			if a.cost > 0 {
+				gain := a.value - a.cost
+				cost = fmt.Sprintf("%.2f", a.cost)
+				pnl = utils.ColorSign(fmt.Sprintf("%+.2f", gain), gain)
+				pnlPercent = utils.ColorSign(fmt.Sprintf("%+.1f%%", gain/a.cost*100), gain)
+			}
+			table.Rows = append(table.Rows, []string{
+				a.asset, a.name, ledger.FormatQuantity(a.quantity), ledger.FormatPrice(p.prices[a.asset]),
+				fmt.Sprintf("%.2f", a.value), fmt.Sprintf("%.1f%%", percent(a.value, total)), cost, pnl, pnlPercent,
+			})
+			keys = append(keys, a.asset)
+		}
+	case viewAllocation:
+		table.Header = []string{"Asset", "Value", "Allocation", ""}
+		table.Numeric = map[int]bool{1: true, 2: true}
+		assets := p.assets(d.source, d.filter)
+		total := 0.0
+		for _, a := range assets {
+			total += a.value
+		}
+		for _, a := range assets {
+			share := percent(a.value, total)
+			table.Rows = append(table.Rows, []string{
+				a.asset, fmt.Sprintf("%.2f", a.value), fmt.Sprintf("%.1f%%", share), bar(share),
+			})
+			keys = append(keys, a.asset)
+		}
+	case viewExchanges:
+		table.Header = []string{"Exchange", "Value", "Share", "Cash", "Assets", ""}
+		table.Numeric = map[int]bool{1: true, 2: true, 3: true, 4: true}
+		sources := p.sourceTotals(d.filter)
+		total := 0.0
+		for _, s := range sources {
+			total += s.value
+		}
+		for _, s := range sources {
+			share := percent(s.value, total)
+			table.Rows = append(table.Rows, []string{
+				s.source, fmt.Sprintf("%.2f", s.value), fmt.Sprintf("%.1f%%", share), fmt.Sprintf("%.2f", s.cash),
+				fmt.Sprintf("%d", s.assets), bar(share),
+			})
+			keys = append(keys, s.source)
+		}
+	case viewTransactions:
+		transactions := p.filterTransactions(d.source, "", d.filter)
+		table = transactionsTable(transactions)
+		for _, tx := range transactions {
+			keys = append(keys, tx.Asset)
+		}
+	}
+
+	return nil, table, keys
+}
+
+// Return the lots of the drilled down asset by source, and its transactions
+func (d *Dashboard) detailBody() ([]string, *utils.Table, []string) {
+	p := d.portfolio
+	top := []string{bold + fmt.Sprintf("%s %s", d.detail, p.names[d.detail]) + reset}
+
+	for _, source := range p.sources() {
+		if d.source != "" && source != d.source {
+			continue
+		}
+		quantity, ok := p.holdings[source][d.detail]
+		if !ok {
+			continue
+		}
+
+		line := fmt.Sprintf("%-10s %s (%.2f %s)", source, ledger.FormatQuantity(quantity), quantity*p.prices[d.detail], p.currency)
+		if position := p.positions.Source(source)[d.detail]; position != nil {
+			lots := []string{}
+			for _, lot := range position.Lots() {
+				if lot.Quantity > 0 {
+					lots = append(lots, fmt.Sprintf("%s at %s", ledger.FormatQuantity(lot.Quantity), ledger.FormatPrice(lot.Cost/lot.Quantity)))
+				}
+			}
+			line += fmt.Sprintf(", cost %.2f, lots: %s", position.Cost, strings.Join(lots, ", "))
+		}
+		top = append(top, line)
+	}
+	top = append(top, "")
+
+	transactions := p.filterTransactions(d.source, d.detail, d.filter)
+
+	return top, transactionsTable(transactions), make([]string, len(transactions))
+}
+
+// Build the table of transactions
+func transactionsTable(transactions []ledger.Transaction) *utils.Table {
+	table := &utils.Table{
+		Header:  []string{"Time", "Exchange", "Type", "Amount", "Asset", "Quote", "Fee"},
+		Numeric: map[int]bool{3: true},
+	}
+
+	for _, tx := range transactions {
+		quote, fee := "", ""
+		if tx.QuoteAsset != "" {
+			quote = fmt.Sprintf("%s %s", ledger.FormatQuantity(tx.QuoteAmount), tx.QuoteAsset)
+		}
+		if tx.FeeAsset != "" {
+			fee = fmt.Sprintf("%s %s", ledger.FormatQuantity(tx.FeeAmount), tx.FeeAsset)
+		}
+		table.Rows = append(table.Rows, []string{
+			time.UnixMilli(tx.Time).UTC().Format("2006-01-02 15:04"), tx.Source, string(tx.Type),
+			ledger.FormatQuantity(tx.Amount), tx.Asset, quote, fee,
+		})
+	}
+
+	return table
+}
+
+// Share of a value in a total, in percent
+func percent(value float64, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return value / total * 100
+}
+
+// Draw a percentage as a bar
+func bar(share float64) string {
+	width := int(share / 100 * barWidth)
+	if width < 0 {
+		width = 0
+	}
+
+	return strings.Repeat("█", width)
+}
+
+// Return the screen lines of the current state
+func (d *Dashboard) render(height int) []string {
+	lines := []string{d.title(), d.tabs(), ""}
+
+	if d.portfolio == nil {
+		return append(lines, "Loading portfolio...")
+	}
+
+	top, table, keys := d.body()
+	lines = append(lines, top...)
+
+	tableLines := table.Lines()
+	lines = append(lines, bold+tableLines[0]+reset, tableLines[1])
+
+	rows := height - chromeHeight - len(top) - 2
+	if rows < 1 {
+		rows = 1
+	}
+	if d.selected >= len(keys) {
+		d.selected = len(keys) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+	if d.selected < d.offset {
+		d.offset = d.selected
+	}
+	if d.selected >= d.offset+rows {
+		d.offset = d.selected - rows + 1
+	}
+
+	for i := d.offset; i < len(table.Rows) && i < d.offset+rows; i++ {
+		line := tableLines[i+2]
+		if i == d.selected {
+			line = reverse + strings.ReplaceAll(line, reset, reset+reverse) + reset
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+
+	return append(lines, d.help())
+}
+
+// Return the title line with the total value and active filters
+func (d *Dashboard) title() string {
+	title := "tracklet"
+	if d.portfolio != nil {
+		total := 0.0
+		for _, a := range d.portfolio.assets(d.source, "") {
+			total += a.value
+		}
+		title += fmt.Sprintf(" | %.2f %s | updated %s", total, d.portfolio.currency, d.portfolio.updated.Format("15:04:05"))
+	}
+
+	source := d.source
+	if source == "" {
+		source = "all"
+	}
+	title += fmt.Sprintf(" | exchange: %s", source)
+	if d.filter != "" || d.editing {
+		title += fmt.Sprintf(" | filter: %s", d.filter)
+		if d.editing {
+			title += "_"
+		}
+	}
+
+	return reverse + title + strings.Repeat(" ", 200) + reset
+}
+
+// Return the tabs line, the current view highlighted
+func (d *Dashboard) tabs() string {
+	tabs := []string{}
+	for i, name := range viewNames {
+		tab := fmt.Sprintf(" %d %s ", i+1, name)
+		if i == d.view && d.detail == "" {
+			tab = bold + reverse + tab + reset
+		}
+		tabs = append(tabs, tab)
+	}
+
+	return strings.Join(tabs, " ")
+}
+
+// Return the help line, or the status when set
+func (d *Dashboard) help() string {
+	if d.status != "" {
+		return d.status
+	}
+	if d.editing {
+		return "Type to filter by asset or exchange, enter to apply, esc to clear"
+	}
+
+	return "tab/1-4 views  ↑↓ move  enter open  esc back  / filter  e exchange  r refresh  q quit"
+}
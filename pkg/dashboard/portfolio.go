@@ -0,0 +1,218 @@
+// Handles portfolio data shown by the dashboard
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/alerts"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+)
+
+// Holdings of every source from ledgers, valued at current prices
+type portfolio struct {
+	currency string
+	// Transactions of all sources, newest first
+	transactions []ledger.Transaction
+	// Quantities by source then asset
+	holdings  map[string]map[string]float64
+	positions *ledger.Positions
+	// Unit prices and names by asset
+	prices  map[string]float64
+	names   map[string]string
+	updated time.Time
+}
+
+// Total of an asset, in a single source or across several
+type assetTotal struct {
+	asset    string
+	name     string
+	quantity float64
+	value    float64
+	cost     float64
+}
+
+// Total of a source
+type sourceTotal struct {
+	source string
+	value  float64
+	cash   float64
+	assets int
+}
+
+// Load ledgers of all sources and value their holdings at current Coingecko prices
+func loadPortfolio(coinList *coingecko.CoinList) (*portfolio, error) {
+	transactions, err := ledger.LoadAllWithCosts()
+	if err != nil {
+		return nil, err
+	}
+
+	prices := ledger.NewPriceHistory(coinList)
+	p := &portfolio{
+		currency:  prices.Currency(),
+		holdings:  make(map[string]map[string]float64),
+		positions: ledger.CalculateCostBasis(transactions, ledger.MatchTransfers(transactions), ledger.CostBasisMethod(), prices),
+		prices:    make(map[string]float64),
+		names:     make(map[string]string),
+		updated:   time.Now(),
+	}
+
+	bySource := make(map[string]map[string]ledger.Holdings)
+	for _, tx := range transactions {
+		if bySource[tx.Source] == nil {
+			bySource[tx.Source] = make(map[string]ledger.Holdings)
+		}
+		ledger.ApplyTransaction(bySource[tx.Source], &ledger.Stats{}, tx)
+	}
+
+	// Prices are requested once per asset, valuing a single unit
+	units := make(map[string]ledger.Holdings)
+	for source, holdings := range bySource {
+		p.holdings[source] = make(map[string]float64)
+		for asset, d := range holdings {
+			if math.Abs(d.Quantity) > 1e-9 {
+				p.holdings[source][asset] = d.Quantity
+				units[asset] = ledger.Holdings{Quantity: 1}
+			}
+		}
+	}
+	ledger.CalculatePrices(units, coinList, prices)
+	for asset, unit := range units {
+		p.prices[asset] = unit.CurrentValue
+		p.names[asset] = unit.Name
+	}
+
+	if err := prices.Save(); err != nil {
+		return nil, fmt.Errorf("could not cache prices: %w", err)
+	}
+
+	p.transactions = transactions
+	sort.SliceStable(p.transactions, func(i, j int) bool {
+		return p.transactions[i].Time > p.transactions[j].Time
+	})
+
+	return p, nil
+}
+
+// Return the value of every asset held across sources, for alert rules to be evaluated against
+func (p *portfolio) alertsPortfolio() *alerts.Portfolio {
+	portfolio := &alerts.Portfolio{
+		Currency: p.currency,
+		Assets:   make(map[string]float64),
+		Cash:     make(map[string]bool),
+	}
+	for _, total := range p.assets("", "") {
+		portfolio.Assets[total.asset] = total.value
+		portfolio.Cash[total.asset] = ledger.IsCash(total.asset)
+		portfolio.Value += total.value
+	}
+
+	return portfolio
+}
+
+// Return the sources holding assets, sorted by name
+func (p *portfolio) sources() []string {
+	sources := []string{}
+	for source := range p.holdings {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	return sources
+}
+
+// Check if text is empty or found in one of the values, ignoring case
+func matches(text string, values ...string) bool {
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), strings.ToLower(text)) {
+			return true
+		}
+	}
+
+	return text == ""
+}
+
+// Sum holdings by asset, of a source or all of them when empty, sorted by value
+func (p *portfolio) assets(source string, text string) []assetTotal {
+	totals := make(map[string]*assetTotal)
+	for s, holdings := range p.holdings {
+		if source != "" && s != source {
+			continue
+		}
+		for asset, quantity := range holdings {
+			if !matches(text, asset, p.names[asset], s) {
+				continue
+			}
+			if totals[asset] == nil {
+				totals[asset] = &assetTotal{asset: asset, name: p.names[asset]}
+			}
+			totals[asset].quantity += quantity
+			totals[asset].value += quantity * p.prices[asset]
+			if position := p.positions.Source(s)[asset]; position != nil {
+				totals[asset].cost += position.Cost
+			}
+		}
+	}
+
+	assets := []assetTotal{}
+	for _, total := range totals {
+		assets = append(assets, *total)
+	}
+	sort.SliceStable(assets, func(i, j int) bool {
+		if assets[i].value != assets[j].value {
+			return assets[i].value > assets[j].value
+		}
+		return assets[i].asset < assets[j].asset
+	})
+
+	return assets
+}
+
+// Sum holdings by source, sorted by value
+func (p *portfolio) sourceTotals(text string) []sourceTotal {
+	totals := []sourceTotal{}
+	for _, source := range p.sources() {
+		total := sourceTotal{source: source}
+		for asset, quantity := range p.holdings[source] {
+			if !matches(text, asset, p.names[asset], source) {
+				continue
+			}
+			value := quantity * p.prices[asset]
+			total.value += value
+			if ledger.IsCash(asset) {
+				total.cash += value
+			} else {
+				total.assets++
+			}
+		}
+		totals = append(totals, total)
+	}
+	sort.SliceStable(totals, func(i, j int) bool {
+		return totals[i].value > totals[j].value
+	})
+
+	return totals
+}
+
+// Return transactions of a source moving an asset, any of them when empty, newest first
+func (p *portfolio) filterTransactions(source string, asset string, text string) []ledger.Transaction {
+	filtered := []ledger.Transaction{}
+	for _, tx := range p.transactions {
+		if source != "" && tx.Source != source {
+			continue
+		}
+		if asset != "" && tx.Asset != asset && tx.QuoteAsset != asset && tx.FeeAsset != asset {
+			continue
+		}
+		if !matches(text, tx.Asset, tx.QuoteAsset, tx.Source, string(tx.Type)) {
+			continue
+		}
+		filtered = append(filtered, tx)
+	}
+
+	return filtered
+}
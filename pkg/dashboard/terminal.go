@@ -0,0 +1,173 @@
+// Handles terminal screen drawing and keyboard input
+package dashboard
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Keys other than printable characters
+const (
+	keyUp = iota + utf8.MaxRune + 1
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyTab
+	keyCtrlC
+)
+
+const (
+	escape        = "\033"
+	clearLine     = escape + "[K"
+	reverse       = escape + "[7m"
+	bold          = escape + "[1m"
+	reset         = escape + "[0m"
+	altScreenOn   = escape + "[?1049h"
+	altScreenOff  = escape + "[?1049l"
+	cursorHide    = escape + "[?25l"
+	cursorShow    = escape + "[?25h"
+	cursorHome    = escape + "[H"
+	clearBelow    = escape + "[J"
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Terminal in raw mode, drawn on the alternate screen
+type terminal struct {
+	restore func() error
+	writer  *bufio.Writer
+}
+
+// Switch the terminal to raw mode and the alternate screen
+func openTerminal() (*terminal, error) {
+	restore, err := makeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("could not set terminal raw mode: %w", err)
+	}
+
+	t := &terminal{restore: restore, writer: bufio.NewWriter(os.Stdout)}
+	t.writer.WriteString(altScreenOn + cursorHide)
+	t.writer.Flush()
+
+	return t, nil
+}
+
+// Restore the terminal as it was
+func (t *terminal) close() error {
+	t.writer.WriteString(cursorShow + altScreenOff)
+	t.writer.Flush()
+
+	return t.restore()
+}
+
+// Return the terminal width and height, defaulting to 80x24
+func (t *terminal) size() (int, int) {
+	width, height, err := windowSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return defaultWidth, defaultHeight
+	}
+
+	return width, height
+}
+
+// Draw lines from the top of the screen, each cut to the terminal width
+func (t *terminal) draw(lines []string) {
+	width, height := t.size()
+
+	t.writer.WriteString(cursorHome)
+	for i, line := range lines {
+		if i >= height {
+			break
+		}
+		t.writer.WriteString(fit(line, width) + clearLine)
+		if i < height-1 && i < len(lines)-1 {
+			t.writer.WriteString("\r\n")
+		}
+	}
+	t.writer.WriteString(clearBelow)
+	t.writer.Flush()
+}
+
+// Cut a line to a number of visible characters, escape sequences not counting
+func fit(line string, width int) string {
+	var b strings.Builder
+	visible, inEscape := 0, false
+
+	for _, r := range line {
+		switch {
+		case inEscape:
+			b.WriteRune(r)
+			inEscape = !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+		case r == '\033':
+			b.WriteRune(r)
+			inEscape = true
+		case visible < width:
+			b.WriteRune(r)
+			visible++
+		}
+	}
+	b.WriteString(reset)
+
+	return b.String()
+}
+
+// Read keys from stdin until it is closed, sending them to a channel
+func readKeys(keys chan<- rune) {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			close(keys)
+			return
+		}
+
+		switch r {
+		case '\r', '\n':
+			keys <- keyEnter
+		case '\t':
+			keys <- keyTab
+		case 127, 8:
+			keys <- keyBackspace
+		case 3:
+			keys <- keyCtrlC
+		case '\033':
+			// Arrow and page keys come as escape sequences, a lone escape being the escape key
+			if reader.Buffered() == 0 {
+				keys <- keyEscape
+				continue
+			}
+			sequence := []rune{}
+			for reader.Buffered() > 0 {
+				next, _, err := reader.ReadRune()
+				if err != nil {
+					break
+				}
+				sequence = append(sequence, next)
+				if next >= 'A' && next <= 'Z' || next == '~' {
+					break
+				}
+			}
+			switch string(sequence) {
+			case "[A", "OA":
+				keys <- keyUp
+			case "[B", "OB":
+				keys <- keyDown
+			case "[5~":
+				keys <- keyPageUp
+			case "[6~":
+				keys <- keyPageDown
+			default:
+				keys <- keyEscape
+			}
+		default:
+			keys <- r
+		}
+	}
+}
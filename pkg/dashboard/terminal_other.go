@@ -0,0 +1,17 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package dashboard
+
+import "errors"
+
+var errUnsupported = errors.New("dashboard is not supported on this platform")
+
+// Raw mode is only implemented for unix terminals
+func makeRaw(fd int) (func() error, error) {
+	return nil, errUnsupported
+}
+
+// Window size is only implemented for unix terminals
+func windowSize(fd int) (int, int, error) {
+	return 0, 0, errUnsupported
+}
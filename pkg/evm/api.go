@@ -0,0 +1,195 @@
+// Handles JSON-RPC and explorer API endpoints logic
+package evm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	balanceOfSelector = "0x70a08231"
+	decimalsSelector  = "0x313ce567"
+	symbolSelector    = "0x95d89b41"
+	pageSize          = 1000
+)
+
+// Explorer history actions
+const (
+	actionTransactions         = "txlist"
+	actionInternalTransactions = "txlistinternal"
+	actionTokenTransfers       = "tokentx"
+)
+
+// A transaction, internal transaction or token transfer as returned by Etherscan-compatible explorers
+type ExplorerTransaction struct {
+	Hash            string `json:"hash"`
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	GasUsed         string `json:"gasUsed"`
+	GasPrice        string `json:"gasPrice"`
+	IsError         string `json:"isError"`
+	ContractAddress string `json:"contractAddress"`
+	TokenSymbol     string `json:"tokenSymbol"`
+	TokenDecimal    string `json:"tokenDecimal"`
+	LogIndex        string `json:"logIndex"`
+	TraceID         string `json:"traceId"`
+}
+
+// Parse a JSON-RPC hexadecimal quantity
+func parseHex(result []byte) (*big.Int, error) {
+	var value string
+	if err := json.Unmarshal(result, &value); err != nil {
+		return nil, fmt.Errorf("could not unmarshal quantity: %w", err)
+	}
+
+	value = strings.TrimPrefix(value, "0x")
+	if value == "" {
+		return new(big.Int), nil
+	}
+
+	n, ok := new(big.Int).SetString(value, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid quantity '%s'", value)
+	}
+
+	return n, nil
+}
+
+// Call a read only contract method
+func (c *Client) callContract(contract string, data string) ([]byte, error) {
+	params := []interface{}{
+		map[string]string{"to": contract, "data": data},
+		"latest",
+	}
+
+	return c.CallWithRetries("eth_call", params)
+}
+
+// Get the native asset balance of an address, in wei
+func (c *Client) GetNativeBalance(address string) (*big.Int, error) {
+	result, err := c.CallWithRetries("eth_getBalance", []interface{}{address, "latest"})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseHex(result)
+}
+
+// Get the ERC-20 token balance of an address, in token base units
+func (c *Client) GetTokenBalance(contract string, address string) (*big.Int, error) {
+	account := strings.TrimPrefix(address, "0x")
+	data := fmt.Sprintf("%s%s%s", balanceOfSelector, strings.Repeat("0", 64-len(account)), account)
+
+	result, err := c.callContract(contract, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseHex(result)
+}
+
+// Get the number of decimals of an ERC-20 token
+func (c *Client) GetTokenDecimals(contract string) (int, error) {
+	result, err := c.callContract(contract, decimalsSelector)
+	if err != nil {
+		return 0, err
+	}
+
+	decimals, err := parseHex(result)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(decimals.Int64()), nil
+}
+
+// Get the symbol of an ERC-20 token, ABI encoded as a string or as bytes32 by older tokens
+func (c *Client) GetTokenSymbol(contract string) (string, error) {
+	result, err := c.callContract(contract, symbolSelector)
+	if err != nil {
+		return "", err
+	}
+
+	var value string
+	if err := json.Unmarshal(result, &value); err != nil {
+		return "", fmt.Errorf("could not unmarshal symbol: %w", err)
+	}
+
+	data, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("could not decode symbol: %w", err)
+	}
+
+	if len(data) >= 64 {
+		length := new(big.Int).SetBytes(data[32:64]).Int64()
+		if 64+length <= int64(len(data)) {
+			return string(data[64 : 64+length]), nil
+		}
+	}
+
+	return strings.TrimRight(string(data), "\x00"), nil
+}
+
+// Get the full history of an address for a given explorer action, oldest first
+// Explorers limit paging depth, so pages move forward by start block
+func (c *Client) GetHistory(action string, address string) ([]ExplorerTransaction, error) {
+	history := []ExplorerTransaction{}
+	startBlock := int64(0)
+
+	for {
+		params := map[string]string{
+			"module":     "account",
+			"action":     action,
+			"address":    address,
+			"startblock": strconv.FormatInt(startBlock, 10),
+			"endblock":   "99999999999",
+			"page":       "1",
+			"offset":     strconv.Itoa(pageSize),
+			"sort":       "asc",
+		}
+
+		body, err := c.RequestWithRetries(params)
+		if err != nil {
+			return nil, err
+		}
+
+		page := []ExplorerTransaction{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("could not unmarshal '%s' history: %w", action, err)
+		}
+
+		if len(page) < pageSize {
+			history = append(history, page...)
+			break
+		}
+
+		// The last block may be incomplete, it is requested again with the next page
+		lastBlock, err := strconv.ParseInt(page[len(page)-1].BlockNumber, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse block number: %w", err)
+		}
+
+		complete := page
+		for len(complete) > 0 && complete[len(complete)-1].BlockNumber == page[len(page)-1].BlockNumber {
+			complete = complete[:len(complete)-1]
+		}
+
+		if len(complete) == 0 {
+			history = append(history, page...)
+			startBlock = lastBlock + 1
+			continue
+		}
+
+		history = append(history, complete...)
+		startBlock = lastBlock
+	}
+
+	return history, nil
+}
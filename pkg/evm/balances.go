@@ -0,0 +1,34 @@
+// Handles on chain balances retrieval
+package evm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+)
+
+// Get on chain balances per asset, summing every address of every chain
+// Balances are those read along with the history, so that both describe the same block
+func GetBalances() (map[string]float64, error) {
+	if !utils.FileExists("evm_accounts.json") {
+		return nil, errors.New("no EVM accounts fetched yet, run 'tracklet evm process' first")
+	}
+
+	accounts := []Account{}
+	if err := json.Unmarshal(utils.LoadFromFile("evm_accounts.json"), &accounts); err != nil {
+		return nil, fmt.Errorf("could not unmarshal EVM accounts: %w", err)
+	}
+
+	balances := make(map[string]float64)
+	for _, account := range accounts {
+		for asset, balance := range account.Balances {
+			if balance != 0 {
+				balances[asset] += balance
+			}
+		}
+	}
+
+	return balances, nil
+}
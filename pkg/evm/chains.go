@@ -0,0 +1,95 @@
+// Handles EVM chains configuration
+package evm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type Token struct {
+	Contract string `mapstructure:"contract" json:"contract"`
+	Symbol   string `mapstructure:"symbol" json:"symbol"`
+	Decimals int    `mapstructure:"decimals" json:"decimals"`
+}
+
+type Chain struct {
+	Name           string   `mapstructure:"-" json:"name"`
+	RPCURL         string   `mapstructure:"rpcURL" json:"-"`
+	ExplorerURL    string   `mapstructure:"explorerURL" json:"-"`
+	ExplorerAPIKey string   `mapstructure:"explorerAPIKey" json:"-"`
+	NativeAsset    string   `mapstructure:"nativeAsset" json:"nativeAsset"`
+	Addresses      []string `mapstructure:"addresses" json:"addresses"`
+	Tokens         []Token  `mapstructure:"tokens" json:"tokens"`
+}
+
+// Default settings of known chains, any field being overridable from configuration
+var knownChains = map[string]Chain{
+	"ethereum": {
+		RPCURL:      "https://cloudflare-eth.com",
+		ExplorerURL: "https://api.etherscan.io/api",
+		NativeAsset: "ETH",
+	},
+	"bsc": {
+		RPCURL:      "https://bsc-dataseed.binance.org",
+		ExplorerURL: "https://api.bscscan.com/api",
+		NativeAsset: "BNB",
+	},
+	"polygon": {
+		RPCURL:      "https://polygon-rpc.com",
+		ExplorerURL: "https://api.polygonscan.com/api",
+		NativeAsset: "POL",
+	},
+	"arbitrum": {
+		RPCURL:      "https://arb1.arbitrum.io/rpc",
+		ExplorerURL: "https://api.arbiscan.io/api",
+		NativeAsset: "ETH",
+	},
+}
+
+// Load configured chains, completed with known chains defaults
+func loadChains() ([]Chain, error) {
+	configured := make(map[string]Chain)
+	if err := viper.UnmarshalKey("wallets.evm.chains", &configured); err != nil {
+		return nil, fmt.Errorf("could not unmarshal chains: %w", err)
+	}
+
+	chains := []Chain{}
+	for name, chain := range configured {
+		chain.Name = name
+
+		if known, ok := knownChains[name]; ok {
+			if chain.RPCURL == "" {
+				chain.RPCURL = known.RPCURL
+			}
+			if chain.ExplorerURL == "" {
+				chain.ExplorerURL = known.ExplorerURL
+			}
+			if chain.NativeAsset == "" {
+				chain.NativeAsset = known.NativeAsset
+			}
+		}
+
+		// Wallets are built from history, which only explorers give
+		if chain.RPCURL == "" || chain.ExplorerURL == "" || chain.NativeAsset == "" {
+			return nil, fmt.Errorf("chain '%s' needs 'rpcURL', 'explorerURL' and 'nativeAsset' settings", name)
+		}
+
+		for i := range chain.Addresses {
+			chain.Addresses[i] = strings.ToLower(chain.Addresses[i])
+		}
+		for i := range chain.Tokens {
+			chain.Tokens[i].Contract = strings.ToLower(chain.Tokens[i].Contract)
+		}
+
+		chains = append(chains, chain)
+	}
+
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].Name < chains[j].Name
+	})
+
+	return chains, nil
+}
@@ -0,0 +1,192 @@
+// Handles HTTP requests logic
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Client struct {
+	HTTPClient     *http.Client
+	RPCURL         string
+	ExplorerURL    string
+	ExplorerAPIKey string
+	RetryDelay     time.Duration
+	MaxRetries     int
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type explorerResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// Create a new Client object for a given chain
+func NewClient(chain Chain) *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: time.Second * viper.GetDuration("tracklet.timeout"),
+		},
+		RPCURL:         chain.RPCURL,
+		ExplorerURL:    chain.ExplorerURL,
+		ExplorerAPIKey: chain.ExplorerAPIKey,
+		RetryDelay:     viper.GetDuration("tracklet.retryDelay"),
+		MaxRetries:     viper.GetInt("tracklet.maxRetries"),
+	}
+}
+
+// Send an HTTP request and return its body
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non valid HTTP status code : %s", response.Status)
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading body: %w", err)
+	}
+
+	return body, nil
+}
+
+// JSON-RPC call to the chain node, returning its result
+func (c *Client) call(method string, params []interface{}) ([]byte, error) {
+	payload, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal rpc request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.RPCURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rpc := rpcResponse{}
+	if err := json.Unmarshal(body, &rpc); err != nil {
+		return nil, fmt.Errorf("could not unmarshal rpc response: %w", err)
+	}
+
+	if rpc.Error != nil {
+		return nil, fmt.Errorf("rpc error %d: %s", rpc.Error.Code, rpc.Error.Message)
+	}
+
+	return rpc.Result, nil
+}
+
+// HTTP get request to the Etherscan-compatible explorer API, returning its result
+// An empty history is returned as an empty list
+func (c *Client) request(parameters map[string]string) ([]byte, error) {
+	req, err := http.NewRequest("GET", c.ExplorerURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+
+	q := url.Values{}
+	for elem := range parameters {
+		q.Add(elem, parameters[elem])
+	}
+	if c.ExplorerAPIKey != "" {
+		q.Add("apikey", c.ExplorerAPIKey)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	explorer := explorerResponse{}
+	if err := json.Unmarshal(body, &explorer); err != nil {
+		return nil, fmt.Errorf("could not unmarshal explorer response: %w", err)
+	}
+
+	if explorer.Status != "1" {
+		if explorer.Message == "No transactions found" {
+			return []byte("[]"), nil
+		}
+		return nil, fmt.Errorf("explorer error: %s: %s", explorer.Message, explorer.Result)
+	}
+
+	return explorer.Result, nil
+}
+
+// Retry mechanism for HTTP requests
+func (c *Client) withRetries(name string, request func() ([]byte, error)) ([]byte, error) {
+	var body []byte
+	var err error
+	retries := 0
+
+	for {
+		metrics.Request(source)
+		body, err = request()
+		if body != nil {
+			break
+		}
+
+		if retries == c.MaxRetries {
+			log.Warn("Max retries exceeded...")
+			break
+		}
+
+		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
+		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
+		time.Sleep(time.Second * c.RetryDelay)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not request '%s': %w", name, err)
+	}
+
+	return body, err
+}
+
+// Retry mechanism for JSON-RPC calls
+func (c *Client) CallWithRetries(method string, params []interface{}) ([]byte, error) {
+	return c.withRetries(method, func() ([]byte, error) {
+		return c.call(method, params)
+	})
+}
+
+// Retry mechanism for explorer requests
+func (c *Client) RequestWithRetries(parameters map[string]string) ([]byte, error) {
+	return c.withRetries(fmt.Sprintf("%s/%s", parameters["module"], parameters["action"]), func() ([]byte, error) {
+		return c.request(parameters)
+	})
+}
@@ -0,0 +1,172 @@
+// Handles EVM wallets package logic to fetch all data
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type Account struct {
+	Chain                string                `json:"chain"`
+	Address              string                `json:"address"`
+	Balances             map[string]float64    `json:"balances"`
+	Transactions         []ExplorerTransaction `json:"transactions"`
+	InternalTransactions []ExplorerTransaction `json:"internalTransactions"`
+	TokenTransfers       []ExplorerTransaction `json:"tokenTransfers"`
+}
+
+type EVM struct {
+	Chains   *[]Chain
+	Accounts *[]Account
+}
+
+// Create a new EVM object
+func New() *EVM {
+	return &EVM{}
+}
+
+// Create a new Wallet object computed from the EVM ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
+// Fill in token symbols and decimals missing from configuration
+func resolveTokens(client *Client, chain *Chain) error {
+	for i, token := range chain.Tokens {
+		if token.Symbol == "" {
+			symbol, err := client.GetTokenSymbol(token.Contract)
+			if err != nil {
+				return fmt.Errorf("could not get '%s' token symbol: %w", token.Contract, err)
+			}
+			chain.Tokens[i].Symbol = strings.ToUpper(symbol)
+		}
+
+		if token.Decimals == 0 {
+			decimals, err := client.GetTokenDecimals(token.Contract)
+			if err != nil {
+				return fmt.Errorf("could not get '%s' token decimals: %w", token.Contract, err)
+			}
+			chain.Tokens[i].Decimals = decimals
+		}
+	}
+
+	return nil
+}
+
+// Fetch balances and history of an address on a chain
+func fetchAccount(client *Client, chain Chain, address string) (*Account, error) {
+	account := &Account{
+		Chain:    chain.Name,
+		Address:  address,
+		Balances: make(map[string]float64),
+	}
+
+	native, err := client.GetNativeBalance(address)
+	if err != nil {
+		return nil, fmt.Errorf("could not get native balance: %w", err)
+	}
+	account.Balances[chain.NativeAsset] += toFloat(native, nativeDecimals)
+
+	for _, token := range chain.Tokens {
+		balance, err := client.GetTokenBalance(token.Contract, address)
+		if err != nil {
+			return nil, fmt.Errorf("could not get '%s' balance: %w", token.Symbol, err)
+		}
+		account.Balances[token.Symbol] += toFloat(balance, token.Decimals)
+	}
+
+	if account.Transactions, err = client.GetHistory(actionTransactions, address); err != nil {
+		return nil, fmt.Errorf("could not get transactions: %w", err)
+	}
+
+	if account.InternalTransactions, err = client.GetHistory(actionInternalTransactions, address); err != nil {
+		return nil, fmt.Errorf("could not get internal transactions: %w", err)
+	}
+
+	if len(chain.Tokens) > 0 {
+		if account.TokenTransfers, err = client.GetHistory(actionTokenTransfers, address); err != nil {
+			return nil, fmt.Errorf("could not get token transfers: %w", err)
+		}
+	}
+
+	return account, nil
+}
+
+// Convert every account history to ledger transactions
+func (e *EVM) toLedger() []ledger.Transaction {
+	chains := make(map[string]Chain)
+	for _, chain := range *e.Chains {
+		chains[chain.Name] = chain
+	}
+
+	transactions := []ledger.Transaction{}
+	for _, account := range *e.Accounts {
+		transactions = append(transactions, account.toLedger(chains[account.Chain])...)
+	}
+
+	return transactions
+}
+
+// Write all fetched data to files
+func (e *EVM) saveDataToFile() error {
+	if err := utils.WriteToFile("evm_accounts", e.Accounts); err != nil {
+		log.Errorf("Could not write data to evm_accounts: %v", err)
+	} else {
+		metrics.Synced(source, "evm_accounts")
+	}
+
+	if err := ledger.Save(source, e.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve balances and history of all configured EVM addresses
+func (e *EVM) ProcessEVMData(verbose bool) error {
+	log.Info("Starting process EVM data...")
+
+	chains, err := loadChains()
+	if err != nil {
+		return err
+	}
+
+	if len(chains) == 0 {
+		return errors.New("no chain configured under 'wallets.evm.chains'")
+	}
+
+	accounts := []Account{}
+	for i := range chains {
+		client := NewClient(chains[i])
+
+		if err := resolveTokens(client, &chains[i]); err != nil {
+			return err
+		}
+
+		for _, address := range chains[i].Addresses {
+			log.Infof("Fetching %s address %s data...", chains[i].Name, address)
+			account, err := fetchAccount(client, chains[i], address)
+			if err != nil {
+				return err
+			}
+			accounts = append(accounts, *account)
+		}
+	}
+
+	e.Chains = &chains
+	e.Accounts = &accounts
+
+	if verbose {
+		if err := utils.OutputResult(e.Accounts); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	return e.saveDataToFile()
+}
@@ -0,0 +1,145 @@
+// Handles conversion of EVM history to ledger transactions
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	source         = "evm"
+	nativeDecimals = 18
+)
+
+// Convert an amount in base units to a decimal value
+func toFloat(value *big.Int, decimals int) float64 {
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(value), divisor).Float64()
+
+	return f
+}
+
+// Parse an explorer decimal integer
+func parseBig(value string) *big.Int {
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return new(big.Int)
+	}
+
+	return n
+}
+
+// Convert an explorer Unix timestamp to milliseconds
+func parseTime(value string) int64 {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Errorf("Could not parse time: %v", err)
+		return 0
+	}
+
+	return seconds * 1000
+}
+
+// Build a deposit or withdrawal of an address, nothing when it is both sender and receiver
+func transfer(account Account, tx ExplorerTransaction, id string, asset string, amount float64) (ledger.Transaction, bool) {
+	from := strings.EqualFold(tx.From, account.Address)
+	to := strings.EqualFold(tx.To, account.Address)
+	if amount <= 0 || from == to {
+		return ledger.Transaction{}, false
+	}
+
+	entry := ledger.Transaction{
+		ID:     fmt.Sprintf("%s-%s-%s", account.Chain, account.Address, id),
+		Source: source,
+		Type:   ledger.TypeDeposit,
+		Time:   parseTime(tx.TimeStamp),
+		Asset:  asset,
+		Amount: amount,
+		TxHash: tx.Hash,
+	}
+	if from {
+		entry.Type = ledger.TypeWithdraw
+	}
+
+	return entry, true
+}
+
+// Return the ID of an internal transaction, stable whatever the history it is listed in
+// Explorers not giving trace IDs have them told apart by sender, receiver and value
+func internalID(tx ExplorerTransaction) string {
+	if tx.TraceID != "" {
+		return fmt.Sprintf("%s-internal-%s", tx.Hash, tx.TraceID)
+	}
+
+	return fmt.Sprintf("%s-internal-%s-%s-%s", tx.Hash, strings.ToLower(tx.From), strings.ToLower(tx.To), tx.Value)
+}
+
+// Convert the history of an account to ledger transactions
+// Gas is recorded as a fee transaction for every transaction sent, failed ones included
+func (a Account) toLedger(chain Chain) []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+
+	for _, tx := range a.Transactions {
+		if tx.IsError != "1" {
+			if entry, ok := transfer(a, tx, tx.Hash, chain.NativeAsset, toFloat(parseBig(tx.Value), nativeDecimals)); ok {
+				transactions = append(transactions, entry)
+			}
+		}
+
+		if strings.EqualFold(tx.From, a.Address) {
+			gas := new(big.Int).Mul(parseBig(tx.GasUsed), parseBig(tx.GasPrice))
+			if gas.Sign() > 0 {
+				transactions = append(transactions, ledger.Transaction{
+					ID:     fmt.Sprintf("%s-%s-%s-fee", a.Chain, a.Address, tx.Hash),
+					Source: source,
+					Type:   ledger.TypeFee,
+					Time:   parseTime(tx.TimeStamp),
+					Asset:  chain.NativeAsset,
+					Amount: toFloat(gas, nativeDecimals),
+					TxHash: tx.Hash,
+				})
+			}
+		}
+	}
+
+	// Identical internal transactions of a same transaction are numbered, their order not mattering
+	internalIDs := make(map[string]int)
+	for _, tx := range a.InternalTransactions {
+		if tx.IsError == "1" {
+			continue
+		}
+		id := internalID(tx)
+		n := internalIDs[id]
+		internalIDs[id]++
+		if n > 0 {
+			id = fmt.Sprintf("%s-%d", id, n)
+		}
+		if entry, ok := transfer(a, tx, id, chain.NativeAsset, toFloat(parseBig(tx.Value), nativeDecimals)); ok {
+			transactions = append(transactions, entry)
+		}
+	}
+
+	tokens := make(map[string]Token)
+	for _, token := range chain.Tokens {
+		tokens[token.Contract] = token
+	}
+
+	for _, tx := range a.TokenTransfers {
+		// Only configured tokens are tracked, leaving out unsolicited airdrops
+		token, ok := tokens[strings.ToLower(tx.ContractAddress)]
+		if !ok {
+			continue
+		}
+		id := fmt.Sprintf("%s-%s", tx.Hash, tx.LogIndex)
+		if entry, ok := transfer(a, tx, id, strings.ToUpper(token.Symbol), toFloat(parseBig(tx.Value), token.Decimals)); ok {
+			transactions = append(transactions, entry)
+		}
+	}
+
+	return transactions
+}
@@ -0,0 +1,35 @@
+package evm
+
+import "testing"
+
+func TestInternalTransactionIDs(t *testing.T) {
+	chain := Chain{Name: "ethereum", NativeAsset: "ETH"}
+	address := "0x00000000000000000000000000000000000000aa"
+	internal := []ExplorerTransaction{
+		{Hash: "0x1", TimeStamp: "1640995200", From: "0xC0", To: address, Value: "1000000000000000000", TraceID: "0_1"},
+		{Hash: "0x2", TimeStamp: "1640995200", From: "0xc0", To: address, Value: "2000000000000000000"},
+		{Hash: "0x2", TimeStamp: "1640995200", From: "0xc0", To: address, Value: "2000000000000000000"},
+		{Hash: "0x2", TimeStamp: "1640995200", From: "0xc0", To: address, Value: "3000000000000000000"},
+	}
+	want := []string{
+		"ethereum-" + address + "-0x1-internal-0_1",
+		"ethereum-" + address + "-0x2-internal-0xc0-" + address + "-2000000000000000000",
+		"ethereum-" + address + "-0x2-internal-0xc0-" + address + "-2000000000000000000-1",
+		"ethereum-" + address + "-0x2-internal-0xc0-" + address + "-3000000000000000000",
+	}
+
+	// IDs must not depend on the position of internal transactions in the history
+	for _, history := range [][]ExplorerTransaction{internal, {internal[3], internal[2], internal[1], internal[0]}} {
+		account := Account{Chain: chain.Name, Address: address, InternalTransactions: history}
+
+		ids := make(map[string]bool)
+		for _, tx := range account.toLedger(chain) {
+			ids[tx.ID] = true
+		}
+		for _, id := range want {
+			if !ids[id] {
+				t.Errorf("missing transaction '%s', got %v", id, ids)
+			}
+		}
+	}
+}
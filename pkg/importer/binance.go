@@ -0,0 +1,169 @@
+// Handles Binance "Transaction History" export parsing
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+var binanceDepositOperations = map[string]bool{
+	"deposit":      true,
+	"fiat deposit": true,
+}
+
+var binanceWithdrawOperations = map[string]bool{
+	"withdraw":        true,
+	"fiat withdraw":   true,
+	"fiat withdrawal": true,
+}
+
+var binanceIncomeOperations = map[string]bool{
+	"distribution":                           true,
+	"airdrop assets":                         true,
+	"pos savings interest":                   true,
+	"savings interest":                       true,
+	"simple earn flexible interest":          true,
+	"simple earn locked rewards":             true,
+	"staking rewards":                        true,
+	"eth 2.0 staking rewards":                true,
+	"launchpool interest":                    true,
+	"bnb vault rewards":                      true,
+	"commission rebate":                      true,
+	"referral kickback":                      true,
+	"referral commission":                    true,
+	"cash voucher distribution":              true,
+	"mission reward distribution":            true,
+	"crypto box":                             true,
+	"liquid swap rewards":                    true,
+	"super bnb mining":                       true,
+	"defi staking interest":                  true,
+	"locked staking rewards":                 true,
+	"simple earn flexible airdrop":           true,
+	"savings distribution":                   true,
+	"launchpad token distribution":           true,
+	"asset recovery":                         true,
+	"staking purchase interest":              true,
+	"flexible savings interest":              true,
+	"fixed savings interest":                 true,
+	"binance card cashback":                  true,
+	"card cashback":                          true,
+	"token swap - distribution":              true,
+	"token swap - redenomination/rebranding": true,
+}
+
+var binanceTradeOperations = map[string]bool{
+	"buy":                       true,
+	"sell":                      true,
+	"transaction related":       true,
+	"transaction buy":           true,
+	"transaction spend":         true,
+	"transaction revenue":       true,
+	"transaction sold":          true,
+	"binance convert":           true,
+	"large otc trading":         true,
+	"small assets exchange bnb": true,
+	"buy crypto":                true,
+	"auto-invest transaction":   true,
+	"etf subscription":          true,
+	"fee":                       true,
+	"transaction fee":           true,
+}
+
+var binanceFeeOperations = map[string]bool{
+	"fee":             true,
+	"transaction fee": true,
+}
+
+// Parse a Binance "Transaction History" export
+// Columns: User_ID, UTC_Time, Account, Operation, Coin, Change, Remark
+// Internal moves between Spot, Funding and Earn accounts are skipped as they do not change holdings
+func parseBinance(rows []row) ([]ledger.Transaction, error) {
+	transactions := []ledger.Transaction{}
+	seen := make(map[string]int)
+	skipped := make(map[string]int)
+
+	// Trade legs share the same operation time, account and remark, which names the pair when set
+	tradeGroups := make(map[string][]row)
+	groupOrder := []string{}
+
+	for i, r := range rows {
+		operation := strings.ToLower(r.get("Operation"))
+		change := parseFloat(r.get("Change"))
+		coin := strings.ToUpper(r.get("Coin"))
+
+		txTime, err := parseTime(r.get("UTC_Time"), "")
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		base := ledger.Transaction{
+			ID:     generateID("binance", seen, r),
+			Time:   txTime,
+			Asset:  coin,
+			Amount: abs(change),
+		}
+
+		switch {
+		case binanceTradeOperations[operation]:
+			// Fiat card purchases credit the fiat amount before spending it
+			if operation == "buy crypto" && change > 0 && ledger.IsFiat(coin) {
+				base.Type = ledger.TypeDeposit
+				transactions = append(transactions, base)
+				continue
+			}
+
+			key := strings.Join([]string{r.get("UTC_Time"), r.get("Account"), r.get("Remark")}, "|")
+			if _, ok := tradeGroups[key]; !ok {
+				groupOrder = append(groupOrder, key)
+			}
+			tradeGroups[key] = append(tradeGroups[key], r)
+		case binanceDepositOperations[operation]:
+			base.Type = ledger.TypeDeposit
+			transactions = append(transactions, base)
+		case binanceWithdrawOperations[operation]:
+			base.Type = ledger.TypeWithdraw
+			transactions = append(transactions, base)
+		case binanceIncomeOperations[operation] && change > 0:
+			base.Type = ledger.TypeIncome
+			transactions = append(transactions, base)
+		default:
+			skipped[r.get("Operation")]++
+		}
+	}
+
+	for _, key := range groupOrder {
+		group := tradeGroups[key]
+
+		txTime, _ := parseTime(group[0].get("UTC_Time"), "")
+		base := ledger.Transaction{
+			ID:   generateID("binance", seen, group...),
+			Time: txTime,
+		}
+
+		legs, fees := []leg{}, []leg{}
+		for _, r := range group {
+			l := leg{asset: strings.ToUpper(r.get("Coin")), amount: parseFloat(r.get("Change"))}
+			if binanceFeeOperations[strings.ToLower(r.get("Operation"))] {
+				fees = append(fees, l)
+			} else {
+				legs = append(legs, l)
+			}
+		}
+
+		paired, err := pairLegs(base, legs, fees)
+		if err != nil {
+			log.Warnf("Skipping %s %s trade, add it manually: %v", group[0].get("UTC_Time"), group[0].get("Account"), err)
+			continue
+		}
+		transactions = append(transactions, paired...)
+	}
+
+	for operation, count := range skipped {
+		log.Debugf("Skipped %d '%s' operations", count, operation)
+	}
+
+	return transactions, nil
+}
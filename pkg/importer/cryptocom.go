@@ -0,0 +1,134 @@
+// Handles Crypto.com app transactions export parsing
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+var cryptoComIncomeKinds = map[string]bool{
+	"crypto_earn_interest_paid":           true,
+	"crypto_earn_extra_interest_paid":     true,
+	"referral_card_cashback":              true,
+	"referral_bonus":                      true,
+	"referral_gift":                       true,
+	"reimbursement":                       true,
+	"mco_stake_reward":                    true,
+	"supercharger_reward_to_app_credited": true,
+	"rewards_platform_deposit_credited":   true,
+	"admin_wallet_credited":               true,
+	"staking_reward":                      true,
+	"airdrop_to_exchange_transfer":        true,
+}
+
+var cryptoComFeeKinds = map[string]bool{
+	"card_cashback_reverted": true,
+	"reimbursement_reverted": true,
+}
+
+var cryptoComDustKinds = map[string]bool{
+	"dust_conversion_debited":  true,
+	"dust_conversion_credited": true,
+}
+
+// Parse a Crypto.com app transactions export
+// Columns: Timestamp (UTC), Transaction Description, Currency, Amount, To Currency, To Amount,
+// Native Currency, Native Amount, Native Amount (in USD), Transaction Kind
+func parseCryptoCom(rows []row) ([]ledger.Transaction, error) {
+	transactions := []ledger.Transaction{}
+	seen := make(map[string]int)
+	dustGroups := make(map[string][]row)
+	dustOrder := []string{}
+
+	for i, r := range rows {
+		kind := strings.ToLower(r.get("Transaction Kind"))
+		currency := strings.ToUpper(r.get("Currency"))
+		amount := parseFloat(r.get("Amount"))
+		toCurrency := strings.ToUpper(r.get("To Currency"))
+		toAmount := parseFloat(r.get("To Amount"))
+		nativeCurrency := strings.ToUpper(r.get("Native Currency"))
+		nativeAmount := abs(parseFloat(r.get("Native Amount")))
+
+		txTime, err := parseTime(r.get("Timestamp (UTC)"), "")
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		base := ledger.Transaction{
+			ID:     generateID("cryptocom", seen, r),
+			Time:   txTime,
+			Asset:  currency,
+			Amount: abs(amount),
+		}
+
+		switch {
+		case kind == "crypto_purchase":
+			// Card purchases are a fiat deposit immediately spent on a buy
+			deposit := base
+			deposit.ID = fmt.Sprintf("%s-deposit", base.ID)
+			deposit.Type, deposit.Asset, deposit.Amount = ledger.TypeDeposit, nativeCurrency, nativeAmount
+			buy := base
+			buy.Type, buy.QuoteAsset, buy.QuoteAmount = ledger.TypeBuy, nativeCurrency, nativeAmount
+			transactions = append(transactions, deposit, buy)
+		case kind == "viban_purchase", kind == "crypto_exchange", kind == "crypto_viban_exchange":
+			transactions = append(transactions, tradeFromLegs(
+				base,
+				leg{asset: toCurrency}, abs(toAmount),
+				leg{asset: currency}, abs(amount),
+			))
+		case kind == "crypto_deposit", kind == "viban_deposit", kind == "viban_card_top_up":
+			base.Type = ledger.TypeDeposit
+			transactions = append(transactions, base)
+		case kind == "crypto_withdrawal", kind == "viban_withdrawal":
+			base.Type = ledger.TypeWithdraw
+			transactions = append(transactions, base)
+		case kind == "crypto_transfer":
+			base.Type = ledger.TypeDeposit
+			if amount < 0 {
+				base.Type = ledger.TypeWithdraw
+			}
+			transactions = append(transactions, base)
+		case cryptoComIncomeKinds[kind]:
+			base.Type = ledger.TypeIncome
+			transactions = append(transactions, base)
+		case cryptoComFeeKinds[kind]:
+			base.Type = ledger.TypeFee
+			transactions = append(transactions, base)
+		case cryptoComDustKinds[kind]:
+			key := r.get("Timestamp (UTC)")
+			if _, ok := dustGroups[key]; !ok {
+				dustOrder = append(dustOrder, key)
+			}
+			dustGroups[key] = append(dustGroups[key], r)
+		default:
+			log.Debugf("Skipped '%s' transaction", kind)
+		}
+	}
+
+	for _, key := range dustOrder {
+		group := dustGroups[key]
+
+		txTime, _ := parseTime(key, "")
+		base := ledger.Transaction{
+			ID:   generateID("cryptocom", seen, group...),
+			Time: txTime,
+		}
+
+		legs := []leg{}
+		for _, r := range group {
+			legs = append(legs, leg{asset: strings.ToUpper(r.get("Currency")), amount: parseFloat(r.get("Amount"))})
+		}
+
+		paired, err := pairLegs(base, legs, nil)
+		if err != nil {
+			log.Warnf("Skipping %s dust conversion, add it manually: %v", key, err)
+			continue
+		}
+		transactions = append(transactions, paired...)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,448 @@
+// Handles import of transactions from exported files
+package importer
+
+import (
+	"archive/zip"
+	"crypto/sha1" //nolint:gosec // Only used to derive stable transaction ids
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+// A CSV line keyed by lower cased column names
+type row struct {
+	values map[string]string
+	line   string
+}
+
+// Return the first non empty value among column name aliases
+func (r row) get(columns ...string) string {
+	for _, column := range columns {
+		if value := strings.TrimSpace(r.values[strings.ToLower(column)]); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Check if a column exists in the file header
+func (r row) has(column string) bool {
+	_, ok := r.values[strings.ToLower(column)]
+	return ok
+}
+
+type format struct {
+	source      string
+	description string
+	parse       func(rows []row) ([]ledger.Transaction, error)
+}
+
+// Built-in formats, user-defined mappings being loaded from configuration
+var formats = map[string]format{
+	"binance": {
+		source:      "binance",
+		description: "Binance \"Transaction History\" export or statement archive",
+		parse:       parseBinance,
+	},
+	"kucoin": {
+		source:      "kucoin",
+		description: "Kucoin trade, deposit or withdrawal history export",
+		parse:       parseKucoin,
+	},
+	"cryptocom": {
+		source:      "cryptocom",
+		description: "Crypto.com app transactions export",
+		parse:       parseCryptoCom,
+	},
+}
+
+// List available format names with their description
+func Formats() map[string]string {
+	list := map[string]string{
+		genericFormat: "Generic tracklet format",
+	}
+
+	for name, f := range formats {
+		list[name] = f.description
+	}
+
+	mappings, err := loadMappings()
+	if err != nil {
+		log.Warnf("Could not load user mappings: %v", err)
+	}
+	for name := range mappings {
+		list[name] = "User-defined mapping"
+	}
+
+	return list
+}
+
+// Return the sorted list of available format names
+func FormatNames() []string {
+	names := []string{}
+	for name := range Formats() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Read a CSV file into rows, or every CSV file of a zip archive such as Binance statements
+func readFile(filename string, delimiter rune) ([]row, error) {
+	if !strings.EqualFold(path.Ext(filename), ".zip") {
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, fmt.Errorf("could not open file: %w", err)
+		}
+		defer file.Close()
+
+		return readCSV(file, delimiter)
+	}
+
+	archive, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not open archive: %w", err)
+	}
+	defer archive.Close()
+
+	rows := []row{}
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".csv") {
+			log.Debugf("Skipped '%s' archive file", f.Name)
+			continue
+		}
+
+		file, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("could not open '%s' archive file: %w", f.Name, err)
+		}
+
+		fileRows, err := readCSV(file, delimiter)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not read '%s' archive file: %w", f.Name, err)
+		}
+
+		log.Infof("Read %d lines from '%s'", len(fileRows), f.Name)
+		rows = append(rows, fileRows...)
+	}
+
+	return rows, nil
+}
+
+// Read CSV content into rows, using its first line as header
+func readCSV(file io.Reader, delimiter rune) ([]row, error) {
+	reader := csv.NewReader(file)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	rows := []row{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read record: %w", err)
+		}
+
+		r := row{values: make(map[string]string), line: strings.Join(record, ",")}
+		for i, value := range record {
+			if i < len(header) {
+				r.values[header[i]] = value
+			}
+		}
+		rows = append(rows, r)
+	}
+
+	return rows, nil
+}
+
+// Import a CSV file, or a zip archive of CSV files, with a given format into the ledger of its source
+// Returns the number of new transactions
+func ImportCSV(formatName string, filename string, source string) (int, error) {
+	var parse func(rows []row) ([]ledger.Transaction, error)
+	delimiter := ','
+
+	if f, ok := formats[formatName]; ok {
+		parse = f.parse
+		if source == "" {
+			source = f.source
+		}
+	} else {
+		mapping, err := getMapping(formatName)
+		if err != nil {
+			return 0, err
+		}
+
+		parse = mapping.parse
+		if mapping.Delimiter != "" {
+			delimiter = []rune(mapping.Delimiter)[0]
+		}
+		if source == "" {
+			source = mapping.Source
+		}
+	}
+
+	if source == "" {
+		return 0, fmt.Errorf("no source given for format '%s'", formatName)
+	}
+
+	log.Infof("Importing '%s' as %s transactions...", filename, source)
+
+	rows, err := readFile(filename, delimiter)
+	if err != nil {
+		return 0, err
+	}
+
+	transactions, err := parse(rows)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse '%s' file: %w", formatName, err)
+	}
+
+	origin := fmt.Sprintf("csv:%s", formatName)
+	for i := range transactions {
+		transactions[i].Source = source
+		transactions[i].Origin = origin
+	}
+
+	added, err := ledger.Import(source, transactions)
+	if err != nil {
+		return 0, fmt.Errorf("could not import transactions: %w", err)
+	}
+
+	log.Infof("Imported %d new transactions out of %d", added, len(transactions))
+
+	return added, nil
+}
+
+// Derive a stable transaction id from the CSV lines it comes from
+// Identical lines are told apart by their occurrence count
+func generateID(prefix string, seen map[string]int, rows ...row) string {
+	lines := make([]string, 0, len(rows))
+	for _, r := range rows {
+		lines = append(lines, r.line)
+	}
+	content := strings.Join(lines, "\n")
+
+	seen[content]++
+	digest := sha1.Sum([]byte(fmt.Sprintf("%s\n%d\n%s", prefix, seen[content], content))) //nolint:gosec
+
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(digest[:8]))
+}
+
+// Parse a decimal value, accepting comma as decimal separator
+func parseFloat(value string) float64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		f, err = strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+		if err != nil {
+			log.Errorf("Could not convert string to float: %v", err)
+			return 0
+		}
+	}
+
+	return f
+}
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"06-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Parse a UTC date to Unix milliseconds, with an optional layout tried first
+// Numeric values are taken as Unix seconds or milliseconds
+func parseTime(value string, layout string) (int64, error) {
+	value = strings.TrimSpace(value)
+
+	if layout != "" {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+
+	for _, l := range timeLayouts {
+		if t, err := time.Parse(l, value); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if n > 100000000000 {
+			return n, nil
+		}
+		return n * 1000, nil
+	}
+
+	return 0, fmt.Errorf("unknown time format '%s'", value)
+}
+
+// A signed balance change of a single asset
+type leg struct {
+	asset  string
+	amount float64
+}
+
+// Sum legs per asset, keeping their first seen order
+func sumLegs(legs []leg) []leg {
+	index := make(map[string]int)
+	summed := []leg{}
+
+	for _, l := range legs {
+		i, ok := index[l.asset]
+		if !ok {
+			index[l.asset] = len(summed)
+			summed = append(summed, leg{asset: l.asset})
+			i = len(summed) - 1
+		}
+		summed[i].amount += l.amount
+	}
+
+	return summed
+}
+
+// Join the assets of legs, for messages
+func joinAssets(legs []leg) string {
+	assets := []string{}
+	for _, l := range legs {
+		assets = append(assets, l.asset)
+	}
+
+	return strings.Join(assets, ", ")
+}
+
+// Build a trade from a received and a spent leg, selling when fiat is received
+func tradeFromLegs(base ledger.Transaction, received leg, receivedAmount float64, spent leg, spentAmount float64) ledger.Transaction {
+	tx := base
+	if ledger.IsFiat(received.asset) && !ledger.IsFiat(spent.asset) {
+		tx.Type = ledger.TypeSell
+		tx.Asset, tx.Amount = spent.asset, spentAmount
+		tx.QuoteAsset, tx.QuoteAmount = received.asset, receivedAmount
+		return tx
+	}
+
+	tx.Type = ledger.TypeBuy
+	tx.Asset, tx.Amount = received.asset, receivedAmount
+	tx.QuoteAsset, tx.QuoteAmount = spent.asset, spentAmount
+
+	return tx
+}
+
+// Turn the legs of a single operation into trades, received assets being bought with spent ones
+// Legs that cannot be paired are kept as income or fees
+// Several assets traded for one or several others outside dust conversions are rejected,
+// as which amounts were traded together is unknown
+func pairLegs(base ledger.Transaction, legs []leg, fees []leg) ([]ledger.Transaction, error) {
+	// Conversions of several assets to a single one (dust) credit it once per converted asset
+	rawReceived, rawSpent := 0, 0
+	receivedAssets := make(map[string]bool)
+	for _, l := range legs {
+		if l.amount > 0 {
+			rawReceived++
+			receivedAssets[l.asset] = true
+		} else if l.amount < 0 {
+			rawSpent++
+		}
+	}
+	dust := len(receivedAssets) == 1 && rawReceived == rawSpent
+	if !dust {
+		legs = sumLegs(legs)
+	}
+
+	received, spent := []leg{}, []leg{}
+	for _, l := range legs {
+		switch {
+		case l.amount > 0:
+			received = append(received, l)
+		case l.amount < 0:
+			spent = append(spent, leg{asset: l.asset, amount: -l.amount})
+		}
+	}
+
+	transactions := []ledger.Transaction{}
+	var leftReceived, leftSpent []leg
+
+	switch {
+	case len(received) == 0 || len(spent) == 0:
+		leftReceived, leftSpent = received, spent
+	case len(received) == 1 && len(spent) == 1:
+		transactions = append(transactions, tradeFromLegs(base, received[0], received[0].amount, spent[0], spent[0].amount))
+	case len(received) == 1:
+		return nil, fmt.Errorf("%s received for %s, amounts received for each are unknown", received[0].asset, joinAssets(spent))
+	case len(spent) == 1:
+		return nil, fmt.Errorf("%s received for %s, amounts spent on each are unknown", joinAssets(received), spent[0].asset)
+	case !dust:
+		return nil, fmt.Errorf("%s received for %s, which was spent on which is unknown", joinAssets(received), joinAssets(spent))
+	default:
+		n := len(received)
+		if len(spent) < n {
+			n = len(spent)
+		}
+		for i := 0; i < n; i++ {
+			transactions = append(transactions, tradeFromLegs(base, received[i], received[i].amount, spent[i], spent[i].amount))
+		}
+		leftReceived, leftSpent = received[n:], spent[n:]
+	}
+
+	for _, r := range leftReceived {
+		tx := base
+		tx.Type, tx.Asset, tx.Amount = ledger.TypeIncome, r.asset, r.amount
+		transactions = append(transactions, tx)
+	}
+	for _, s := range leftSpent {
+		tx := base
+		tx.Type, tx.Asset, tx.Amount = ledger.TypeFee, s.asset, s.amount
+		transactions = append(transactions, tx)
+	}
+
+	summedFees := sumLegs(fees)
+	if len(summedFees) == 1 && len(transactions) > 0 {
+		transactions[0].FeeAsset = summedFees[0].asset
+		transactions[0].FeeAmount = -summedFees[0].amount
+	} else {
+		for _, f := range summedFees {
+			tx := base
+			tx.Type, tx.Asset, tx.Amount = ledger.TypeFee, f.asset, -f.amount
+			transactions = append(transactions, tx)
+		}
+	}
+
+	for i := range transactions {
+		if len(transactions) > 1 {
+			transactions[i].ID = fmt.Sprintf("%s-%d", base.ID, i)
+		}
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,226 @@
+package importer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/spf13/viper"
+)
+
+// Return the Unix milliseconds time of a UTC date
+func utc(value string) int64 {
+	t, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		panic(err)
+	}
+
+	return t.UnixMilli()
+}
+
+// Parse a fixture file, checking transactions have distinct ids before leaving them out
+func parseFixture(t *testing.T, name string, delimiter rune, parse func(rows []row) ([]ledger.Transaction, error)) []ledger.Transaction {
+	t.Helper()
+
+	rows, err := readFile(filepath.Join("testdata", name), delimiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transactions, err := parse(rows)
+	if err != nil {
+		t.Fatalf("could not parse '%s': %v", name, err)
+	}
+
+	ids := make(map[string]bool)
+	for i := range transactions {
+		if transactions[i].ID == "" || ids[transactions[i].ID] {
+			t.Errorf("transaction %d has an empty or duplicate id '%s'", i, transactions[i].ID)
+		}
+		ids[transactions[i].ID] = true
+		transactions[i].ID = ""
+	}
+
+	return transactions
+}
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		delimiter rune
+		parse     func(rows []row) ([]ledger.Transaction, error)
+		want      []ledger.Transaction
+	}{
+		{
+			name:  "binance",
+			file:  "binance.csv",
+			parse: parseBinance,
+			// Trades come after other operations, ambiguous ones and internal transfers being skipped
+			want: []ledger.Transaction{
+				{Type: ledger.TypeDeposit, Time: utc("2022-01-01 10:00"), Asset: "EUR", Amount: 1000},
+				{Type: ledger.TypeIncome, Time: utc("2022-01-07 10:00"), Asset: "ADA", Amount: 0.5},
+				{Type: ledger.TypeWithdraw, Time: utc("2022-01-09 10:00"), Asset: "BTC", Amount: 0.004},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-02 10:00"), Asset: "BTC", Amount: 0.01, QuoteAsset: "EUR", QuoteAmount: 400, FeeAsset: "BNB", FeeAmount: 0.001},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-02 10:00"), Asset: "ETH", Amount: 0.1, QuoteAsset: "EUR", QuoteAmount: 300},
+				{Type: ledger.TypeSell, Time: utc("2022-01-03 10:00"), Asset: "BTC", Amount: 0.005, QuoteAsset: "EUR", QuoteAmount: 250},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-04 10:00"), Asset: "BNB", Amount: 0.01, QuoteAsset: "ADA", QuoteAmount: 10},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-04 10:00"), Asset: "BNB", Amount: 0.02, QuoteAsset: "DOGE", QuoteAmount: 50},
+			},
+		},
+		{
+			name:  "kucoin trades",
+			file:  "kucoin_trades.csv",
+			parse: parseKucoin,
+			want: []ledger.Transaction{
+				{Type: ledger.TypeBuy, Time: utc("2022-01-02 10:00"), Asset: "BTC", Amount: 0.01, QuoteAsset: "USDT", QuoteAmount: 400, FeeAsset: "USDT", FeeAmount: 0.4},
+				{Type: ledger.TypeSell, Time: utc("2022-01-03 10:00"), Asset: "ETH", Amount: 0.1, QuoteAsset: "BTC", QuoteAmount: 0.007, FeeAsset: "BTC", FeeAmount: 0.0001},
+			},
+		},
+		{
+			name:  "kucoin deposits",
+			file:  "kucoin_deposits.csv",
+			parse: parseKucoin,
+			want: []ledger.Transaction{
+				{Type: ledger.TypeDeposit, Time: utc("2022-01-01 10:00"), Asset: "USDT", Amount: 1000, TxHash: "0xabc"},
+			},
+		},
+		{
+			name:  "kucoin withdrawals",
+			file:  "kucoin_withdrawals.csv",
+			parse: parseKucoin,
+			want: []ledger.Transaction{
+				{Type: ledger.TypeWithdraw, Time: utc("2022-01-09 10:00"), Asset: "BTC", Amount: 0.005, FeeAsset: "BTC", FeeAmount: 0.0005, TxHash: "abc123"},
+			},
+		},
+		{
+			name:  "crypto.com",
+			file:  "cryptocom.csv",
+			parse: parseCryptoCom,
+			want: []ledger.Transaction{
+				{Type: ledger.TypeDeposit, Time: utc("2022-01-02 10:00"), Asset: "EUR", Amount: 400},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-02 10:00"), Asset: "BTC", Amount: 0.01, QuoteAsset: "EUR", QuoteAmount: 400},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-03 10:00"), Asset: "ETH", Amount: 0.05, QuoteAsset: "CRO", QuoteAmount: 100},
+				{Type: ledger.TypeIncome, Time: utc("2022-01-04 10:00"), Asset: "CRO", Amount: 1},
+				{Type: ledger.TypeWithdraw, Time: utc("2022-01-09 10:00"), Asset: "BTC", Amount: 0.005},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-05 10:00"), Asset: "CRO", Amount: 5, QuoteAsset: "ADA", QuoteAmount: 1},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-05 10:00"), Asset: "CRO", Amount: 3, QuoteAsset: "DOGE", QuoteAmount: 2},
+			},
+		},
+		{
+			name:  "generic",
+			file:  "tracklet.csv",
+			parse: genericMapping.parse,
+			want: []ledger.Transaction{
+				{Type: ledger.TypeDeposit, Time: utc("2022-01-01 10:00"), Asset: "EUR", Amount: 1000},
+				{Type: ledger.TypeBuy, Time: utc("2022-01-02 10:00"), Asset: "BTC", Amount: 0.01, QuoteAsset: "EUR", QuoteAmount: 400, FeeAsset: "EUR", FeeAmount: 2},
+				{Type: ledger.TypeWithdraw, Time: utc("2022-01-09 10:00"), Asset: "BTC", Amount: 0.005, FeeAsset: "BTC", FeeAmount: 0.0001, TxHash: "abc123"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delimiter := tt.delimiter
+			if delimiter == 0 {
+				delimiter = ','
+			}
+
+			got := parseFixture(t, tt.file, delimiter, tt.parse)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got transactions\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUserMapping(t *testing.T) {
+	viper.Set("imports.mappings", map[string]interface{}{
+		"mybank": map[string]interface{}{
+			"delimiter":  ";",
+			"timeFormat": "02/01/2006 15:04",
+			"columns":    map[string]string{"time": "Date", "type": "Kind", "asset": "Currency", "amount": "Amount"},
+			"types":      map[string]string{"Versement": "deposit", "Retrait": "withdraw"},
+		},
+	})
+	t.Cleanup(func() { viper.Set("imports.mappings", nil) })
+
+	mapping, err := getMapping("mybank")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping.Source != "mybank" {
+		t.Errorf("mapping source = '%s', want its name", mapping.Source)
+	}
+
+	got := parseFixture(t, "mybank.csv", []rune(mapping.Delimiter)[0], mapping.parse)
+	want := []ledger.Transaction{
+		{Type: ledger.TypeDeposit, Time: utc("2022-01-01 10:00"), Asset: "EUR", Amount: 100.5},
+		{Type: ledger.TypeWithdraw, Time: utc("2022-01-09 10:00"), Asset: "EUR", Amount: 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got transactions\n%+v\nwant\n%+v", got, want)
+	}
+
+	if _, err := getMapping("unknown"); err == nil {
+		t.Error("getMapping() of an unknown format succeeded, want an error")
+	}
+}
+
+func TestPairLegs(t *testing.T) {
+	base := ledger.Transaction{ID: "trade", Time: utc("2022-01-01 10:00")}
+
+	tests := []struct {
+		name    string
+		legs    []leg
+		want    []ledger.Transaction
+		wantErr bool
+	}{
+		{
+			name: "single pair",
+			legs: []leg{{asset: "BTC", amount: 0.01}, {asset: "EUR", amount: -400}},
+			want: []ledger.Transaction{{ID: "trade", Type: ledger.TypeBuy, Time: base.Time, Asset: "BTC", Amount: 0.01, QuoteAsset: "EUR", QuoteAmount: 400}},
+		},
+		{
+			name: "fills paired in order",
+			legs: []leg{{asset: "EUR", amount: 100}, {asset: "BTC", amount: -0.002}, {asset: "EUR", amount: 150}, {asset: "BTC", amount: -0.003}},
+			want: []ledger.Transaction{
+				{ID: "trade-0", Type: ledger.TypeSell, Time: base.Time, Asset: "BTC", Amount: 0.002, QuoteAsset: "EUR", QuoteAmount: 100},
+				{ID: "trade-1", Type: ledger.TypeSell, Time: base.Time, Asset: "BTC", Amount: 0.003, QuoteAsset: "EUR", QuoteAmount: 150},
+			},
+		},
+		{
+			name: "fills summed",
+			legs: []leg{{asset: "EUR", amount: 100}, {asset: "BTC", amount: -0.002}, {asset: "EUR", amount: 150}},
+			want: []ledger.Transaction{{ID: "trade", Type: ledger.TypeSell, Time: base.Time, Asset: "BTC", Amount: 0.002, QuoteAsset: "EUR", QuoteAmount: 250}},
+		},
+		{
+			name:    "one received for several spent",
+			legs:    []leg{{asset: "BTC", amount: 0.01}, {asset: "ETH", amount: -0.1}, {asset: "ADA", amount: -100}},
+			wantErr: true,
+		},
+		{
+			name:    "several received for one spent",
+			legs:    []leg{{asset: "ETH", amount: 0.1}, {asset: "ADA", amount: 100}, {asset: "USDT", amount: -500}},
+			wantErr: true,
+		},
+		{
+			name: "income only",
+			legs: []leg{{asset: "ADA", amount: 1}},
+			want: []ledger.Transaction{{ID: "trade", Type: ledger.TypeIncome, Time: base.Time, Asset: "ADA", Amount: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pairLegs(base, tt.legs, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pairLegs() error = %v, want error %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
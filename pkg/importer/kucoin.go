@@ -0,0 +1,107 @@
+// Handles Kucoin history exports parsing
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+)
+
+// Parse a Kucoin export, detecting trades, deposits or withdrawals from its header
+func parseKucoin(rows []row) ([]ledger.Transaction, error) {
+	if len(rows) == 0 {
+		return []ledger.Transaction{}, nil
+	}
+
+	switch {
+	case rows[0].has("Symbol") && rows[0].has("Side"):
+		return parseKucoinTrades(rows)
+	case rows[0].has("Withdrawal Address/Account"):
+		return parseKucoinTransfers(rows, ledger.TypeWithdraw)
+	default:
+		return parseKucoinTransfers(rows, ledger.TypeDeposit)
+	}
+}
+
+// Parse a Kucoin "Spot Orders - Filled Orders" export
+func parseKucoinTrades(rows []row) ([]ledger.Transaction, error) {
+	transactions := []ledger.Transaction{}
+	seen := make(map[string]int)
+
+	for i, r := range rows {
+		if status := strings.ToLower(r.get("Status")); status != "" && status != "deal" && status != "done" {
+			continue
+		}
+
+		txTime, err := parseTime(r.get("Filled Time(UTC)", "Order Time(UTC)", "Time(UTC)"), "")
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		assets := strings.SplitN(strings.ToUpper(r.get("Symbol")), "-", 2)
+		if len(assets) != 2 {
+			return nil, fmt.Errorf("line %d: unknown symbol '%s'", i+2, r.get("Symbol"))
+		}
+
+		txType := ledger.TypeBuy
+		if strings.EqualFold(r.get("Side"), "sell") {
+			txType = ledger.TypeSell
+		}
+
+		id := r.get("Order ID")
+		if id == "" {
+			id = generateID("kucoin", seen, r)
+		}
+
+		transactions = append(transactions, ledger.Transaction{
+			ID:          id,
+			Type:        txType,
+			Time:        txTime,
+			Asset:       assets[0],
+			Amount:      parseFloat(r.get("Filled Amount", "Amount")),
+			QuoteAsset:  assets[1],
+			QuoteAmount: parseFloat(r.get("Filled Volume", "Volume", "Funds")),
+			FeeAsset:    strings.ToUpper(r.get("Fee Currency")),
+			FeeAmount:   parseFloat(r.get("Fee")),
+		})
+	}
+
+	return transactions, nil
+}
+
+// Parse a Kucoin "Deposit History" or "Withdrawal History" export
+func parseKucoinTransfers(rows []row, txType ledger.Type) ([]ledger.Transaction, error) {
+	transactions := []ledger.Transaction{}
+	seen := make(map[string]int)
+
+	for i, r := range rows {
+		if status := strings.ToLower(r.get("Status")); status != "" && status != "success" {
+			continue
+		}
+
+		txTime, err := parseTime(r.get("Time(UTC)", "Time"), "")
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		coin := strings.ToUpper(r.get("Coin", "Currency"))
+		transaction := ledger.Transaction{
+			ID:     generateID("kucoin", seen, r),
+			Type:   txType,
+			Time:   txTime,
+			Asset:  coin,
+			Amount: abs(parseFloat(r.get("Amount"))),
+			TxHash: r.get("Hash", "Transaction Hash"),
+		}
+
+		if fee := parseFloat(r.get("Fee")); fee > 0 {
+			transaction.FeeAsset = coin
+			transaction.FeeAmount = fee
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,146 @@
+// Handles column mappings of the generic and user-defined formats
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/spf13/viper"
+)
+
+const genericFormat = "tracklet"
+
+// Describe how CSV columns map to ledger transaction fields
+type Mapping struct {
+	Source     string            `mapstructure:"source"`
+	Delimiter  string            `mapstructure:"delimiter"`
+	TimeFormat string            `mapstructure:"timeFormat"`
+	Columns    map[string]string `mapstructure:"columns"`
+	Types      map[string]string `mapstructure:"types"`
+}
+
+// Generic tracklet format, columns being named after ledger fields
+var genericMapping = Mapping{
+	Source: "",
+	Columns: map[string]string{
+		"id":          "id",
+		"time":        "time",
+		"type":        "type",
+		"asset":       "asset",
+		"amount":      "amount",
+		"quoteasset":  "quoteAsset",
+		"quoteamount": "quoteAmount",
+		"feeasset":    "feeAsset",
+		"feeamount":   "feeAmount",
+		"txhash":      "txHash",
+	},
+}
+
+// Load user-defined mappings from configuration
+func loadMappings() (map[string]Mapping, error) {
+	mappings := make(map[string]Mapping)
+	if err := viper.UnmarshalKey("imports.mappings", &mappings); err != nil {
+		return nil, fmt.Errorf("could not unmarshal mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+// Get a user-defined mapping or the generic one
+func getMapping(name string) (*Mapping, error) {
+	if name == genericFormat {
+		return &genericMapping, nil
+	}
+
+	mappings, err := loadMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, ok := mappings[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown format '%s', available formats are: %s", name, strings.Join(FormatNames(), ", "))
+	}
+
+	if mapping.Source == "" {
+		mapping.Source = name
+	}
+
+	return &mapping, nil
+}
+
+// Return the CSV value of a ledger field
+func (m *Mapping) value(r row, field string) string {
+	column, ok := m.Columns[strings.ToLower(field)]
+	if !ok {
+		return ""
+	}
+
+	return r.get(column)
+}
+
+// Translate a CSV type value to a ledger type
+func (m *Mapping) txType(value string) (ledger.Type, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for from, to := range m.Types {
+		if strings.ToLower(from) == value {
+			value = strings.ToLower(to)
+			break
+		}
+	}
+
+	switch t := ledger.Type(value); t {
+	case ledger.TypeBuy, ledger.TypeSell, ledger.TypeDeposit, ledger.TypeWithdraw, ledger.TypeIncome, ledger.TypeFee:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown transaction type '%s'", value)
+	}
+}
+
+// Parse rows, one transaction per line
+func (m *Mapping) parse(rows []row) ([]ledger.Transaction, error) {
+	transactions := []ledger.Transaction{}
+	seen := make(map[string]int)
+
+	for i, r := range rows {
+		txType, err := m.txType(m.value(r, "type"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		txTime, err := parseTime(m.value(r, "time"), m.TimeFormat)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+
+		id := m.value(r, "id")
+		if id == "" {
+			id = generateID("csv", seen, r)
+		}
+
+		transactions = append(transactions, ledger.Transaction{
+			ID:          id,
+			Type:        txType,
+			Time:        txTime,
+			Asset:       strings.ToUpper(m.value(r, "asset")),
+			Amount:      abs(parseFloat(m.value(r, "amount"))),
+			QuoteAsset:  strings.ToUpper(m.value(r, "quoteAsset")),
+			QuoteAmount: abs(parseFloat(m.value(r, "quoteAmount"))),
+			FeeAsset:    strings.ToUpper(m.value(r, "feeAsset")),
+			FeeAmount:   abs(parseFloat(m.value(r, "feeAmount"))),
+			TxHash:      m.value(r, "txHash"),
+		})
+	}
+
+	return transactions, nil
+}
+
+// Ledger amounts are always positive
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+
+	return f
+}
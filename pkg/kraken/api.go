@@ -0,0 +1,160 @@
+// Handles Kraken API endpoints logic
+package kraken
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	assetPairsEndpoint    = "/0/public/AssetPairs"
+	balanceEndpoint       = "/0/private/Balance"
+	ledgersEndpoint       = "/0/private/Ledgers"
+	tradesHistoryEndpoint = "/0/private/TradesHistory"
+	pageSize              = 50
+)
+
+type AssetPairs map[string]struct {
+	Altname string `json:"altname"`
+	Base    string `json:"base"`
+	Quote   string `json:"quote"`
+}
+
+// Get trading pairs available on Kraken
+func GetAssetPairs() (*AssetPairs, error) {
+	client := NewClient()
+	body, err := client.RequestWithRetries(assetPairsEndpoint, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("could not request asset pairs endpoint: %w", err)
+	}
+
+	assetPairs := AssetPairs{}
+	if err := json.Unmarshal(body, &assetPairs); err != nil {
+		return nil, fmt.Errorf("could not unmarshal asset pairs: %w", err)
+	}
+
+	return &assetPairs, nil
+}
+
+type Balance map[string]string
+
+// Get Kraken account balances, keyed by Kraken asset codes
+func GetBalance() (*Balance, error) {
+	client := NewClient()
+	body, err := client.RequestWithRetries(balanceEndpoint, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("could not request balance endpoint: %w", err)
+	}
+
+	balance := Balance{}
+	if err := json.Unmarshal(body, &balance); err != nil {
+		return nil, fmt.Errorf("could not unmarshal balance: %w", err)
+	}
+
+	return &balance, nil
+}
+
+type LedgerEntry struct {
+	RefID   string  `json:"refid"`
+	Time    float64 `json:"time"`
+	Type    string  `json:"type"`
+	Subtype string  `json:"subtype"`
+	Aclass  string  `json:"aclass"`
+	Asset   string  `json:"asset"`
+	Amount  string  `json:"amount"`
+	Fee     string  `json:"fee"`
+	Balance string  `json:"balance"`
+}
+
+type Ledgers struct {
+	Ledger map[string]LedgerEntry `json:"ledger"`
+	Count  int                    `json:"count"`
+}
+
+// Get Kraken ledger entries (deposits, withdrawals, staking, transfers...)
+func GetLedgers() (*Ledgers, error) {
+	client := NewClient()
+	ledgers := Ledgers{Ledger: make(map[string]LedgerEntry)}
+	start := time.Now().AddDate(0, 0, -client.MaxHistory).Unix()
+
+	for offset := 0; ; offset += pageSize {
+		params := map[string]string{
+			"start": fmt.Sprint(start),
+			"ofs":   fmt.Sprint(offset),
+		}
+
+		body, err := client.RequestWithRetries(ledgersEndpoint, params)
+		if err != nil {
+			return nil, fmt.Errorf("could not request ledgers endpoint: %w", err)
+		}
+
+		ledgersPage := Ledgers{}
+		if err := json.Unmarshal(body, &ledgersPage); err != nil {
+			return nil, fmt.Errorf("could not unmarshal ledgers: %w", err)
+		}
+
+		for id, entry := range ledgersPage.Ledger {
+			ledgers.Ledger[id] = entry
+		}
+		ledgers.Count = ledgersPage.Count
+
+		if len(ledgersPage.Ledger) == 0 || offset+pageSize >= ledgersPage.Count {
+			break
+		}
+	}
+
+	return &ledgers, nil
+}
+
+type Trade struct {
+	OrderTxID string  `json:"ordertxid"`
+	Pair      string  `json:"pair"`
+	Time      float64 `json:"time"`
+	Type      string  `json:"type"`
+	OrderType string  `json:"ordertype"`
+	Price     string  `json:"price"`
+	Cost      string  `json:"cost"`
+	Fee       string  `json:"fee"`
+	Volume    string  `json:"vol"`
+}
+
+type TradesHistory struct {
+	Trades map[string]Trade `json:"trades"`
+	Count  int              `json:"count"`
+}
+
+// Get Kraken account trading history
+func GetTradesHistory() (*TradesHistory, error) {
+	client := NewClient()
+	tradesHistory := TradesHistory{Trades: make(map[string]Trade)}
+	start := time.Now().AddDate(0, 0, -client.MaxHistory).Unix()
+
+	for offset := 0; ; offset += pageSize {
+		params := map[string]string{
+			"start": fmt.Sprint(start),
+			"ofs":   fmt.Sprint(offset),
+		}
+
+		body, err := client.RequestWithRetries(tradesHistoryEndpoint, params)
+		if err != nil {
+			return nil, fmt.Errorf("could not request trades history endpoint: %w", err)
+		}
+
+		tradesHistoryPage := TradesHistory{}
+		if err := json.Unmarshal(body, &tradesHistoryPage); err != nil {
+			return nil, fmt.Errorf("could not unmarshal trades history: %w", err)
+		}
+
+		for id, trade := range tradesHistoryPage.Trades {
+			tradesHistory.Trades[id] = trade
+		}
+		tradesHistory.Count = tradesHistoryPage.Count
+
+		if len(tradesHistoryPage.Trades) == 0 || offset+pageSize >= tradesHistoryPage.Count {
+			break
+		}
+	}
+
+	return &tradesHistory, nil
+}
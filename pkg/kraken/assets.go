@@ -0,0 +1,53 @@
+// Handles Kraken asset codes normalization
+package kraken
+
+import "strings"
+
+// Kraken specific codes that do not match the common ticker once their prefix is removed
+var assetAliases = map[string]string{
+	"XBT":  "BTC",
+	"XDG":  "DOGE",
+	"ETH2": "ETH",
+}
+
+// Legacy Kraken codes prefixed with X (crypto) or Z (fiat)
+var prefixedAssets = map[string]bool{
+	"XXBT": true,
+	"XETH": true,
+	"XETC": true,
+	"XLTC": true,
+	"XXRP": true,
+	"XXLM": true,
+	"XXMR": true,
+	"XZEC": true,
+	"XREP": true,
+	"XMLN": true,
+	"XXDG": true,
+	"ZEUR": true,
+	"ZUSD": true,
+	"ZGBP": true,
+	"ZCAD": true,
+	"ZJPY": true,
+	"ZCHF": true,
+	"ZAUD": true,
+}
+
+// Convert a Kraken asset code (XXBT, ZEUR, DOT.S, ETH2.S, XBT.M...) to its common symbol
+func NormalizeAsset(asset string) string {
+	asset = strings.ToUpper(asset)
+
+	// Staking (.S), opt-in rewards (.M), parachain (.P) and flexible (.F) variants hold the same asset
+	if i := strings.Index(asset, "."); i > 0 {
+		asset = asset[:i]
+	}
+
+	if prefixedAssets[asset] {
+		asset = asset[1:]
+	}
+
+	if alias, ok := assetAliases[asset]; ok {
+		return alias
+	}
+
+	return asset
+}
@@ -0,0 +1,177 @@
+// Handles HTTP requests logic
+package kraken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	APIKey     string
+	SecretKey  string
+	RetryDelay time.Duration
+	MaxRetries int
+	MaxHistory int
+}
+
+type apiResponse struct {
+	Error  []string        `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// Create a new Client object
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: time.Second * viper.GetDuration("tracklet.timeout"),
+		},
+		BaseURL:    viper.GetString("exchanges.kraken.apiBaseURL"),
+		APIKey:     viper.GetString("exchanges.kraken.apiKey"),
+		SecretKey:  viper.GetString("exchanges.kraken.secretKey"),
+		RetryDelay: viper.GetDuration("tracklet.retryDelay"),
+		MaxRetries: viper.GetInt("tracklet.maxRetries"),
+		MaxHistory: viper.GetInt("tracklet.maxHistory"),
+	}
+}
+
+// Generate a strictly increasing nonce
+func (c *Client) generateNonce() string {
+	return fmt.Sprint(time.Now().UnixNano() / int64(time.Microsecond))
+}
+
+// Generate a HMAC-SHA512 signature of the URI path and SHA256 digest of nonce and post data
+func (c *Client) generateSignature(path string, nonce string, postData string) (string, error) {
+	secret, err := base64.StdEncoding.DecodeString(c.SecretKey)
+	if err != nil {
+		return "", fmt.Errorf("could not decode secret key: %w", err)
+	}
+
+	sha := sha256.Sum256([]byte(nonce + postData))
+
+	mac := hmac.New(sha512.New, secret)
+	_, err = mac.Write(append([]byte(path), sha[:]...))
+	if err != nil {
+		return "", fmt.Errorf("could not generate hmac: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Build a signed private request, public ones being simple GET requests
+func (c *Client) buildRequest(endpoint string, parameters map[string]string) (*http.Request, error) {
+	values := url.Values{}
+	for elem := range parameters {
+		values.Add(elem, parameters[elem])
+	}
+
+	if !strings.HasPrefix(endpoint, "/0/private/") {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", c.BaseURL, endpoint), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while executing request: %w", err)
+		}
+		req.URL.RawQuery = values.Encode()
+		return req, nil
+	}
+
+	nonce := c.generateNonce()
+	values.Set("nonce", nonce)
+	postData := values.Encode()
+
+	signature, err := c.generateSignature(endpoint, nonce, postData)
+	if err != nil {
+		return nil, fmt.Errorf("error while generating signature: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.BaseURL, endpoint), strings.NewReader(postData))
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+
+	req.Header.Add("API-Key", c.APIKey)
+	req.Header.Add("API-Sign", signature)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	return req, nil
+}
+
+// HTTP request for a given Kraken API endpoint, returning its result
+func (c *Client) request(endpoint string, parameters map[string]string) ([]byte, error) {
+	req, err := c.buildRequest(endpoint, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non valid HTTP status code : %s", response.Status)
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading body: %w", err)
+	}
+
+	// Kraken reports API errors with a 200 status code
+	krakenResponse := apiResponse{}
+	if err := json.Unmarshal(body, &krakenResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response: %w", err)
+	}
+
+	if len(krakenResponse.Error) > 0 {
+		return nil, errors.New(strings.Join(krakenResponse.Error, ", "))
+	}
+
+	return krakenResponse.Result, nil
+}
+
+// Retry mechanism for HTTP requests
+func (c *Client) RequestWithRetries(endpoint string, parameters map[string]string) ([]byte, error) {
+	var body []byte
+	var err error
+	retries := 0
+
+	for {
+		metrics.Request(source)
+		body, err = c.request(endpoint, parameters)
+		if body != nil {
+			break
+		}
+
+		if retries == c.MaxRetries {
+			log.Warn("Max retries exceeded...")
+			break
+		}
+
+		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
+		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
+		time.Sleep(time.Second * c.RetryDelay)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not request '%s' endpoint: %w", endpoint, err)
+	}
+
+	return body, err
+}
@@ -0,0 +1,103 @@
+package kraken
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+const testSecretKey = "kQH5HW/8p1uGOVjbgWA7FunAmGO8lsSUXNsu3eow76sz84Q18fWxnyRzBHCd3pd5nE9qa99HAZtuZuj6F1huXg=="
+
+// Example of the Kraken REST API authentication documentation
+func TestGenerateSignature(t *testing.T) {
+	client := &Client{SecretKey: testSecretKey}
+
+	got, err := client.generateSignature("/0/private/AddOrder", "1616492376594", "nonce=1616492376594&ordertype=limit&pair=XBTUSD&price=37500&type=buy&volume=1.25")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "4/dpxb3iT4tp/ZCVEwSnEsLxx0bqyhLpdfOpc6fn7OR8+UClSV5n9E6aSS8MPtnRfp32bAb0nmbRn6H8ndwLUQ=="
+	if got != want {
+		t.Errorf("generateSignature() = %s, want %s", got, want)
+	}
+}
+
+// Serve ledger entries by pages of pageSize, checking requests are signed
+func ledgersServer(t *testing.T, count int, served int) (*httptest.Server, *int) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		body, _ := ioutil.ReadAll(r.Body)
+		values, _ := url.ParseQuery(string(body))
+		want, _ := (&Client{SecretKey: testSecretKey}).generateSignature(r.URL.Path, values.Get("nonce"), string(body))
+		if r.Method != http.MethodPost || r.Header.Get("API-Key") != "key" || r.Header.Get("API-Sign") != want {
+			t.Errorf("request %s %s is not signed", r.Method, r.URL.Path)
+		}
+
+		offset, _ := strconv.Atoi(values.Get("ofs"))
+		ledger := make(map[string]LedgerEntry)
+		for i := offset; i < offset+pageSize && i < served; i++ {
+			ledger[fmt.Sprintf("L%d", i)] = LedgerEntry{RefID: fmt.Sprintf("R%d", i), Type: "deposit"}
+		}
+		result, _ := json.Marshal(Ledgers{Ledger: ledger, Count: count})
+		_ = json.NewEncoder(w).Encode(apiResponse{Error: []string{}, Result: result})
+	}))
+	t.Cleanup(server.Close)
+
+	viper.Set("exchanges.kraken.apiBaseURL", server.URL)
+	viper.Set("exchanges.kraken.apiKey", "key")
+	viper.Set("exchanges.kraken.secretKey", testSecretKey)
+	viper.Set("tracklet.maxRetries", 0)
+	t.Cleanup(func() {
+		viper.Set("exchanges.kraken.apiBaseURL", nil)
+		viper.Set("exchanges.kraken.apiKey", nil)
+		viper.Set("exchanges.kraken.secretKey", nil)
+		viper.Set("tracklet.maxRetries", nil)
+	})
+
+	return server, &requests
+}
+
+func TestGetLedgersPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        int
+		served       int
+		wantRequests int
+		wantEntries  int
+	}{
+		{name: "single page", count: 10, served: 10, wantRequests: 1, wantEntries: 10},
+		{name: "exact pages", count: 2 * pageSize, served: 2 * pageSize, wantRequests: 2, wantEntries: 2 * pageSize},
+		{name: "last partial page", count: 2*pageSize + 1, served: 2*pageSize + 1, wantRequests: 3, wantEntries: 2*pageSize + 1},
+		{name: "empty", count: 0, served: 0, wantRequests: 1, wantEntries: 0},
+		// Entries leaving the window while paging leave fewer than counted, an empty page ending it
+		{name: "fewer entries than counted", count: 10 * pageSize, served: pageSize + 5, wantRequests: 3, wantEntries: pageSize + 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, requests := ledgersServer(t, tt.count, tt.served)
+
+			ledgers, err := GetLedgers()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", *requests, tt.wantRequests)
+			}
+			if len(ledgers.Ledger) != tt.wantEntries {
+				t.Errorf("got %d entries, want %d", len(ledgers.Ledger), tt.wantEntries)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Handles Kraken package logic to fetch all data
+package kraken
+
+import (
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type Kraken struct {
+	AssetPairs    *AssetPairs
+	Balance       *Balance
+	Ledgers       *Ledgers
+	TradesHistory *TradesHistory
+}
+
+// Create a new Kraken object
+func New() *Kraken {
+	return &Kraken{}
+}
+
+// Create a new Wallet object computed from the Kraken ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
+// Write all fetched data to files
+func (k *Kraken) saveDataToFile() error {
+	if err := utils.WriteToFile("kraken_asset_pairs", k.AssetPairs); err != nil {
+		log.Errorf("Could not write data to kraken_asset_pairs: %v", err)
+	} else {
+		metrics.Synced(source, "kraken_asset_pairs")
+	}
+
+	if err := utils.WriteToFile("kraken_balance", k.Balance); err != nil {
+		log.Errorf("Could not write data to kraken_balance: %v", err)
+	} else {
+		metrics.Synced(source, "kraken_balance")
+	}
+
+	if err := utils.WriteToFile("kraken_ledgers", k.Ledgers); err != nil {
+		log.Errorf("Could not write data to kraken_ledgers: %v", err)
+	} else {
+		metrics.Synced(source, "kraken_ledgers")
+	}
+
+	if err := utils.WriteToFile("kraken_trades_history", k.TradesHistory); err != nil {
+		log.Errorf("Could not write data to kraken_trades_history: %v", err)
+	} else {
+		metrics.Synced(source, "kraken_trades_history")
+	}
+
+	if err := ledger.Save(source, k.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve all account data from Kraken
+func (k *Kraken) ProcessKrakenData(verbose bool) error {
+	log.Info("Starting process Kraken data...")
+
+	// EXCHANGE'S ASSET PAIRS
+	log.Info("Fetching asset pairs data...")
+	assetPairs, err := GetAssetPairs()
+	if err != nil {
+		return err
+	}
+
+	k.AssetPairs = assetPairs
+
+	// BALANCE
+	log.Info("Fetching balance data...")
+	balance, err := GetBalance()
+	if err != nil {
+		return err
+	}
+
+	k.Balance = balance
+
+	if verbose {
+		if err := utils.OutputResult(k.Balance); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// LEDGERS
+	log.Info("Fetching ledgers data...")
+	ledgers, err := GetLedgers()
+	if err != nil {
+		return err
+	}
+
+	k.Ledgers = ledgers
+
+	if verbose {
+		if err := utils.OutputResult(k.Ledgers); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// TRADES HISTORY
+	log.Info("Fetching trades history data...")
+	tradesHistory, err := GetTradesHistory()
+	if err != nil {
+		return err
+	}
+
+	k.TradesHistory = tradesHistory
+
+	if verbose {
+		if err := utils.OutputResult(k.TradesHistory); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	return k.saveDataToFile()
+}
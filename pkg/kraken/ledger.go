@@ -0,0 +1,235 @@
+// Handles conversion of Kraken data to ledger transactions
+package kraken
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const source = "kraken"
+
+// Parse a Kraken decimal string, defaulting to zero
+func parseFloat(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Errorf("Could not convert string to float: %v", err)
+		return 0
+	}
+
+	return f
+}
+
+// Convert Kraken fractional Unix seconds to Unix milliseconds
+func parseTime(value float64) int64 {
+	return int64(value * 1000)
+}
+
+// Return the pair of a trade from asset pairs, older trades being named after the pair alternative name
+func findPair(name string, assetPairs *AssetPairs) (base string, quote string, ok bool) {
+	if assetPairs == nil {
+		return "", "", false
+	}
+
+	pair, ok := (*assetPairs)[name]
+	if !ok {
+		for _, p := range *assetPairs {
+			if p.Altname == name {
+				pair, ok = p, true
+				break
+			}
+		}
+	}
+	if !ok || pair.Base == "" || pair.Quote == "" {
+		return "", "", false
+	}
+
+	return NormalizeAsset(pair.Base), NormalizeAsset(pair.Quote), true
+}
+
+// Return the base and quote assets of a trade from its ledger entries, the base being received on buys and spent on sells
+func pairFromEntries(trade Trade, entries []LedgerEntry) (base string, quote string, ok bool) {
+	var received, spent string
+	for _, entry := range entries {
+		switch amount := parseFloat(entry.Amount); {
+		case amount > 0:
+			received = NormalizeAsset(entry.Asset)
+		case amount < 0:
+			spent = NormalizeAsset(entry.Asset)
+		}
+	}
+	if received == "" || spent == "" {
+		return "", "", false
+	}
+
+	if trade.Type == "sell" {
+		return spent, received, true
+	}
+
+	return received, spent, true
+}
+
+// Convert a trade to a ledger transaction, using asset pairs to split the pair name
+// Trades on a pair missing from asset pairs are rebuilt from their ledger entries, sharing the trade ID as reference
+// The fee is taken from the ledger entry charged with it, Kraken fees being paid in either asset
+// Returns false for trades whose assets could not be found
+func tradeToTransaction(id string, trade Trade, assetPairs *AssetPairs, entries []LedgerEntry) (ledger.Transaction, bool) {
+	base, quote, ok := findPair(trade.Pair, assetPairs)
+	if !ok {
+		base, quote, ok = pairFromEntries(trade, entries)
+	}
+	if !ok {
+		log.Warnf("Skipping Kraken trade '%s' on unknown pair '%s'", id, trade.Pair)
+		return ledger.Transaction{}, false
+	}
+
+	txType := ledger.TypeBuy
+	if trade.Type == "sell" {
+		txType = ledger.TypeSell
+	}
+
+	feeAsset, feeAmount := quote, parseFloat(trade.Fee)
+	for _, entry := range entries {
+		if fee := parseFloat(entry.Fee); fee != 0 {
+			feeAsset, feeAmount = NormalizeAsset(entry.Asset), fee
+			break
+		}
+	}
+
+	return ledger.Transaction{
+		ID:          id,
+		Source:      source,
+		Type:        txType,
+		Time:        parseTime(trade.Time),
+		Asset:       base,
+		Amount:      parseFloat(trade.Volume),
+		QuoteAsset:  quote,
+		QuoteAmount: parseFloat(trade.Cost),
+		FeeAsset:    feeAsset,
+		FeeAmount:   feeAmount,
+	}, true
+}
+
+// Convert a non trade ledger entry to a ledger transaction
+// Returns false for entries that do not change holdings (internal staking transfers, earn allocations...)
+func entryToTransaction(id string, entry LedgerEntry) (ledger.Transaction, bool) {
+	amount := parseFloat(entry.Amount)
+	asset := NormalizeAsset(entry.Asset)
+
+	transaction := ledger.Transaction{
+		ID:     id,
+		Source: source,
+		Time:   parseTime(entry.Time),
+		Asset:  asset,
+		Amount: math.Abs(amount),
+	}
+
+	if fee := parseFloat(entry.Fee); fee > 0 {
+		transaction.FeeAsset = asset
+		transaction.FeeAmount = fee
+	}
+
+	switch entry.Type {
+	case "deposit":
+		transaction.Type = ledger.TypeDeposit
+	case "withdrawal":
+		transaction.Type = ledger.TypeWithdraw
+	case "staking", "dividend":
+		transaction.Type = ledger.TypeIncome
+	case "earn":
+		if entry.Subtype != "reward" {
+			return transaction, false
+		}
+		transaction.Type = ledger.TypeIncome
+	case "transfer":
+		// Spot to staking moves cancel out once assets are normalized, others are airdrops or forks
+		if strings.Contains(entry.Subtype, "staking") || strings.Contains(entry.Subtype, "spot") || amount <= 0 {
+			return transaction, false
+		}
+		transaction.Type = ledger.TypeIncome
+	default:
+		return transaction, false
+	}
+
+	return transaction, true
+}
+
+// Pair instant buy spend and receive ledger entries into trades
+func spendReceiveToTransactions(entries map[string]LedgerEntry) []ledger.Transaction {
+	spends := make(map[string]LedgerEntry)
+	for _, entry := range entries {
+		if entry.Type == "spend" {
+			spends[entry.RefID] = entry
+		}
+	}
+
+	transactions := []ledger.Transaction{}
+	for id, entry := range entries {
+		if entry.Type != "receive" {
+			continue
+		}
+
+		spend, ok := spends[entry.RefID]
+		if !ok {
+			continue
+		}
+
+		spendAsset := NormalizeAsset(spend.Asset)
+		transactions = append(transactions, ledger.Transaction{
+			ID:          id,
+			Source:      source,
+			Type:        ledger.TypeBuy,
+			Time:        parseTime(entry.Time),
+			Asset:       NormalizeAsset(entry.Asset),
+			Amount:      parseFloat(entry.Amount) - parseFloat(entry.Fee),
+			QuoteAsset:  spendAsset,
+			QuoteAmount: math.Abs(parseFloat(spend.Amount)),
+			FeeAsset:    spendAsset,
+			FeeAmount:   parseFloat(spend.Fee),
+		})
+	}
+
+	return transactions
+}
+
+// Convert all fetched Kraken data to ledger transactions
+func (k *Kraken) toLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+
+	// Trade ledger entries reference the trade they belong to
+	tradeEntries := make(map[string][]LedgerEntry)
+	if k.Ledgers != nil {
+		for _, entry := range k.Ledgers.Ledger {
+			if entry.Type == "trade" {
+				tradeEntries[entry.RefID] = append(tradeEntries[entry.RefID], entry)
+			}
+		}
+	}
+
+	if k.TradesHistory != nil {
+		for id, trade := range k.TradesHistory.Trades {
+			if transaction, ok := tradeToTransaction(id, trade, k.AssetPairs, tradeEntries[id]); ok {
+				transactions = append(transactions, transaction)
+			}
+		}
+	}
+
+	if k.Ledgers != nil {
+		for id, entry := range k.Ledgers.Ledger {
+			if transaction, ok := entryToTransaction(id, entry); ok {
+				transactions = append(transactions, transaction)
+			}
+		}
+
+		transactions = append(transactions, spendReceiveToTransactions(k.Ledgers.Ledger)...)
+	}
+
+	return transactions
+}
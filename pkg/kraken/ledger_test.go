@@ -0,0 +1,69 @@
+package kraken
+
+import (
+	"testing"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+)
+
+func TestTradeToTransaction(t *testing.T) {
+	assetPairs := &AssetPairs{
+		"XXBTZEUR": {Altname: "XBTEUR", Base: "XXBT", Quote: "ZEUR"},
+	}
+
+	tests := []struct {
+		name    string
+		trade   Trade
+		entries []LedgerEntry
+		want    ledger.Transaction
+		wantOk  bool
+	}{
+		{
+			name:  "known pair, fee in quote",
+			trade: Trade{Pair: "XXBTZEUR", Time: 1640995200, Type: "buy", Cost: "400", Fee: "1.04", Volume: "0.01"},
+			entries: []LedgerEntry{
+				{RefID: "T1", Type: "trade", Asset: "XXBT", Amount: "0.01", Fee: "0"},
+				{RefID: "T1", Type: "trade", Asset: "ZEUR", Amount: "-400", Fee: "1.04"},
+			},
+			want:   ledger.Transaction{Type: ledger.TypeBuy, Asset: "BTC", Amount: 0.01, QuoteAsset: "EUR", QuoteAmount: 400, FeeAsset: "EUR", FeeAmount: 1.04},
+			wantOk: true,
+		},
+		{
+			name:   "known alternative name without ledger entries",
+			trade:  Trade{Pair: "XBTEUR", Time: 1640995200, Type: "sell", Cost: "200", Fee: "0.52", Volume: "0.005"},
+			want:   ledger.Transaction{Type: ledger.TypeSell, Asset: "BTC", Amount: 0.005, QuoteAsset: "EUR", QuoteAmount: 200, FeeAsset: "EUR", FeeAmount: 0.52},
+			wantOk: true,
+		},
+		{
+			name:  "unknown pair, fee in base",
+			trade: Trade{Pair: "LUNAEUR", Time: 1640995200, Type: "sell", Cost: "50", Fee: "0.13", Volume: "10"},
+			entries: []LedgerEntry{
+				{RefID: "T1", Type: "trade", Asset: "LUNA", Amount: "-10", Fee: "0.026"},
+				{RefID: "T1", Type: "trade", Asset: "ZEUR", Amount: "50", Fee: "0"},
+			},
+			want:   ledger.Transaction{Type: ledger.TypeSell, Asset: "LUNA", Amount: 10, QuoteAsset: "EUR", QuoteAmount: 50, FeeAsset: "LUNA", FeeAmount: 0.026},
+			wantOk: true,
+		},
+		{
+			name:  "unknown pair without ledger entries",
+			trade: Trade{Pair: "LUNAEUR", Time: 1640995200, Type: "buy", Cost: "50", Fee: "0.13", Volume: "10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tradeToTransaction("T1", tt.trade, assetPairs, tt.entries)
+			if ok != tt.wantOk {
+				t.Fatalf("tradeToTransaction() ok = %t, want %t", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+
+			tt.want.ID, tt.want.Source, tt.want.Time = "T1", source, 1640995200000
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
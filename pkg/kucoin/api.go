@@ -3,12 +3,15 @@ package kucoin
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
 )
 
 const (
 	accountsEndpoint        = "/api/v1/accounts"
 	depositHistoryEndpoint  = "/api/v1/deposits"
 	withdrawHistoryEndpoint = "/api/v1/withdrawals"
+	accountLedgersEndpoint  = "/api/v1/accounts/ledgers"
 )
 
 type Pagination struct {
@@ -45,18 +48,22 @@ func GetAccounts() (*Accounts, error) {
 	return &accounts, nil
 }
 
+type Transfer struct {
+	ID         string `json:"id"`
+	Address    string `json:"address"`
+	Amount     string `json:"amount"`
+	Fee        string `json:"fee"`
+	Currency   string `json:"currency"`
+	IsInner    bool   `json:"isInner"`
+	WalletTxID string `json:"walletTxId"`
+	Status     string `json:"status"`
+	CreatedAt  int64  `json:"createdAt"`
+}
+
 type DepositHistory struct {
 	Data struct {
 		Pagination Pagination
-		Items      []struct {
-			Address   string `json:"address"`
-			Amount    string `json:"amount"`
-			Fee       string `json:"fee"`
-			Currency  string `json:"currency"`
-			IsInner   bool   `json:"isInner"`
-			Status    string `json:"status"`
-			CreatedAt int64  `json:"createdAt"`
-		} `json:"items"`
+		Items      []Transfer `json:"items"`
 	} `json:"data"`
 }
 
@@ -79,15 +86,7 @@ func GetDepositHistory() (*DepositHistory, error) {
 type WithdrawHistory struct {
 	Data struct {
 		Pagination Pagination
-		Items      []struct {
-			Address   string `json:"address"`
-			Amount    string `json:"amount"`
-			Fee       string `json:"fee"`
-			Currency  string `json:"currency"`
-			IsInner   bool   `json:"isInner"`
-			Status    string `json:"status"`
-			CreatedAt int64  `json:"createdAt"`
-		} `json:"items"`
+		Items      []Transfer `json:"items"`
 	} `json:"data"`
 }
 
@@ -106,3 +105,59 @@ func GetWithdrawHistory() (*WithdrawHistory, error) {
 
 	return &withdrawHistory, nil
 }
+
+type AccountLedger struct {
+	ID          string `json:"id"`
+	Currency    string `json:"currency"`
+	Amount      string `json:"amount"`
+	Fee         string `json:"fee"`
+	Balance     string `json:"balance"`
+	AccountType string `json:"accountType"`
+	BizType     string `json:"bizType"`
+	Direction   string `json:"direction"`
+	CreatedAt   int64  `json:"createdAt"`
+	Context     string `json:"context"`
+}
+
+type AccountLedgers struct {
+	Data struct {
+		Pagination
+		Items []AccountLedger `json:"items"`
+	} `json:"data"`
+}
+
+// Get account ledgers, Kucoin limiting queries to a day
+func GetAccountLedgers() (*[]AccountLedger, error) {
+	client := NewClient()
+	dateRanges := utils.GetDateRanges(client.MaxHistory, 1)
+	accountLedgers := []AccountLedger{}
+
+	for _, dateRange := range dateRanges {
+		for page := 1; ; page++ {
+			params := map[string]string{
+				"startAt":     fmt.Sprintf("%d", dateRange.StartDate),
+				"endAt":       fmt.Sprintf("%d", dateRange.EndDate),
+				"currentPage": fmt.Sprintf("%d", page),
+				"pageSize":    "500",
+			}
+
+			body, err := client.RequestWithRetries(accountLedgersEndpoint, params)
+			if err != nil {
+				return nil, fmt.Errorf("could not request account ledgers endpoint: %w", err)
+			}
+
+			accountLedgersRange := AccountLedgers{}
+			if err := json.Unmarshal(body, &accountLedgersRange); err != nil {
+				return nil, fmt.Errorf("could not unmarshal account ledgers: %w", err)
+			}
+
+			accountLedgers = append(accountLedgers, accountLedgersRange.Data.Items...)
+
+			if page >= accountLedgersRange.Data.TotalPage {
+				break
+			}
+		}
+	}
+
+	return &accountLedgers, nil
+}
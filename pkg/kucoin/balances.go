@@ -0,0 +1,19 @@
+// Handles live balances retrieval
+package kucoin
+
+// Get live balances per asset, summing every account type
+func GetBalances() (map[string]float64, error) {
+	accounts, err := GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]float64)
+	for _, account := range accounts.Data {
+		if balance := parseFloat(account.Balance); balance != 0 {
+			balances[account.Currency] += balance
+		}
+	}
+
+	return balances, nil
+}
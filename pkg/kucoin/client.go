@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -65,12 +67,19 @@ func (c *Client) request(endpoint string, parameters map[string]string) ([]byte,
 		return nil, fmt.Errorf("error while executing request: %w", err)
 	}
 
+	q := url.Values{}
+	for key, value := range parameters {
+		q.Add(key, value)
+	}
+	req.URL.RawQuery = q.Encode()
+
 	serverTime := c.getServerTime()
 	if err != nil {
 		return nil, fmt.Errorf("error while getting server time: %w", err)
 	}
 
-	signature, err := c.generateSignature(fmt.Sprintf("%d%s%s", serverTime, req.Method, req.URL.Path))
+	// Signed endpoints include their query string
+	signature, err := c.generateSignature(fmt.Sprintf("%d%s%s", serverTime, req.Method, req.URL.RequestURI()))
 	if err != nil {
 		return nil, fmt.Errorf("error while generating signature: %w", err)
 	}
@@ -111,6 +120,7 @@ func (c *Client) RequestWithRetries(endpoint string, parameters map[string]strin
 	retries := 0
 
 	for {
+		metrics.Request(source)
 		body, err = c.request(endpoint, parameters)
 		if body != nil {
 			break
@@ -122,6 +132,7 @@ func (c *Client) RequestWithRetries(endpoint string, parameters map[string]strin
 		}
 
 		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
 		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
 		time.Sleep(time.Second * c.RetryDelay)
 	}
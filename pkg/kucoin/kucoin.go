@@ -2,8 +2,9 @@ package kucoin
 
 import (
 	"fmt"
-	"os"
 
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
 	"github.com/eliasbokreta/tracklet/pkg/utils"
 	log "github.com/sirupsen/logrus"
 )
@@ -12,6 +13,7 @@ type Kucoin struct {
 	Accounts        *Accounts
 	DepositHistory  *DepositHistory
 	WithdrawHistory *WithdrawHistory
+	AccountLedgers  *[]AccountLedger
 }
 
 // Create a new Kucoin object
@@ -19,24 +21,55 @@ func New() *Kucoin {
 	return &Kucoin{}
 }
 
+// Write all fetched data to files
+func (k *Kucoin) saveDataToFile() error {
+	if err := utils.WriteToFile("kucoin_accounts", k.Accounts); err != nil {
+		log.Errorf("Could not write data to kucoin_accounts: %v", err)
+	} else {
+		metrics.Synced(source, "kucoin_accounts")
+	}
+
+	if err := utils.WriteToFile("kucoin_deposit_history", k.DepositHistory); err != nil {
+		log.Errorf("Could not write data to kucoin_deposit_history: %v", err)
+	} else {
+		metrics.Synced(source, "kucoin_deposit_history")
+	}
+
+	if err := utils.WriteToFile("kucoin_withdraw_history", k.WithdrawHistory); err != nil {
+		log.Errorf("Could not write data to kucoin_withdraw_history: %v", err)
+	} else {
+		metrics.Synced(source, "kucoin_withdraw_history")
+	}
+
+	if err := utils.WriteToFile("kucoin_account_ledgers", k.AccountLedgers); err != nil {
+		log.Errorf("Could not write data to kucoin_account_ledgers: %v", err)
+	} else {
+		metrics.Synced(source, "kucoin_account_ledgers")
+	}
+
+	if err := ledger.Save(source, k.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
 // Retrieve all account data from Kucoin
-func (k *Kucoin) ProcessKucoinData(verbose bool) {
+func (k *Kucoin) ProcessKucoinData(verbose bool) error {
 	log.Info("Starting process Kucoin data...")
 
 	// EXCHANGE'S ACCOUNTS
 	log.Info("Fetching accounts data...")
 	accounts, err := GetAccounts()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	k.Accounts = accounts
 
 	if verbose {
 		if err := utils.OutputResult(k.Accounts); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
 
@@ -44,16 +77,14 @@ func (k *Kucoin) ProcessKucoinData(verbose bool) {
 	log.Info("Fetching deposit history data...")
 	depositHistory, err := GetDepositHistory()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	k.DepositHistory = depositHistory
 
 	if verbose {
 		if err := utils.OutputResult(k.DepositHistory); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
 
@@ -61,16 +92,31 @@ func (k *Kucoin) ProcessKucoinData(verbose bool) {
 	log.Info("Fetching withdraw history data...")
 	withdrawHistory, err := GetWithdrawHistory()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
 	k.WithdrawHistory = withdrawHistory
 
 	if verbose {
 		if err := utils.OutputResult(k.WithdrawHistory); err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// ACCOUNT LEDGERS
+	log.Info("Fetching account ledgers data...")
+	accountLedgers, err := GetAccountLedgers()
+	if err != nil {
+		return err
+	}
+
+	k.AccountLedgers = accountLedgers
+
+	if verbose {
+		if err := utils.OutputResult(k.AccountLedgers); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
 		}
 	}
+
+	return k.saveDataToFile()
 }
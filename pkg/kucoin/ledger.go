@@ -0,0 +1,110 @@
+// Handles conversion of Kucoin data to ledger transactions
+package kucoin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const source = "kucoin"
+
+// Parse a Kucoin decimal string, defaulting to zero
+func parseFloat(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Errorf("Could not convert string to float: %v", err)
+		return 0
+	}
+
+	return f
+}
+
+// Convert a successful deposit or withdrawal to a ledger transaction
+func transferToTransaction(transfer Transfer, txType ledger.Type) ledger.Transaction {
+	id := transfer.ID
+	if id == "" {
+		id = fmt.Sprintf("%s-%d-%s", txType, transfer.CreatedAt, transfer.Currency)
+	}
+
+	transaction := ledger.Transaction{
+		ID:     id,
+		Source: source,
+		Type:   txType,
+		Time:   transfer.CreatedAt,
+		Asset:  transfer.Currency,
+		Amount: parseFloat(transfer.Amount),
+		TxHash: transfer.WalletTxID,
+	}
+
+	if fee := parseFloat(transfer.Fee); fee > 0 {
+		transaction.FeeAsset = transfer.Currency
+		transaction.FeeAmount = fee
+	}
+
+	return transaction
+}
+
+// Account ledger business types crediting rewards, such as lending interest, staking profits and bonuses
+var incomeBizTypes = []string{"interest", "staking", "bonus", "reward", "airdrop"}
+
+// Check if an account ledger entry credits a reward
+func isIncome(entry AccountLedger) bool {
+	if entry.Direction != "in" {
+		return false
+	}
+
+	bizType := strings.ToLower(entry.BizType)
+	for _, incomeBizType := range incomeBizTypes {
+		if strings.Contains(bizType, incomeBizType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Convert all fetched Kucoin data to ledger transactions
+func (k *Kucoin) toLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+
+	if k.DepositHistory != nil {
+		for _, deposit := range k.DepositHistory.Data.Items {
+			if deposit.Status == "SUCCESS" {
+				transactions = append(transactions, transferToTransaction(deposit, ledger.TypeDeposit))
+			}
+		}
+	}
+
+	if k.WithdrawHistory != nil {
+		for _, withdraw := range k.WithdrawHistory.Data.Items {
+			if withdraw.Status == "SUCCESS" {
+				transactions = append(transactions, transferToTransaction(withdraw, ledger.TypeWithdraw))
+			}
+		}
+	}
+
+	if k.AccountLedgers != nil {
+		for _, entry := range *k.AccountLedgers {
+			if isIncome(entry) {
+				transactions = append(transactions, ledger.Transaction{
+					ID:     entry.ID,
+					Source: source,
+					Type:   ledger.TypeIncome,
+					Time:   entry.CreatedAt,
+					Asset:  entry.Currency,
+					Amount: parseFloat(entry.Amount),
+				})
+			}
+		}
+	}
+
+	return transactions
+}
@@ -0,0 +1,180 @@
+// Handles classification of assets
+package ledger
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Class string
+
+const (
+	ClassFiat       Class = "fiat"
+	ClassStablecoin Class = "stablecoin"
+	ClassCrypto     Class = "crypto"
+	ClassDerivative Class = "derivative"
+)
+
+// Class of an asset
+// Base is the fiat currency a stablecoin is pegged to, or the asset a derivative token tracks one for one when known
+type AssetClass struct {
+	Class Class  `json:"class"`
+	Base  string `json:"base,omitempty"`
+}
+
+var stablecoins = map[string]string{
+	"USDT":  "USD",
+	"USDC":  "USD",
+	"BUSD":  "USD",
+	"DAI":   "USD",
+	"TUSD":  "USD",
+	"USDP":  "USD",
+	"FDUSD": "USD",
+	"PYUSD": "USD",
+	"GUSD":  "USD",
+	"EURT":  "EUR",
+	"EURC":  "EUR",
+	"EUROC": "EUR",
+	"EURS":  "EUR",
+}
+
+// Wrapped and liquid staking tokens redeemable one for one
+var derivatives = map[string]string{
+	"WBTC":  "BTC",
+	"WETH":  "ETH",
+	"BETH":  "ETH",
+	"STETH": "ETH",
+	"WBNB":  "BNB",
+}
+
+var (
+	configuredClasses     map[string]AssetClass
+	configuredClassesOnce sync.Once
+)
+
+// Coingecko coin IDs by upper case symbol, telling derivatives apart from coins
+type Coins map[string]string
+
+// Index the Coingecko coin list by symbol, the first listed coin winning as when retrieving current prices
+func NewCoins(coinList *coingecko.CoinList) Coins {
+	coins := make(Coins)
+	for _, coin := range coinList.Coins {
+		symbol := strings.ToUpper(coin.Symbol)
+		if _, ok := coins[symbol]; !ok {
+			coins[symbol] = coin.ID
+		}
+	}
+
+	return coins
+}
+
+// Return the Coingecko ID of an asset
+func (c Coins) ID(asset string) (string, bool) {
+	id, ok := c[strings.ToUpper(asset)]
+
+	return id, ok
+}
+
+// Return the class of an asset, Binance Earn positions being told apart from coins
+func (c Coins) Classify(asset string) AssetClass {
+	return classify(asset, c)
+}
+
+// Return the asset whose price gives the value of an asset, derivatives being valued as the asset they track
+func (c Coins) PricedAsset(asset string) string {
+	class := c.Classify(asset)
+	if class.Class == ClassDerivative && class.Base != "" {
+		return class.Base
+	}
+
+	return asset
+}
+
+// Parse asset classes set in configuration as `class` or `class:base`, by asset
+func loadConfiguredClasses() {
+	configuredClasses = make(map[string]AssetClass)
+
+	for asset, value := range viper.GetStringMapString("assets.classes") {
+		parts := strings.SplitN(value, ":", 2)
+		class := AssetClass{Class: Class(strings.ToLower(parts[0]))}
+		if len(parts) == 2 {
+			class.Base = strings.ToUpper(parts[1])
+		}
+
+		switch class.Class {
+		case ClassFiat, ClassCrypto, ClassDerivative:
+		case ClassStablecoin:
+			if !IsFiat(class.Base) {
+				log.Warnf("Stablecoin '%s' must be pegged to a fiat currency, ignoring it", asset)
+				continue
+			}
+		default:
+			log.Warnf("Unknown class '%s' for asset '%s', ignoring it", class.Class, asset)
+			continue
+		}
+		configuredClasses[strings.ToUpper(asset)] = class
+	}
+}
+
+// Return the class of an asset, configured classes taking precedence over known ones
+// Binance Earn positions can only be told apart from coins knowing them, see Coins.Classify
+func Classify(asset string) AssetClass {
+	return classify(asset, nil)
+}
+
+// Return the class of an asset, with the coin list when known
+func classify(asset string, coins Coins) AssetClass {
+	configuredClassesOnce.Do(loadConfiguredClasses)
+
+	asset = strings.ToUpper(asset)
+	if class, ok := configuredClasses[asset]; ok {
+		return class
+	}
+
+	if IsFiat(asset) {
+		return AssetClass{Class: ClassFiat}
+	}
+	if peg, ok := stablecoins[asset]; ok {
+		return AssetClass{Class: ClassStablecoin, Base: peg}
+	}
+	if base, ok := derivatives[asset]; ok {
+		return AssetClass{Class: ClassDerivative, Base: base}
+	}
+	// Binance Earn positions, such as LDBTC, which are not coins of their own but track one
+	if base := strings.TrimPrefix(asset, "LD"); base != asset && coins[base] != "" && coins[asset] == "" {
+		return AssetClass{Class: ClassDerivative, Base: base}
+	}
+	// Liquidity pool shares
+	if strings.HasSuffix(asset, "-LP") || strings.HasPrefix(asset, "UNI-V") {
+		return AssetClass{Class: ClassDerivative}
+	}
+
+	return AssetClass{Class: ClassCrypto}
+}
+
+// Check if an asset is cash, fiat currencies always being and stablecoins when `assets.stablecoinsAsCash` is set
+func IsCash(asset string) bool {
+	switch Classify(asset).Class {
+	case ClassFiat:
+		return true
+	case ClassStablecoin:
+		return viper.GetBool("assets.stablecoinsAsCash")
+	default:
+		return false
+	}
+}
+
+// Return the fiat currency of a cash asset, stablecoins counting as the currency they are pegged to
+func CashCurrency(asset string) string {
+	class := Classify(asset)
+	if class.Class == ClassStablecoin {
+		return class.Base
+	}
+
+	return strings.ToUpper(asset)
+}
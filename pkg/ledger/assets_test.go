@@ -0,0 +1,41 @@
+package ledger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+)
+
+func TestClassifyBinanceEarn(t *testing.T) {
+	var coinList coingecko.CoinList
+	if err := json.Unmarshal([]byte(`{"Coins": [{"id": "bitcoin", "symbol": "btc"}, {"id": "lido-dao", "symbol": "ldo"}, {"id": "ethereum", "symbol": "eth"}]}`), &coinList); err != nil {
+		t.Fatal(err)
+	}
+	coins := NewCoins(&coinList)
+
+	tests := []struct {
+		asset string
+		class Class
+		base  string
+	}{
+		{asset: "LDBTC", class: ClassDerivative, base: "BTC"},
+		{asset: "LDO", class: ClassCrypto},
+		{asset: "LDXYZ", class: ClassCrypto},
+		{asset: "ETH", class: ClassCrypto},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.asset, func(t *testing.T) {
+			class := coins.Classify(tt.asset)
+			if class.Class != tt.class || class.Base != tt.base {
+				t.Errorf("Classify(%s) = %s %s, want %s %s", tt.asset, class.Class, class.Base, tt.class, tt.base)
+			}
+		})
+	}
+
+	// Without the coin list, LD assets cannot be told apart from coins
+	if class := Classify("LDBTC"); class.Class != ClassCrypto {
+		t.Errorf("Classify(LDBTC) without coins = %s, want %s", class.Class, ClassCrypto)
+	}
+}
@@ -0,0 +1,298 @@
+// Handles cost basis calculation across sources
+package ledger
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Accounting methods choosing which acquisitions are disposed of first
+const (
+	MethodAverage = "average"
+	MethodFIFO    = "fifo"
+	MethodLIFO    = "lifo"
+)
+
+// A quantity acquired at once and what it cost
+type lot struct {
+	quantity float64
+	cost     float64
+}
+
+// Quantity of an asset held in a source and what it cost, in fiat
+type Position struct {
+	Quantity float64 `json:"quantity"`
+	Cost     float64 `json:"cost"`
+	lots     []lot
+}
+
+// A quantity still held from acquisitions and what it cost, in disposal order for FIFO
+type Lot struct {
+	Quantity float64 `json:"quantity"`
+	Cost     float64 `json:"cost"`
+}
+
+// Return the lots of a position, a single one with average cost
+func (p *Position) Lots() []Lot {
+	lots := make([]Lot, len(p.lots))
+	for i, l := range p.lots {
+		lots[i] = Lot{Quantity: l.quantity, Cost: l.cost}
+	}
+
+	return lots
+}
+
+// Positions of every source, by source then asset
+type Positions struct {
+	method    string
+	prices    *PriceHistory
+	sources   map[string]map[string]*Position
+	disposals []Disposal
+	// Assets whose income could not be valued, warned about once
+	unpriced map[string]bool
+}
+
+// Return disposals of crypto realizing a gain or loss, oldest first
+func (p *Positions) Disposals() []Disposal {
+	return p.disposals
+}
+
+// Return the accounting method set in configuration, defaulting to average cost
+func CostBasisMethod() string {
+	method := viper.GetString("tracklet.costBasisMethod")
+	switch method {
+	case MethodAverage, MethodFIFO, MethodLIFO:
+		return method
+	default:
+		log.Warnf("Unknown cost basis method '%s', using '%s'", method, MethodAverage)
+		return MethodAverage
+	}
+}
+
+// Return the positions of a source by asset
+func (p *Positions) Source(source string) map[string]*Position {
+	return p.sources[source]
+}
+
+// Return the position of an asset in a source, creating it if needed
+func (p *Positions) get(source string, asset string) *Position {
+	if p.sources[source] == nil {
+		p.sources[source] = make(map[string]*Position)
+	}
+	if p.sources[source][asset] == nil {
+		p.sources[source][asset] = &Position{}
+	}
+
+	return p.sources[source][asset]
+}
+
+// Add a quantity and its cost to a position, cash not being tracked
+func (p *Positions) add(source string, asset string, quantity float64, cost float64) {
+	if asset == "" || IsCash(asset) || quantity <= 0 {
+		return
+	}
+
+	position := p.get(source, asset)
+	position.Quantity += quantity
+	position.Cost += cost
+
+	if p.method == MethodAverage && len(position.lots) > 0 {
+		position.lots[0].quantity += quantity
+		position.lots[0].cost += cost
+		return
+	}
+	position.lots = append(position.lots, lot{quantity: quantity, cost: cost})
+}
+
+// Remove a quantity from a position, returning its cost according to the accounting method
+// Quantities exceeding the position, from missing history, cost nothing
+func (p *Positions) remove(source string, asset string, quantity float64) float64 {
+	if asset == "" || IsCash(asset) || quantity <= 0 {
+		return 0
+	}
+
+	position := p.get(source, asset)
+	position.Quantity -= quantity
+
+	cost := 0.0
+	for quantity > 0 && len(position.lots) > 0 {
+		i := 0
+		if p.method == MethodLIFO {
+			i = len(position.lots) - 1
+		}
+		l := &position.lots[i]
+
+		if l.quantity > quantity {
+			share := l.cost * quantity / l.quantity
+			l.quantity -= quantity
+			l.cost -= share
+			cost += share
+			break
+		}
+
+		quantity -= l.quantity
+		cost += l.cost
+		position.lots = append(position.lots[:i], position.lots[i+1:]...)
+	}
+
+	position.Cost -= cost
+
+	return cost
+}
+
+// Convert a cash amount to reporting currency, amounts which cannot be converted costing nothing
+func (p *Positions) convert(amount float64, currency string, t int64) float64 {
+	value, err := p.prices.Convert(amount, currency, t)
+	if err != nil {
+		log.Warnf("Could not convert %s cost: %v", currency, err)
+		return 0
+	}
+
+	return value
+}
+
+// Value of income when received, its cost being what it was taxed at as income, income which cannot be valued costing nothing
+func (p *Positions) incomeValue(tx Transaction) float64 {
+	value, err := p.prices.Value(tx)
+	if err != nil {
+		if !p.unpriced[tx.Asset] {
+			log.Warnf("Could not value '%s' income, counting it as free: %v", tx.Asset, err)
+			p.unpriced[tx.Asset] = true
+		}
+		return 0
+	}
+
+	return value
+}
+
+// Record the disposal of an asset quantity for what it cost and brought
+func (p *Positions) dispose(tx Transaction, asset string, quantity float64, cost float64, proceeds float64) {
+	p.disposals = append(p.disposals, Disposal{
+		Time:     tx.Time,
+		Source:   tx.Source,
+		Asset:    asset,
+		Quantity: quantity,
+		Proceeds: proceeds,
+		Cost:     cost,
+		Gain:     proceeds - cost,
+	})
+}
+
+// Value given by a transaction quote, zero when its quote is not cash
+func (p *Positions) fiatValue(tx Transaction) float64 {
+	if IsCash(tx.QuoteAsset) {
+		return p.convert(tx.QuoteAmount, tx.QuoteAsset, tx.Time)
+	}
+
+	return 0
+}
+
+// Calculate cost positions from transactions of all sources
+// Internal transfers carry their cost over to the receiving source, crypto swaps carry the cost of what was spent,
+// income costs its value when received, and other crypto deposits only cost their fiat value when known or assigned
+// Fees paid to acquire, sell or move an asset add to its cost, other fees are lost
+// Sales for cash are recorded as disposals, their proceeds being net of fees paid in the quote
+// Costs are in the currency of the price history, fiat quotes being converted at their day rate
+func CalculateCostBasis(transactions []Transaction, transfers *Transfers, method string, prices *PriceHistory) *Positions {
+	sorted := append([]Transaction{}, transactions...)
+	Sort(sorted)
+
+	positions := &Positions{
+		method:   method,
+		prices:   prices,
+		sources:  make(map[string]map[string]*Position),
+		unpriced: make(map[string]bool),
+	}
+	preloadIncomePrices(sorted, prices)
+
+	for _, tx := range sorted {
+		feeHandled := false
+
+		switch tx.Type {
+		case TypeBuy:
+			cost := positions.fiatValue(tx)
+			if !IsCash(tx.QuoteAsset) {
+				cost = positions.remove(tx.Source, tx.QuoteAsset, tx.QuoteAmount)
+			}
+
+			quantity := tx.Amount
+			switch {
+			case tx.FeeAsset == tx.Asset:
+				quantity -= tx.FeeAmount
+			case IsCash(tx.FeeAsset):
+				cost += positions.convert(tx.FeeAmount, tx.FeeAsset, tx.Time)
+			default:
+				cost += positions.remove(tx.Source, tx.FeeAsset, tx.FeeAmount)
+			}
+			feeHandled = true
+
+			positions.add(tx.Source, tx.Asset, quantity, cost)
+		case TypeSell:
+			cost := positions.remove(tx.Source, tx.Asset, tx.Amount)
+
+			// Fees reduce the proceeds, or add to the cost of what was sold when paid in another asset
+			quantity := tx.QuoteAmount
+			switch {
+			case tx.FeeAsset == tx.QuoteAsset:
+				quantity -= tx.FeeAmount
+			case IsCash(tx.FeeAsset):
+				cost += positions.convert(tx.FeeAmount, tx.FeeAsset, tx.Time)
+			default:
+				cost += positions.remove(tx.Source, tx.FeeAsset, tx.FeeAmount)
+			}
+			feeHandled = true
+
+			// Only sales for cash realize a gain, swaps carrying their cost over
+			if IsCash(tx.QuoteAsset) {
+				positions.dispose(tx, tx.Asset, tx.Amount, cost, positions.convert(quantity, tx.QuoteAsset, tx.Time))
+			}
+			positions.add(tx.Source, tx.QuoteAsset, quantity, cost)
+		case TypeDeposit:
+			// Matched deposits are handled along with their withdrawal
+			if transfers.Find(tx) == nil {
+				positions.add(tx.Source, tx.Asset, tx.Amount, positions.fiatValue(tx))
+			}
+		case TypeWithdraw:
+			transfer := transfers.Find(tx)
+			if transfer == nil {
+				positions.remove(tx.Source, tx.Asset, tx.Amount)
+				break
+			}
+
+			quantity := tx.Amount
+			if tx.FeeAsset == tx.Asset {
+				quantity += tx.FeeAmount
+				feeHandled = true
+			}
+			cost := positions.remove(tx.Source, tx.Asset, quantity)
+			positions.add(transfer.Deposit.Source, transfer.Deposit.Asset, transfer.Deposit.Amount, cost)
+		case TypeIncome:
+			positions.add(tx.Source, tx.Asset, tx.Amount, positions.incomeValue(tx))
+		case TypeFee:
+			positions.remove(tx.Source, tx.Asset, tx.Amount)
+		}
+
+		if !feeHandled {
+			positions.remove(tx.Source, tx.FeeAsset, tx.FeeAmount)
+		}
+	}
+
+	return positions
+}
+
+// Calculate the cost basis of a source assets from all ledgers, with the configured accounting method
+func SourceCostBasis(source string, prices *PriceHistory) (map[string]float64, error) {
+	transactions, err := LoadAllWithCosts()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := CalculateCostBasis(transactions, MatchTransfers(transactions), CostBasisMethod(), prices)
+
+	costs := make(map[string]float64)
+	for asset, position := range positions.Source(source) {
+		costs[asset] = position.Cost
+	}
+
+	return costs, nil
+}
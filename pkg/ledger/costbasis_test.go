@@ -0,0 +1,174 @@
+package ledger
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Return a price history in EUR from known prices only, keyed as in the price cache, and coin IDs by asset
+func testPrices(coins map[string]string, prices map[string]float64) *PriceHistory {
+	history := &PriceHistory{
+		currency: "EUR",
+		coins:    make(map[string]string),
+		prices:   make(map[string]float64),
+		errors:   make(map[string]error),
+	}
+	for asset, id := range coins {
+		history.coins[asset] = id
+	}
+	for key, price := range prices {
+		history.prices[key] = price
+	}
+
+	return history
+}
+
+// Return the Unix milliseconds time of a day at an hour
+func testTime(day string, hour int) int64 {
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		panic(err)
+	}
+
+	return t.Add(time.Duration(hour) * time.Hour).UnixMilli()
+}
+
+func almostEqual(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+// Buys on kraken, a transfer to binance with a withdrawal fee, then a sale on each
+func testTransactions() []Transaction {
+	return []Transaction{
+		{ID: "1", Source: "kraken", Type: TypeBuy, Time: testTime("2022-01-01", 0), Asset: "BTC", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 10000, FeeAsset: "EUR", FeeAmount: 10},
+		{ID: "2", Source: "kraken", Type: TypeBuy, Time: testTime("2022-02-01", 0), Asset: "BTC", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 20000},
+		{ID: "3", Source: "kraken", Type: TypeWithdraw, Time: testTime("2022-03-01", 0), Asset: "BTC", Amount: 0.5, FeeAsset: "BTC", FeeAmount: 0.001},
+		{ID: "4", Source: "binance", Type: TypeDeposit, Time: testTime("2022-03-01", 1), Asset: "BTC", Amount: 0.5},
+		{ID: "5", Source: "kraken", Type: TypeSell, Time: testTime("2022-04-01", 0), Asset: "BTC", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 30000, FeeAsset: "EUR", FeeAmount: 30},
+		{ID: "6", Source: "binance", Type: TypeSell, Time: testTime("2022-05-01", 0), Asset: "BTC", Amount: 0.5, QuoteAsset: "EUR", QuoteAmount: 16000},
+	}
+}
+
+func setTransferConfig(t *testing.T) {
+	viper.Set("transfers.window", 24)
+	viper.Set("transfers.amountTolerance", 1)
+	t.Cleanup(func() {
+		viper.Set("transfers.window", nil)
+		viper.Set("transfers.amountTolerance", nil)
+	})
+}
+
+func TestCalculateCostBasis(t *testing.T) {
+	setTransferConfig(t)
+
+	tests := []struct {
+		method string
+		// Cost and gain of the kraken then the binance sale
+		costs []float64
+		gains []float64
+		// Cost of the BTC left on kraken
+		remaining float64
+	}{
+		{
+			method:    MethodFIFO,
+			costs:     []float64{0.499*10010 + 0.501*20000, 0.501 * 10010},
+			gains:     []float64{29970 - 0.499*10010 - 0.501*20000, 16000 - 0.501*10010},
+			remaining: 0.499 * 20000,
+		},
+		{
+			method:    MethodLIFO,
+			costs:     []float64{0.499*20000 + 0.501*10010, 0.501 * 20000},
+			gains:     []float64{29970 - 0.499*20000 - 0.501*10010, 16000 - 0.501*20000},
+			remaining: 0.499 * 10010,
+		},
+		{
+			method:    MethodAverage,
+			costs:     []float64{15005, 0.501 * 15005},
+			gains:     []float64{29970 - 15005, 16000 - 0.501*15005},
+			remaining: 0.499 * 15005,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			transactions := testTransactions()
+			positions := CalculateCostBasis(transactions, MatchTransfers(transactions), tt.method, testPrices(nil, nil))
+
+			disposals := positions.Disposals()
+			if len(disposals) != 2 {
+				t.Fatalf("got %d disposals, want 2", len(disposals))
+			}
+			for i, disposal := range disposals {
+				if !almostEqual(disposal.Cost, tt.costs[i]) {
+					t.Errorf("disposal %d cost = %f, want %f", i, disposal.Cost, tt.costs[i])
+				}
+				if !almostEqual(disposal.Gain, tt.gains[i]) {
+					t.Errorf("disposal %d gain = %f, want %f", i, disposal.Gain, tt.gains[i])
+				}
+			}
+			if !almostEqual(disposals[0].Proceeds, 29970) {
+				t.Errorf("kraken proceeds = %f, want 29970, net of the fee", disposals[0].Proceeds)
+			}
+
+			kraken := positions.Source("kraken")["BTC"]
+			if !almostEqual(kraken.Quantity, 0.499) || !almostEqual(kraken.Cost, tt.remaining) {
+				t.Errorf("kraken position = %f BTC for %f, want 0.499 BTC for %f", kraken.Quantity, kraken.Cost, tt.remaining)
+			}
+			binance := positions.Source("binance")["BTC"]
+			if !almostEqual(binance.Quantity, 0) || !almostEqual(binance.Cost, 0) {
+				t.Errorf("binance position = %f BTC for %f, want nothing left", binance.Quantity, binance.Cost)
+			}
+		})
+	}
+}
+
+func TestCalculateCostBasisFees(t *testing.T) {
+	buyBTC := Transaction{ID: "1", Source: "binance", Type: TypeBuy, Time: testTime("2022-01-01", 0), Asset: "BTC", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 10000}
+	buyBNB := Transaction{ID: "2", Source: "binance", Type: TypeBuy, Time: testTime("2022-01-01", 1), Asset: "BNB", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 300}
+	sale := Transaction{ID: "3", Source: "binance", Type: TypeSell, Time: testTime("2022-04-01", 0), Asset: "BTC", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 12000}
+	// 1 USD is worth 0.9 EUR on the sale day
+	prices := testPrices(nil, map[string]float64{
+		"bitcoin/2022-04-01/usd": 40000,
+		"bitcoin/2022-04-01/eur": 36000,
+	})
+
+	tests := []struct {
+		name      string
+		feeAsset  string
+		feeAmount float64
+		proceeds  float64
+		cost      float64
+		bnbLeft   float64
+	}{
+		{name: "fee in quote", feeAsset: "EUR", feeAmount: 12, proceeds: 11988, cost: 10000, bnbLeft: 1},
+		{name: "fee in a third asset", feeAsset: "BNB", feeAmount: 0.1, proceeds: 12000, cost: 10030, bnbLeft: 0.9},
+		{name: "fee in another currency", feeAsset: "USD", feeAmount: 10, proceeds: 12000, cost: 10009, bnbLeft: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sold := sale
+			sold.FeeAsset, sold.FeeAmount = tt.feeAsset, tt.feeAmount
+			positions := CalculateCostBasis([]Transaction{buyBTC, buyBNB, sold}, nil, MethodFIFO, prices)
+
+			disposals := positions.Disposals()
+			if len(disposals) != 1 {
+				t.Fatalf("got %d disposals, want 1", len(disposals))
+			}
+			if !almostEqual(disposals[0].Proceeds, tt.proceeds) || !almostEqual(disposals[0].Cost, tt.cost) {
+				t.Errorf("disposal = %f for %f, want %f for %f", disposals[0].Proceeds, disposals[0].Cost, tt.proceeds, tt.cost)
+			}
+			if !almostEqual(disposals[0].Gain, tt.proceeds-tt.cost) {
+				t.Errorf("gain = %f, want %f", disposals[0].Gain, tt.proceeds-tt.cost)
+			}
+
+			bnb := positions.Source("binance")["BNB"]
+			if !almostEqual(bnb.Quantity, tt.bnbLeft) || !almostEqual(bnb.Cost, 300*tt.bnbLeft) {
+				t.Errorf("BNB position = %f for %f, want %f for %f", bnb.Quantity, bnb.Cost, tt.bnbLeft, 300*tt.bnbLeft)
+			}
+		})
+	}
+}
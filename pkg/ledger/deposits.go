@@ -0,0 +1,120 @@
+// Handles crypto deposits coming from outside tracked sources
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+)
+
+const depositCostsFile = "deposit_costs"
+
+// Cost assigned by the user to an external deposit, in the fiat currency it was given in
+type DepositCost struct {
+	Cost     float64 `json:"cost"`
+	Currency string  `json:"currency"`
+}
+
+// Costs assigned by the user to external deposits, by source and transaction ID
+type DepositCosts map[string]DepositCost
+
+// Load assigned deposit costs, none being assigned when the file does not exist yet
+func LoadDepositCosts() (DepositCosts, error) {
+	costs := make(DepositCosts)
+	if !utils.FileExists(fmt.Sprintf("%s.json", depositCostsFile)) {
+		return costs, nil
+	}
+
+	data := utils.LoadFromFile(fmt.Sprintf("%s.json", depositCostsFile))
+	if err := json.Unmarshal(data, &costs); err != nil {
+		return nil, fmt.Errorf("could not unmarshal deposit costs: %w", err)
+	}
+
+	return costs, nil
+}
+
+// Write assigned deposit costs to file
+func (c DepositCosts) Save() error {
+	if err := utils.WriteToFile(depositCostsFile, c); err != nil {
+		return fmt.Errorf("could not save deposit costs: %w", err)
+	}
+
+	return nil
+}
+
+// Assign a cost in a fiat currency to a deposit
+func (c DepositCosts) Assign(tx Transaction, cost float64, currency string) {
+	c[key(tx)] = DepositCost{Cost: cost, Currency: strings.ToUpper(currency)}
+}
+
+// Return transactions with assigned costs set as the fiat value of their deposits
+// Costs keep their currency, being converted at the deposit day rate as other fiat quotes
+func (c DepositCosts) Apply(transactions []Transaction) []Transaction {
+	applied := make([]Transaction, len(transactions))
+	for i, tx := range transactions {
+		if cost, ok := c[key(tx)]; ok && tx.Type == TypeDeposit {
+			tx.QuoteAsset = cost.Currency
+			tx.QuoteAmount = cost.Cost
+		}
+		applied[i] = tx
+	}
+
+	return applied
+}
+
+// Return crypto deposits, cash excluded, not matched to a withdrawal from another tracked source
+func ExternalDeposits(transactions []Transaction, transfers *Transfers) []Transaction {
+	deposits := []Transaction{}
+	for _, tx := range transactions {
+		if tx.Type == TypeDeposit && !IsCash(tx.Asset) && transfers.Find(tx) == nil {
+			deposits = append(deposits, tx)
+		}
+	}
+
+	return deposits
+}
+
+// Load all ledgers with assigned deposit costs applied
+func LoadAllWithCosts() ([]Transaction, error) {
+	transactions, err := LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not load ledgers: %w", err)
+	}
+
+	costs, err := LoadDepositCosts()
+	if err != nil {
+		return nil, err
+	}
+
+	return costs.Apply(transactions), nil
+}
+
+// Sum the value of external crypto deposits of a source, in the currency of the price history
+// Returns as well the number of deposits with an unknown value, counting as zero
+func DepositedValue(source string, prices *PriceHistory) (float64, int, error) {
+	transactions, err := LoadAllWithCosts()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	value, unknown := 0.0, 0
+	for _, tx := range ExternalDeposits(transactions, MatchTransfers(transactions)) {
+		if tx.Source != source {
+			continue
+		}
+		if !IsCash(tx.QuoteAsset) {
+			unknown++
+			continue
+		}
+
+		converted, err := prices.Convert(tx.QuoteAmount, tx.QuoteAsset, tx.Time)
+		if err != nil {
+			return 0, 0, fmt.Errorf("could not convert deposit value: %w", err)
+		}
+		value += converted
+	}
+
+	return value, unknown, nil
+}
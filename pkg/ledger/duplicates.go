@@ -0,0 +1,132 @@
+// Handles detection of transactions known from several origins
+package ledger
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// A signed balance change of a single asset
+type movement struct {
+	asset  string
+	amount float64
+}
+
+// Return the balance changes of a transaction, fees excluded
+func movements(tx Transaction) []movement {
+	switch tx.Type {
+	case TypeBuy:
+		return []movement{{tx.Asset, tx.Amount}, {tx.QuoteAsset, -tx.QuoteAmount}}
+	case TypeSell:
+		return []movement{{tx.Asset, -tx.Amount}, {tx.QuoteAsset, tx.QuoteAmount}}
+	case TypeDeposit, TypeIncome:
+		return []movement{{tx.Asset, tx.Amount}}
+	case TypeWithdraw, TypeFee:
+		return []movement{{tx.Asset, -tx.Amount}}
+	default:
+		return nil
+	}
+}
+
+// Compare amounts coming from different origins, which may be rounded differently
+func sameAmount(a float64, b float64) bool {
+	return math.Abs(a-b) <= 1e-8+1e-6*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// Return the time between two transactions, in milliseconds
+func timeDistance(a Transaction, b Transaction) int64 {
+	if a.Time > b.Time {
+		return a.Time - b.Time
+	}
+
+	return b.Time - a.Time
+}
+
+// Check if two sets of balance changes are the same, each movement matching a distinct one
+func sameMovements(a []movement, b []movement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	matched := make([]bool, len(b))
+	for _, m := range a {
+		found := false
+		for j, other := range b {
+			if !matched[j] && other.asset == m.asset && (other.amount > 0) == (m.amount > 0) && sameAmount(other.amount, m.amount) {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Index of reference transactions by moved asset, sorted by time, to find duplicates
+// Each reference transaction matches a single duplicate
+type duplicateIndex struct {
+	window       int64
+	transactions []Transaction
+	used         []bool
+	byAsset      map[string][]int
+}
+
+// Build an index over reference transactions
+func newDuplicateIndex(transactions []Transaction) *duplicateIndex {
+	sorted := append([]Transaction{}, transactions...)
+	Sort(sorted)
+
+	index := &duplicateIndex{
+		window:       viper.GetInt64("tracklet.duplicateWindow") * 1000,
+		transactions: sorted,
+		used:         make([]bool, len(sorted)),
+		byAsset:      make(map[string][]int),
+	}
+
+	for i, tx := range sorted {
+		for _, m := range movements(tx) {
+			if m.asset != "" {
+				index.byAsset[m.asset] = append(index.byAsset[m.asset], i)
+			}
+		}
+	}
+
+	return index
+}
+
+// Check if an unused indexed transaction makes the same balance changes as a transaction around the
+// same time, the closest one being marked as used so that it does not match another transaction
+func (d *duplicateIndex) match(tx Transaction) bool {
+	moves := movements(tx)
+	if len(moves) == 0 {
+		return false
+	}
+
+	candidates := d.byAsset[moves[0].asset]
+	start := sort.Search(len(candidates), func(i int) bool {
+		return d.transactions[candidates[i]].Time >= tx.Time-d.window
+	})
+
+	best := -1
+	for i := start; i < len(candidates) && d.transactions[candidates[i]].Time <= tx.Time+d.window; i++ {
+		c := candidates[i]
+		if d.used[c] || !sameMovements(moves, movements(d.transactions[c])) {
+			continue
+		}
+		if best == -1 || timeDistance(d.transactions[c], tx) < timeDistance(d.transactions[best], tx) {
+			best = c
+		}
+	}
+	if best == -1 {
+		return false
+	}
+	d.used[best] = true
+
+	return true
+}
@@ -0,0 +1,63 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestDuplicateIndexMatch(t *testing.T) {
+	viper.Set("tracklet.duplicateWindow", 60)
+	defer viper.Set("tracklet.duplicateWindow", nil)
+
+	fetched := []Transaction{
+		{ID: "api-buy", Type: TypeBuy, Time: 1000, Asset: "BTC", Amount: 0.1, QuoteAsset: "EUR", QuoteAmount: 4000},
+		{ID: "api-deposit", Type: TypeDeposit, Time: 1000, Asset: "EUR", Amount: 4000},
+	}
+
+	tests := []struct {
+		name         string
+		transactions []Transaction
+		want         []bool
+	}{
+		{
+			name:         "same trade",
+			transactions: []Transaction{{Type: TypeBuy, Time: 2000, Asset: "BTC", Amount: 0.1, QuoteAsset: "EUR", QuoteAmount: 4000}},
+			want:         []bool{true},
+		},
+		{
+			name:         "single leg matching another transaction",
+			transactions: []Transaction{{Type: TypeBuy, Time: 2000, Asset: "ETH", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 4000}},
+			want:         []bool{false},
+		},
+		{
+			name: "identical fills against a single fetched one",
+			transactions: []Transaction{
+				{Type: TypeBuy, Time: 2000, Asset: "BTC", Amount: 0.1, QuoteAsset: "EUR", QuoteAmount: 4000},
+				{Type: TypeBuy, Time: 2000, Asset: "BTC", Amount: 0.1, QuoteAsset: "EUR", QuoteAmount: 4000},
+			},
+			want: []bool{true, false},
+		},
+		{
+			name:         "outside the window",
+			transactions: []Transaction{{Type: TypeDeposit, Time: 100000, Asset: "EUR", Amount: 4000}},
+			want:         []bool{false},
+		},
+		{
+			name:         "opposite direction",
+			transactions: []Transaction{{Type: TypeWithdraw, Time: 1000, Asset: "EUR", Amount: 4000}},
+			want:         []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := newDuplicateIndex(fetched)
+			for i, tx := range tt.transactions {
+				if got := index.match(tx); got != tt.want[i] {
+					t.Errorf("match(#%d) = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
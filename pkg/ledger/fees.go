@@ -0,0 +1,52 @@
+// Handles reporting of fees paid
+package ledger
+
+import (
+	"time"
+)
+
+// Fee quantities by asset
+type FeeTotals map[string]float64
+
+type FeeReport struct {
+	ByAsset  FeeTotals            `json:"byAsset"`
+	BySource map[string]FeeTotals `json:"bySource"`
+	ByYear   map[string]FeeTotals `json:"byYear"`
+}
+
+// Add a fee to its asset, source and year totals
+func (r *FeeReport) add(tx Transaction, asset string, amount float64) {
+	if asset == "" || amount <= 0 {
+		return
+	}
+
+	year := time.UnixMilli(tx.Time).UTC().Format("2006")
+	if r.BySource[tx.Source] == nil {
+		r.BySource[tx.Source] = make(FeeTotals)
+	}
+	if r.ByYear[year] == nil {
+		r.ByYear[year] = make(FeeTotals)
+	}
+
+	r.ByAsset[asset] += amount
+	r.BySource[tx.Source][asset] += amount
+	r.ByYear[year][asset] += amount
+}
+
+// Sum fees paid in transactions, trading commissions, network fees and fee transactions alike
+func Fees(transactions []Transaction) *FeeReport {
+	report := &FeeReport{
+		ByAsset:  make(FeeTotals),
+		BySource: make(map[string]FeeTotals),
+		ByYear:   make(map[string]FeeTotals),
+	}
+
+	for _, tx := range transactions {
+		if tx.Type == TypeFee {
+			report.add(tx, tx.Asset, tx.Amount)
+		}
+		report.add(tx, tx.FeeAsset, tx.FeeAmount)
+	}
+
+	return report
+}
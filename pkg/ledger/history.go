@@ -0,0 +1,163 @@
+// Handles portfolio value history from snapshots
+package ledger
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// History periods
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+// Portfolio value at the last snapshot of a period
+type HistoryPoint struct {
+	Time     int64   `json:"time"`
+	Period   string  `json:"period"`
+	Value    float64 `json:"value"`
+	Invested float64 `json:"invested"`
+	// Value change since the previous period
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"changePercent"`
+	// Percentage below the highest value reached so far
+	Drawdown float64 `json:"drawdown"`
+}
+
+// Fall of the portfolio value from a peak to a trough, recovered when the peak value was reached again
+type Drawdown struct {
+	PeakTime     int64   `json:"peakTime"`
+	PeakValue    float64 `json:"peakValue"`
+	TroughTime   int64   `json:"troughTime"`
+	TroughValue  float64 `json:"troughValue"`
+	Percent      float64 `json:"percent"`
+	RecoveryTime int64   `json:"recoveryTime,omitempty"`
+}
+
+type History struct {
+	Currency    string         `json:"currency"`
+	Period      string         `json:"period"`
+	Points      []HistoryPoint `json:"points"`
+	MaxDrawdown *Drawdown      `json:"maxDrawdown,omitempty"`
+	Returns     *Returns       `json:"returns,omitempty"`
+}
+
+// Return the period a Unix milliseconds time belongs to
+func periodKey(t int64, period string) (string, error) {
+	date := time.UnixMilli(t).UTC()
+
+	switch period {
+	case PeriodDaily:
+		return date.Format("2006-01-02"), nil
+	case PeriodWeekly:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week), nil
+	case PeriodMonthly:
+		return date.Format("2006-01"), nil
+	default:
+		return "", fmt.Errorf("unknown period '%s', expected %s, %s or %s", period, PeriodDaily, PeriodWeekly, PeriodMonthly)
+	}
+}
+
+// Total portfolio value after each snapshot, every source counting with its latest snapshot
+func portfolioValues(snapshots []Snapshot) []HistoryPoint {
+	latest := make(map[string]Stats)
+	points := []HistoryPoint{}
+
+	for _, snapshot := range snapshots {
+		latest[snapshot.Source] = snapshot.Stats
+
+		point := HistoryPoint{Time: snapshot.Time}
+		for _, stats := range latest {
+			point.Value += stats.TotalValue
+			point.Invested += stats.TotalInvested + stats.TotalDeposited
+		}
+		points = append(points, point)
+	}
+
+	return points
+}
+
+// Build the portfolio value history of snapshots in reporting currency, by period
+// Drawdowns are measured between all snapshots, not only the last of each period
+func BuildHistory(snapshots []Snapshot, period string) (*History, error) {
+	currency := ReportingCurrency()
+
+	sorted := []Snapshot{}
+	for _, snapshot := range snapshots {
+		if snapshot.Stats.Currency == currency {
+			sorted = append(sorted, snapshot)
+		}
+	}
+	if skipped := len(snapshots) - len(sorted); skipped > 0 {
+		log.Warnf("Skipping %d snapshots not valued in %s", skipped, currency)
+	}
+	SortSnapshots(sorted)
+
+	return buildHistory(currency, portfolioValues(sorted), period)
+}
+
+// Group portfolio values by period, measuring drawdowns and returns between all of them
+func buildHistory(currency string, points []HistoryPoint, period string) (*History, error) {
+	history := &History{
+		Currency: currency,
+		Period:   period,
+		Points:   []HistoryPoint{},
+	}
+
+	var current, deepest *Drawdown
+	peak := HistoryPoint{}
+	for _, point := range points {
+		key, err := periodKey(point.Time, period)
+		if err != nil {
+			return nil, err
+		}
+
+		if point.Value >= peak.Value {
+			if current != nil {
+				current.RecoveryTime = point.Time
+			}
+			peak, current = point, nil
+		} else if peak.Value > 0 {
+			if current == nil {
+				current = &Drawdown{PeakTime: peak.Time, PeakValue: peak.Value, TroughValue: peak.Value}
+			}
+			if point.Value < current.TroughValue {
+				current.TroughTime, current.TroughValue = point.Time, point.Value
+				current.Percent = (peak.Value - point.Value) / peak.Value * 100
+			}
+			if deepest == nil || current.Percent > deepest.Percent {
+				deepest = current
+			}
+			point.Drawdown = (peak.Value - point.Value) / peak.Value * 100
+		}
+		point.Period = key
+
+		// Only the last snapshot of a period is kept
+		if n := len(history.Points); n > 0 && history.Points[n-1].Period == key {
+			history.Points[n-1] = point
+		} else {
+			history.Points = append(history.Points, point)
+		}
+	}
+
+	for i := 1; i < len(history.Points); i++ {
+		previous := history.Points[i-1].Value
+		history.Points[i].Change = history.Points[i].Value - previous
+		if previous != 0 {
+			history.Points[i].ChangePercent = history.Points[i].Change / previous * 100
+		}
+	}
+
+	if deepest != nil {
+		maxDrawdown := *deepest
+		history.MaxDrawdown = &maxDrawdown
+	}
+	history.Returns = calculateReturns(points)
+
+	return history, nil
+}
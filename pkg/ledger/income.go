@@ -0,0 +1,173 @@
+// Handles reporting of staking rewards, interest and other passive income
+package ledger
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const millisecondsPerYear = 365.25 * 24 * 3600 * 1000
+
+type IncomeTotal struct {
+	Quantity float64 `json:"quantity"`
+	// Value of each reward when received
+	Value float64 `json:"value"`
+	// Time weighted balance held since the first reward, rewards excluded
+	AverageBalance float64 `json:"averageBalance,omitempty"`
+	// Percentage of the average balance earned over a year at the same rate
+	AnnualYield float64 `json:"annualYield,omitempty"`
+	first       int64
+}
+
+// Income totals by asset
+type IncomeTotals map[string]*IncomeTotal
+
+type IncomeReport struct {
+	Currency   string                  `json:"currency"`
+	TotalValue float64                 `json:"totalValue"`
+	ByAsset    IncomeTotals            `json:"byAsset"`
+	BySource   map[string]IncomeTotals `json:"bySource"`
+	ByMonth    map[string]IncomeTotals `json:"byMonth"`
+}
+
+// Add a valued reward to an asset total
+func (t IncomeTotals) add(tx Transaction, value float64) {
+	total, ok := t[tx.Asset]
+	if !ok {
+		total = &IncomeTotal{first: tx.Time}
+		t[tx.Asset] = total
+	}
+
+	total.Quantity += tx.Amount
+	total.Value += value
+}
+
+// Set totals yield relative to the balance held between their first reward and the end of the period
+func (t IncomeTotals) calculateYield(transactions []Transaction, source string, end int64) {
+	for asset, total := range t {
+		// Yields extrapolated from less than a day are meaningless
+		if end-total.first < 24*3600*1000 {
+			continue
+		}
+
+		total.AverageBalance = averageBalance(transactions, source, asset, total.first, end)
+		if total.AverageBalance > 0 {
+			total.AnnualYield = total.Quantity / total.AverageBalance * millisecondsPerYear / float64(end-total.first) * 100
+		}
+	}
+}
+
+// Time weighted average balance of an asset over a period, in a source or across all of them when empty
+// Rewards are left out, yields being relative to the balance earning them
+func averageBalance(transactions []Transaction, source string, asset string, start int64, end int64) float64 {
+	balance, weighted, last := 0.0, 0.0, start
+
+	for _, tx := range transactions {
+		if tx.Time >= end {
+			break
+		}
+		if tx.Type == TypeIncome || (source != "" && tx.Source != source) {
+			continue
+		}
+
+		change := 0.0
+		for _, m := range movements(tx) {
+			if m.asset == asset {
+				change += m.amount
+			}
+		}
+		if tx.FeeAsset == asset {
+			change -= tx.FeeAmount
+		}
+		if change == 0 {
+			continue
+		}
+
+		if tx.Time > start {
+			weighted += balance * float64(tx.Time-last)
+			last = tx.Time
+		}
+		balance += change
+	}
+	weighted += balance * float64(end-last)
+
+	return weighted / float64(end-start)
+}
+
+// Request the prices of every asset received as income without a cash quote at once, over the period it was received in
+func preloadIncomePrices(transactions []Transaction, prices *PriceHistory) {
+	periods := make(map[string][2]int64)
+	for _, tx := range transactions {
+		if tx.Type != TypeIncome || IsCash(tx.QuoteAsset) || IsCash(tx.Asset) {
+			continue
+		}
+
+		period, ok := periods[tx.Asset]
+		if !ok || tx.Time < period[0] {
+			period[0] = tx.Time
+		}
+		if tx.Time > period[1] {
+			period[1] = tx.Time
+		}
+		periods[tx.Asset] = period
+	}
+
+	for asset, period := range periods {
+		if err := prices.Preload(asset, prices.Currency(), period[0], period[1]+millisecondsPerDay); err != nil {
+			log.Warnf("Could not preload '%s' prices: %v", asset, err)
+		}
+	}
+}
+
+// Aggregate income received between two Unix milliseconds times by asset, source and month
+// Rewards are valued when received, balances of all transactions being used for yields
+func CalculateIncome(transactions []Transaction, prices *PriceHistory, from int64, to int64) *IncomeReport {
+	sorted := append([]Transaction{}, transactions...)
+	Sort(sorted)
+
+	report := &IncomeReport{
+		Currency: prices.Currency(),
+		ByAsset:  make(IncomeTotals),
+		BySource: make(map[string]IncomeTotals),
+		ByMonth:  make(map[string]IncomeTotals),
+	}
+	unpriced := make(map[string]bool)
+
+	for _, tx := range sorted {
+		if tx.Type != TypeIncome || tx.Time < from || tx.Time >= to {
+			continue
+		}
+
+		value, err := prices.Value(tx)
+		if err != nil && !unpriced[tx.Asset] {
+			log.Warnf("Could not value '%s' income: %v", tx.Asset, err)
+			unpriced[tx.Asset] = true
+		}
+
+		month := time.UnixMilli(tx.Time).UTC().Format("2006-01")
+		if report.BySource[tx.Source] == nil {
+			report.BySource[tx.Source] = make(IncomeTotals)
+		}
+		if report.ByMonth[month] == nil {
+			report.ByMonth[month] = make(IncomeTotals)
+		}
+
+		report.TotalValue += value
+		report.ByAsset.add(tx, value)
+		report.BySource[tx.Source].add(tx, value)
+		report.ByMonth[month].add(tx, value)
+	}
+
+	end := time.Now().UnixMilli()
+	if to < end {
+		end = to
+	}
+
+	report.ByAsset.calculateYield(sorted, "", end)
+	for source, totals := range report.BySource {
+		totals.calculateYield(sorted, source, end)
+	}
+
+	return report
+}
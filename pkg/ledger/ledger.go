@@ -0,0 +1,84 @@
+// Handles exchange agnostic transactions logic
+package ledger
+
+import (
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Type string
+
+const (
+	TypeBuy      Type = "buy"
+	TypeSell     Type = "sell"
+	TypeDeposit  Type = "deposit"
+	TypeWithdraw Type = "withdraw"
+	TypeIncome   Type = "income"
+	TypeFee      Type = "fee"
+)
+
+// A single movement of assets from any source
+// Amount is always positive, its direction being given by the transaction type
+// Quote of a deposit, withdrawal or income, when set, is its fiat value and moves no holdings
+// Origin is empty for transactions fetched from an API, and names the import otherwise
+type Transaction struct {
+	ID          string  `json:"id"`
+	Source      string  `json:"source"`
+	Type        Type    `json:"type"`
+	Time        int64   `json:"time"`
+	Asset       string  `json:"asset"`
+	Amount      float64 `json:"amount"`
+	QuoteAsset  string  `json:"quoteAsset,omitempty"`
+	QuoteAmount float64 `json:"quoteAmount,omitempty"`
+	FeeAsset    string  `json:"feeAsset,omitempty"`
+	FeeAmount   float64 `json:"feeAmount,omitempty"`
+	TxHash      string  `json:"txHash,omitempty"`
+	Origin      string  `json:"origin,omitempty"`
+}
+
+var fiatCurrencies = map[string]bool{
+	"EUR": true,
+	"USD": true,
+	"GBP": true,
+	"CHF": true,
+	"AUD": true,
+	"CAD": true,
+	"JPY": true,
+	"SEK": true,
+	"NOK": true,
+	"DKK": true,
+	"PLN": true,
+	"NZD": true,
+	"SGD": true,
+	"HKD": true,
+	"TRY": true,
+	"BRL": true,
+}
+
+const defaultReportingCurrency = "EUR"
+
+// Check if an asset is a fiat currency
+func IsFiat(asset string) bool {
+	return fiatCurrencies[strings.ToUpper(asset)]
+}
+
+// Return the fiat currency wallets and reports are valued in, set in configuration
+func ReportingCurrency() string {
+	currency := strings.ToUpper(viper.GetString("tracklet.currency"))
+	if !IsFiat(currency) {
+		log.Warnf("Unknown reporting currency '%s', using '%s'", currency, defaultReportingCurrency)
+		return defaultReportingCurrency
+	}
+
+	return currency
+}
+
+// Sort transactions from the oldest to the newest
+func Sort(transactions []Transaction) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Time < transactions[j].Time
+	})
+}
@@ -0,0 +1,219 @@
+// Handles historical asset prices and exchange rates
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Coin priced in every fiat currency, giving exchange rates between them
+const exchangeRateCoin = "bitcoin"
+
+const priceCacheFile = "price_cache"
+
+// Daily asset prices in reporting currency, each asset and day being requested once
+// Prices of past days are cached to file, those of the current day still changing
+type PriceHistory struct {
+	currency string
+	coins    Coins
+	// Prices by coin ID, day and lower case currency
+	prices map[string]float64
+	errors map[string]error
+}
+
+// Create a new PriceHistory object from the Coingecko coin list and the cached prices
+func NewPriceHistory(coinList *coingecko.CoinList) *PriceHistory {
+	prices := make(map[string]float64)
+	if utils.FileExists(fmt.Sprintf("%s.json", priceCacheFile)) {
+		if err := json.Unmarshal(utils.LoadFromFile(fmt.Sprintf("%s.json", priceCacheFile)), &prices); err != nil {
+			log.Warnf("Could not unmarshal price cache, ignoring it: %v", err)
+			prices = make(map[string]float64)
+		}
+	}
+
+	return &PriceHistory{
+		currency: ReportingCurrency(),
+		coins:    NewCoins(coinList),
+		prices:   prices,
+		errors:   make(map[string]error),
+	}
+}
+
+// Write prices of past days to the price cache
+func (p *PriceHistory) Save() error {
+	today := time.Now().UTC().Format("2006-01-02")
+
+	cached := make(map[string]float64)
+	for key, price := range p.prices {
+		if parts := strings.Split(key, "/"); len(parts) == 3 && parts[1] < today {
+			cached[key] = price
+		}
+	}
+
+	if err := utils.WriteToFile(priceCacheFile, cached); err != nil {
+		return fmt.Errorf("could not save price cache: %w", err)
+	}
+
+	return nil
+}
+
+// Return the currency prices are given in
+func (p *PriceHistory) Currency() string {
+	return p.currency
+}
+
+// Return the coins prices are known for
+func (p *PriceHistory) Coins() Coins {
+	return p.coins
+}
+
+// Return the Coingecko ID of an asset
+func (p *PriceHistory) CoinID(asset string) (string, bool) {
+	return p.coins.ID(asset)
+}
+
+// Return the cache key of a coin price in a currency on the day of a Unix milliseconds time
+func priceKey(id string, t int64, currency string) string {
+	return fmt.Sprintf("%s/%s/%s", id, time.UnixMilli(t).UTC().Format("2006-01-02"), strings.ToLower(currency))
+}
+
+// Return the price of a coin in a currency on the day of a Unix milliseconds time
+func (p *PriceHistory) history(id string, t int64, currency string) (float64, error) {
+	key := priceKey(id, t, currency)
+	if price, ok := p.prices[key]; ok {
+		return price, nil
+	}
+
+	day := fmt.Sprintf("%s/%s", id, time.UnixMilli(t).UTC().Format("2006-01-02"))
+	if err, ok := p.errors[day]; ok {
+		return 0, err
+	}
+
+	coinHistory, err := coingecko.GetCoinHistory(id, time.UnixMilli(t).UTC())
+	if err != nil {
+		p.errors[day] = fmt.Errorf("could not get '%s' price history: %w", id, err)
+		return 0, p.errors[day]
+	}
+
+	for c, price := range coinHistory.MarketData.CurrentPrice {
+		p.prices[priceKey(id, t, c)] = price
+	}
+
+	price, ok := p.prices[key]
+	if !ok {
+		p.errors[day] = fmt.Errorf("no '%s' price found for '%s'", strings.ToUpper(currency), id)
+		return 0, p.errors[day]
+	}
+
+	return price, nil
+}
+
+// Request the daily prices of an asset in a currency between two Unix milliseconds times at once
+// Days already known are kept, the first price of each day being used otherwise
+func (p *PriceHistory) Preload(asset string, currency string, from int64, to int64) error {
+	id, ok := p.CoinID(p.coins.PricedAsset(asset))
+	if IsCash(asset) {
+		id, ok = exchangeRateCoin, true
+	}
+	if !ok {
+		return fmt.Errorf("no Coingecko coin found for '%s'", asset)
+	}
+
+	// Past days are requested only when one is missing from the cache, today being requested apart
+	today := time.Now().UTC().Truncate(24 * time.Hour).UnixMilli()
+	cached := true
+	for t := from; t < to && t < today; t += millisecondsPerDay {
+		if _, ok := p.prices[priceKey(id, t, currency)]; !ok {
+			cached = false
+			break
+		}
+	}
+	if cached {
+		return nil
+	}
+
+	chart, err := coingecko.GetCoinMarketChart(id, currency, from, to)
+	if err != nil {
+		return fmt.Errorf("could not get '%s' market chart: %w", id, err)
+	}
+
+	for _, point := range chart.Prices {
+		key := priceKey(id, int64(point[0]), currency)
+		if _, ok := p.prices[key]; !ok {
+			p.prices[key] = point[1]
+		}
+	}
+
+	return nil
+}
+
+// Return the exchange rate from a cash asset to reporting currency on the day of a Unix milliseconds time
+func (p *PriceHistory) Rate(currency string, t int64) (float64, error) {
+	currency = CashCurrency(currency)
+	if strings.EqualFold(currency, p.currency) {
+		return 1, nil
+	}
+
+	from, err := p.history(exchangeRateCoin, t, currency)
+	if err != nil {
+		return 0, err
+	}
+	to, err := p.history(exchangeRateCoin, t, p.currency)
+	if err != nil {
+		return 0, err
+	}
+	if from == 0 || to == 0 {
+		return 0, fmt.Errorf("no exchange rate found from '%s' to '%s'", currency, p.currency)
+	}
+
+	return to / from, nil
+}
+
+// Convert a cash amount to reporting currency at the rate of the day of a Unix milliseconds time
+func (p *PriceHistory) Convert(amount float64, currency string, t int64) (float64, error) {
+	rate, err := p.Rate(currency, t)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount * rate, nil
+}
+
+// Return the price of an asset on the day of a Unix milliseconds time
+func (p *PriceHistory) Price(asset string, t int64) (float64, error) {
+	if IsCash(asset) {
+		return p.Rate(asset, t)
+	}
+
+	id, ok := p.CoinID(p.coins.PricedAsset(asset))
+	if !ok {
+		return 0, fmt.Errorf("no Coingecko coin found for '%s'", asset)
+	}
+
+	price, err := p.history(id, t, p.currency)
+	if err != nil {
+		return 0, fmt.Errorf("could not get '%s' price: %w", asset, err)
+	}
+
+	return price, nil
+}
+
+// Return the value of a transaction asset when it happened, its cash quote being used when set
+func (p *PriceHistory) Value(tx Transaction) (float64, error) {
+	if IsCash(tx.QuoteAsset) && tx.QuoteAmount > 0 {
+		return p.Convert(tx.QuoteAmount, tx.QuoteAsset, tx.Time)
+	}
+
+	price, err := p.Price(tx.Asset, tx.Time)
+	if err != nil {
+		return 0, err
+	}
+
+	return price * tx.Amount, nil
+}
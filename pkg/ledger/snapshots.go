@@ -0,0 +1,106 @@
+// Handles persistence of wallet snapshots
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+)
+
+const snapshotsSuffix = "_snapshots"
+
+// State of a source wallet at a given time
+type Snapshot struct {
+	Time     int64               `json:"time"`
+	Source   string              `json:"source"`
+	Holdings map[string]Holdings `json:"holdings"`
+	Cash     map[string]Holdings `json:"cash"`
+	// Unit prices in reporting currency, by asset
+	Prices map[string]float64 `json:"prices"`
+	Stats  Stats              `json:"stats"`
+}
+
+// Load the snapshots of a source, oldest first
+func LoadSnapshots(source string) ([]Snapshot, error) {
+	snapshots := []Snapshot{}
+
+	filename := fmt.Sprintf("%s%s.json", source, snapshotsSuffix)
+	if !utils.FileExists(filename) {
+		return snapshots, nil
+	}
+
+	if err := json.Unmarshal(utils.LoadFromFile(filename), &snapshots); err != nil {
+		return nil, fmt.Errorf("could not unmarshal '%s' snapshots: %w", source, err)
+	}
+
+	return snapshots, nil
+}
+
+// Load the snapshots of every source, oldest first
+func LoadAllSnapshots() ([]Snapshot, error) {
+	dataPath, err := utils.GetDataPath()
+	if err != nil {
+		return nil, fmt.Errorf("could not get data path: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dataPath, fmt.Sprintf("*%s.json", snapshotsSuffix)))
+	if err != nil {
+		return nil, fmt.Errorf("could not list snapshots: %w", err)
+	}
+
+	snapshots := []Snapshot{}
+	for _, file := range files {
+		sourceSnapshots, err := LoadSnapshots(strings.TrimSuffix(filepath.Base(file), fmt.Sprintf("%s.json", snapshotsSuffix)))
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, sourceSnapshots...)
+	}
+	SortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+// Sort snapshots from the oldest to the newest
+func SortSnapshots(snapshots []Snapshot) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time < snapshots[j].Time
+	})
+}
+
+// Append a snapshot of a wallet to its source snapshots
+func SaveSnapshot(source string, holdings map[string]Holdings, cash map[string]Holdings, stats Stats) error {
+	snapshots, err := LoadSnapshots(source)
+	if err != nil {
+		return err
+	}
+
+	prices := make(map[string]float64)
+	for _, assets := range []map[string]Holdings{holdings, cash} {
+		for asset, d := range assets {
+			if d.Quantity != 0 {
+				prices[asset] = d.CurrentValue / d.Quantity
+			}
+		}
+	}
+
+	snapshots = append(snapshots, Snapshot{
+		Time:     time.Now().UnixMilli(),
+		Source:   source,
+		Holdings: holdings,
+		Cash:     cash,
+		Prices:   prices,
+		Stats:    stats,
+	})
+
+	if err := utils.WriteToFile(fmt.Sprintf("%s%s", source, snapshotsSuffix), snapshots); err != nil {
+		return fmt.Errorf("could not save '%s' snapshots: %w", source, err)
+	}
+
+	return nil
+}
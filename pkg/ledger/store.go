@@ -0,0 +1,169 @@
+// Handles ledger persistence logic
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	"github.com/spf13/viper"
+)
+
+const fileSuffix = "_ledger"
+
+// Return the data file name holding the ledger of a given source
+func fileName(source string) string {
+	return fmt.Sprintf("%s%s", source, fileSuffix)
+}
+
+// Write a source ledger to file
+func write(source string, transactions []Transaction) error {
+	Sort(transactions)
+
+	if err := utils.WriteToFile(fileName(source), transactions); err != nil {
+		return fmt.Errorf("could not save '%s' ledger: %w", source, err)
+	}
+
+	return nil
+}
+
+// Load a source ledger before updating it, a source without one yet having no transactions
+// Ledgers which cannot be read fail the update rather than being overwritten
+func loadExisting(source string) ([]Transaction, error) {
+	if !utils.FileExists(fmt.Sprintf("%s.json", fileName(source))) {
+		return []Transaction{}, nil
+	}
+
+	return Load(source)
+}
+
+// Save transactions fetched from a source API, replacing previously fetched ones
+// Fetched transactions older than the history window are kept, the API only returning recent ones
+func Save(source string, transactions []Transaction) error {
+	existing, err := loadExisting(source)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]bool)
+	for _, tx := range transactions {
+		ids[tx.ID] = true
+	}
+
+	windowStart := time.Now().AddDate(0, 0, -viper.GetInt("tracklet.maxHistory")).UnixMilli()
+	for _, tx := range existing {
+		if tx.Origin == "" && tx.Time < windowStart && !ids[tx.ID] {
+			transactions = append(transactions, tx)
+		}
+	}
+
+	return Replace(source, transactions)
+}
+
+// Replace all but imported transactions of a source ledger
+// Imported transactions are kept unless the new ones contain them too
+func Replace(source string, transactions []Transaction) error {
+	existing, err := loadExisting(source)
+	if err != nil {
+		return err
+	}
+
+	fetched := newDuplicateIndex(transactions)
+	for _, tx := range existing {
+		if tx.Origin != "" && !fetched.match(tx) {
+			transactions = append(transactions, tx)
+		}
+	}
+
+	return write(source, transactions)
+}
+
+// Add imported transactions to a source ledger, skipping already known ones
+// Transactions matching one fetched from the API by time, asset and amount are skipped as well
+// Returns the number of transactions actually added
+func Import(source string, transactions []Transaction) (int, error) {
+	existing, err := loadExisting(source)
+	if err != nil {
+		return 0, err
+	}
+
+	known := make(map[string]bool)
+	fetched := []Transaction{}
+	for _, tx := range existing {
+		known[tx.ID] = true
+		if tx.Origin == "" {
+			fetched = append(fetched, tx)
+		}
+	}
+	duplicates := newDuplicateIndex(fetched)
+
+	added := 0
+	for _, tx := range transactions {
+		if known[tx.ID] || duplicates.match(tx) {
+			continue
+		}
+		known[tx.ID] = true
+		existing = append(existing, tx)
+		added++
+	}
+
+	return added, write(source, existing)
+}
+
+// Load a source ledger from file
+func Load(source string) ([]Transaction, error) {
+	data := utils.LoadFromFile(fmt.Sprintf("%s.json", fileName(source)))
+	if data == nil {
+		return nil, fmt.Errorf("no ledger found for '%s', process its data first", source)
+	}
+
+	transactions := []Transaction{}
+	if err := json.Unmarshal(data, &transactions); err != nil {
+		return nil, fmt.Errorf("could not unmarshal '%s' ledger: %w", source, err)
+	}
+
+	return transactions, nil
+}
+
+// List all sources having a ledger on disk
+func Sources() ([]string, error) {
+	dataPath, err := utils.GetDataPath()
+	if err != nil {
+		return nil, fmt.Errorf("could not get data path: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dataPath, fmt.Sprintf("*%s.json", fileSuffix)))
+	if err != nil {
+		return nil, fmt.Errorf("could not list ledgers: %w", err)
+	}
+
+	sources := []string{}
+	for _, file := range files {
+		sources = append(sources, strings.TrimSuffix(filepath.Base(file), fmt.Sprintf("%s.json", fileSuffix)))
+	}
+
+	return sources, nil
+}
+
+// Load the ledgers of every source
+func LoadAll() ([]Transaction, error) {
+	sources, err := Sources()
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := []Transaction{}
+	for _, source := range sources {
+		sourceTransactions, err := Load(source)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, sourceTransactions...)
+	}
+	Sort(transactions)
+
+	return transactions, nil
+}
@@ -0,0 +1,48 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateUnreadableLedger(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dataPath := filepath.Join(home, ".tracklet", "data")
+	if err := os.MkdirAll(dataPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{ID: "1", Source: "kraken", Type: TypeDeposit, Time: testTime("2022-01-01", 0), Asset: "EUR", Amount: 100}
+
+	// A source without a ledger yet starts empty
+	if added, err := Import("kraken", []Transaction{tx}); err != nil || added != 1 {
+		t.Fatalf("Import() = %d, %v, want 1 transaction added", added, err)
+	}
+
+	file := filepath.Join(dataPath, fileName("kraken")+".json")
+	if err := os.WriteFile(file, []byte("{corrupted"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := map[string]func() error{
+		"save":    func() error { return Save("kraken", []Transaction{tx}) },
+		"replace": func() error { return Replace("kraken", []Transaction{tx}) },
+		"import": func() error {
+			_, err := Import("kraken", []Transaction{tx})
+			return err
+		},
+	}
+	for name, update := range updates {
+		if err := update(); err == nil {
+			t.Errorf("%s of an unreadable ledger succeeded, want an error", name)
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil || string(data) != "{corrupted" {
+			t.Errorf("%s overwrote the unreadable ledger", name)
+		}
+	}
+}
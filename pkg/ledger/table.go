@@ -0,0 +1,115 @@
+// Handles table output of wallets
+package ledger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Number of days of value history drawn below wallet tables
+const sparklineDays = 30
+
+// Format a quantity to 8 decimals without trailing zeros
+func FormatQuantity(quantity float64) string {
+	return strings.TrimSuffix(strings.TrimRight(strconv.FormatFloat(quantity, 'f', 8, 64), "0"), ".")
+}
+
+// Format a unit price, keeping significant digits of small prices
+func FormatPrice(price float64) string {
+	if price != 0 && price < 1 && price > -1 {
+		return strconv.FormatFloat(price, 'g', 4, 64)
+	}
+
+	return fmt.Sprintf("%.2f", price)
+}
+
+// Draw the daily value of a source from its snapshots, ending with its current value
+func valueSparkline(source string, stats Stats) string {
+	snapshots, err := LoadSnapshots(source)
+	if err != nil {
+		log.Warnf("Could not load snapshots: %v", err)
+		return ""
+	}
+
+	today, _ := periodKey(time.Now().UnixMilli(), PeriodDaily)
+	values := []float64{}
+	if history, err := BuildHistory(snapshots, PeriodDaily); err == nil {
+		for _, point := range history.Points {
+			if point.Period != today {
+				values = append(values, point.Value)
+			}
+		}
+	}
+	values = append(values, stats.TotalValue)
+	if len(values) < 2 {
+		return ""
+	}
+	if len(values) > sparklineDays {
+		values = values[len(values)-sparklineDays:]
+	}
+
+	return fmt.Sprintf("Last %d days : %s %.2f -> %.2f %s", len(values), utils.Sparkline(values), values[0], values[len(values)-1], stats.Currency)
+}
+
+// Build the table of a wallet holdings and cash, sorted by value
+func WalletTable(source string, holdings map[string]Holdings, cash map[string]Holdings, stats Stats) *utils.Table {
+	table := &utils.Table{
+		Header:  []string{"Asset", "Name", "Quantity", "Price", "Value", "Allocation", "Cost", "PnL", "PnL %"},
+		Numeric: map[int]bool{2: true, 3: true, 4: true, 5: true, 6: true, 7: true, 8: true},
+	}
+
+	for _, assets := range []map[string]Holdings{holdings, cash} {
+		for asset, d := range assets {
+			if d.Quantity == 0 {
+				continue
+			}
+
+			allocation := 0.0
+			if stats.TotalValue != 0 {
+				allocation = d.CurrentValue / stats.TotalValue * 100
+			}
+			cost, pnl, pnlPercent := "-", "-", "-"
+			if d.CostBasis > 0 {
+				gain := d.CurrentValue - d.CostBasis
+				cost = fmt.Sprintf("%.2f", d.CostBasis)
+				pnl = utils.ColorSign(fmt.Sprintf("%+.2f", gain), gain)
+				pnlPercent = utils.ColorSign(fmt.Sprintf("%+.1f%%", gain/d.CostBasis*100), gain)
+			}
+
+			table.Rows = append(table.Rows, []string{
+				strings.ToUpper(asset),
+				d.Name,
+				FormatQuantity(d.Quantity),
+				FormatPrice(d.CurrentValue / d.Quantity),
+				fmt.Sprintf("%.2f", d.CurrentValue),
+				fmt.Sprintf("%.1f%%", allocation),
+				cost,
+				pnl,
+				pnlPercent,
+			})
+		}
+	}
+	// Sorting by an existing column can not fail
+	_ = table.Sort("Value")
+
+	table.Footer = []string{
+		fmt.Sprintf("Total value : %.2f %s (cash %.2f)", stats.TotalValue, stats.Currency, stats.TotalCash),
+		fmt.Sprintf("Invested : %.2f, deposited : %.2f, income : %.2f", stats.TotalInvested, stats.TotalDeposited, stats.TotalIncome),
+		fmt.Sprintf("Gain : %s", utils.ColorSign(fmt.Sprintf("%+.2f", stats.GainValue), stats.GainValue)),
+	}
+	if sparkline := valueSparkline(source, stats); sparkline != "" {
+		table.Footer = append(table.Footer, sparkline)
+	}
+
+	return table
+}
+
+// Return the wallet as a table
+func (w *Wallet) Table() *utils.Table {
+	return WalletTable(w.Source, w.Holdings, w.Cash, w.Stats)
+}
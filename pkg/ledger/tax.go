@@ -0,0 +1,69 @@
+// Handles realized gains and taxable income by year
+package ledger
+
+import (
+	"time"
+)
+
+// A sale of crypto for cash and the gain it realized, in reporting currency
+type Disposal struct {
+	Time     int64   `json:"time"`
+	Source   string  `json:"source"`
+	Asset    string  `json:"asset"`
+	Quantity float64 `json:"quantity"`
+	Proceeds float64 `json:"proceeds"`
+	Cost     float64 `json:"cost"`
+	Gain     float64 `json:"gain"`
+}
+
+type TaxReport struct {
+	Year          int                `json:"year"`
+	Currency      string             `json:"currency"`
+	Method        string             `json:"method"`
+	Disposals     []Disposal         `json:"disposals"`
+	TotalProceeds float64            `json:"totalProceeds"`
+	TotalCost     float64            `json:"totalCost"`
+	TotalGain     float64            `json:"totalGain"`
+	GainByAsset   map[string]float64 `json:"gainByAsset"`
+	// Value of staking rewards, interest and other income received during the year
+	TotalIncome float64 `json:"totalIncome"`
+}
+
+// Calculate gains realized by sales for cash and income received during a year, of a source or all of them when empty
+// Costs are calculated from transactions of all sources, internal transfers carrying them over
+func CalculateTax(transactions []Transaction, prices *PriceHistory, year int, source string) *TaxReport {
+	method := CostBasisMethod()
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	to := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	report := &TaxReport{
+		Year:        year,
+		Currency:    prices.Currency(),
+		Method:      method,
+		Disposals:   []Disposal{},
+		GainByAsset: make(map[string]float64),
+	}
+
+	positions := CalculateCostBasis(transactions, MatchTransfers(transactions), method, prices)
+	for _, disposal := range positions.Disposals() {
+		if disposal.Time < from || disposal.Time >= to || (source != "" && disposal.Source != source) {
+			continue
+		}
+
+		report.Disposals = append(report.Disposals, disposal)
+		report.TotalProceeds += disposal.Proceeds
+		report.TotalCost += disposal.Cost
+		report.TotalGain += disposal.Gain
+		report.GainByAsset[disposal.Asset] += disposal.Gain
+	}
+
+	income := []Transaction{}
+	for _, tx := range transactions {
+		if source == "" || tx.Source == source {
+			income = append(income, tx)
+		}
+	}
+	report.TotalIncome = CalculateIncome(income, prices, from, to).TotalValue
+
+	return report
+}
@@ -0,0 +1,75 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCalculateTax(t *testing.T) {
+	transactions := []Transaction{
+		{ID: "1", Source: "kraken", Type: TypeBuy, Time: testTime("2021-06-01", 0), Asset: "ETH", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 2000},
+		{ID: "2", Source: "binance", Type: TypeBuy, Time: testTime("2021-12-01", 0), Asset: "BTC", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 40000},
+		{ID: "3", Source: "kraken", Type: TypeBuy, Time: testTime("2022-01-10", 0), Asset: "ETH", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 3000},
+		{ID: "4", Source: "kraken", Type: TypeSell, Time: testTime("2022-02-01", 0), Asset: "ETH", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 2500},
+		{ID: "5", Source: "kraken", Type: TypeIncome, Time: testTime("2022-03-01", 0), Asset: "ETH", Amount: 0.1},
+		{ID: "6", Source: "binance", Type: TypeSell, Time: testTime("2022-06-01", 0), Asset: "BTC", Amount: 1, QuoteAsset: "EUR", QuoteAmount: 25000},
+		{ID: "7", Source: "kraken", Type: TypeSell, Time: testTime("2023-01-05", 0), Asset: "ETH", Amount: 1.1, QuoteAsset: "EUR", QuoteAmount: 3500},
+	}
+	prices := testPrices(map[string]string{"ETH": "ethereum"}, map[string]float64{"ethereum/2022-03-01/eur": 2800})
+
+	tests := []struct {
+		name        string
+		method      string
+		year        int
+		source      string
+		disposals   int
+		gainByAsset map[string]float64
+		totalIncome float64
+	}{
+		{name: "fifo", method: MethodFIFO, year: 2022, disposals: 2, gainByAsset: map[string]float64{"ETH": 500, "BTC": -15000}, totalIncome: 280},
+		{name: "lifo of a source", method: MethodLIFO, year: 2022, source: "kraken", disposals: 1, gainByAsset: map[string]float64{"ETH": -500}, totalIncome: 280},
+		{name: "average of another source", method: MethodAverage, year: 2022, source: "binance", disposals: 1, gainByAsset: map[string]float64{"BTC": -15000}},
+		// Income costs its value when received, the remaining lots being 1 ETH for 3000 then 0.1 ETH for 280
+		{name: "next year", method: MethodFIFO, year: 2023, disposals: 1, gainByAsset: map[string]float64{"ETH": 3500 - 3000 - 280}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("tracklet.costBasisMethod", tt.method)
+			t.Cleanup(func() { viper.Set("tracklet.costBasisMethod", nil) })
+
+			report := CalculateTax(transactions, prices, tt.year, tt.source)
+
+			if report.Method != tt.method || report.Currency != "EUR" {
+				t.Errorf("report method and currency = %s %s, want %s EUR", report.Method, report.Currency, tt.method)
+			}
+			if len(report.Disposals) != tt.disposals {
+				t.Fatalf("got %d disposals, want %d", len(report.Disposals), tt.disposals)
+			}
+
+			proceeds, cost, gain := 0.0, 0.0, 0.0
+			for _, disposal := range report.Disposals {
+				proceeds += disposal.Proceeds
+				cost += disposal.Cost
+				gain += disposal.Gain
+			}
+			if !almostEqual(report.TotalProceeds, proceeds) || !almostEqual(report.TotalCost, cost) || !almostEqual(report.TotalGain, gain) {
+				t.Errorf("totals = %f - %f = %f, want %f - %f = %f", report.TotalProceeds, report.TotalCost, report.TotalGain, proceeds, cost, gain)
+			}
+
+			if len(report.GainByAsset) != len(tt.gainByAsset) {
+				t.Errorf("got gains of %d assets, want %d", len(report.GainByAsset), len(tt.gainByAsset))
+			}
+			for asset, want := range tt.gainByAsset {
+				if !almostEqual(report.GainByAsset[asset], want) {
+					t.Errorf("%s gain = %f, want %f", asset, report.GainByAsset[asset], want)
+				}
+			}
+
+			if !almostEqual(report.TotalIncome, tt.totalIncome) {
+				t.Errorf("total income = %f, want %f", report.TotalIncome, tt.totalIncome)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Handles matching of crypto withdrawals to deposits across sources
+package ledger
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// A withdrawal and the deposit it ended up as, the asset staying owned
+type Transfer struct {
+	Asset      string      `json:"asset"`
+	Withdrawal Transaction `json:"withdrawal"`
+	Deposit    Transaction `json:"deposit"`
+	// Difference between the amounts sent and received, withdrawal and network fees included
+	Fee float64 `json:"fee"`
+}
+
+type Transfers struct {
+	Matched   []Transfer    `json:"matched"`
+	Unmatched []Transaction `json:"unmatched"`
+	byKey     map[string]*Transfer
+}
+
+// Unique key of a transaction across sources
+func key(tx Transaction) string {
+	return fmt.Sprintf("%s/%s", tx.Source, tx.ID)
+}
+
+// Return the transfer a transaction is part of, nil when it is not an internal transfer
+func (t *Transfers) Find(tx Transaction) *Transfer {
+	if t == nil {
+		return nil
+	}
+
+	return t.byKey[key(tx)]
+}
+
+// Return the amount leaving the source of a withdrawal, its fee in the withdrawn asset being charged on top
+func sentAmount(withdrawal Transaction) float64 {
+	if withdrawal.FeeAsset == withdrawal.Asset {
+		return withdrawal.Amount + withdrawal.FeeAmount
+	}
+
+	return withdrawal.Amount
+}
+
+// Relative difference between a deposit amount and what the withdrawal could have delivered,
+// withdrawal fees being either included in its amount or charged on top of it
+func amountDifference(withdrawal Transaction, deposit Transaction) float64 {
+	expected := []float64{withdrawal.Amount}
+	if withdrawal.FeeAsset == withdrawal.Asset && withdrawal.FeeAmount > 0 {
+		expected = append(expected, withdrawal.Amount-withdrawal.FeeAmount)
+	}
+
+	difference := math.Inf(1)
+	for _, e := range expected {
+		if e > 0 {
+			difference = math.Min(difference, math.Abs(deposit.Amount-e)/e)
+		}
+	}
+
+	return difference
+}
+
+// Pair crypto withdrawals with deposits of any source
+// Transactions sharing a tx hash are paired first, then by asset, amount within
+// `transfers.amountTolerance` percent and deposit time within `transfers.window` hours
+func MatchTransfers(transactions []Transaction) *Transfers {
+	window := viper.GetInt64("transfers.window") * 3600 * 1000
+	tolerance := viper.GetFloat64("transfers.amountTolerance") / 100
+	// Exchanges and chains clocks may disagree a little
+	skew := int64(10 * 60 * 1000)
+
+	withdrawals, deposits := []Transaction{}, []Transaction{}
+	for _, tx := range transactions {
+		if IsFiat(tx.Asset) {
+			continue
+		}
+		switch tx.Type {
+		case TypeWithdraw:
+			withdrawals = append(withdrawals, tx)
+		case TypeDeposit:
+			deposits = append(deposits, tx)
+		}
+	}
+	Sort(withdrawals)
+	Sort(deposits)
+
+	used := make([]bool, len(deposits))
+	transfers := &Transfers{
+		Matched:   []Transfer{},
+		Unmatched: []Transaction{},
+		byKey:     make(map[string]*Transfer),
+	}
+	unmatchedWithdrawals := []Transaction{}
+
+	match := func(withdrawal Transaction, i int) {
+		used[i] = true
+		transfers.Matched = append(transfers.Matched, Transfer{
+			Asset:      withdrawal.Asset,
+			Withdrawal: withdrawal,
+			Deposit:    deposits[i],
+			Fee:        sentAmount(withdrawal) - deposits[i].Amount,
+		})
+	}
+
+	// Tx hashes identify transfers without doubt
+	pending := []Transaction{}
+	for _, withdrawal := range withdrawals {
+		found := -1
+		if withdrawal.TxHash != "" {
+			for i, deposit := range deposits {
+				if !used[i] && deposit.Asset == withdrawal.Asset && key(deposit) != key(withdrawal) && strings.EqualFold(deposit.TxHash, withdrawal.TxHash) {
+					found = i
+					break
+				}
+			}
+		}
+
+		if found >= 0 {
+			match(withdrawal, found)
+		} else {
+			pending = append(pending, withdrawal)
+		}
+	}
+
+	for _, withdrawal := range pending {
+		best, bestDifference := -1, math.Inf(1)
+		start := sort.Search(len(deposits), func(i int) bool {
+			return deposits[i].Time >= withdrawal.Time-skew
+		})
+
+		for i := start; i < len(deposits) && deposits[i].Time <= withdrawal.Time+window; i++ {
+			deposit := deposits[i]
+			if used[i] || deposit.Asset != withdrawal.Asset || key(deposit) == key(withdrawal) {
+				continue
+			}
+			// Deposits with a different known hash belong to another transfer
+			if withdrawal.TxHash != "" && deposit.TxHash != "" && !strings.EqualFold(withdrawal.TxHash, deposit.TxHash) {
+				continue
+			}
+
+			if difference := amountDifference(withdrawal, deposit); difference <= tolerance && difference < bestDifference {
+				best, bestDifference = i, difference
+			}
+		}
+
+		if best >= 0 {
+			match(withdrawal, best)
+		} else {
+			unmatchedWithdrawals = append(unmatchedWithdrawals, withdrawal)
+		}
+	}
+
+	transfers.Unmatched = append(transfers.Unmatched, unmatchedWithdrawals...)
+	for i, deposit := range deposits {
+		if !used[i] {
+			transfers.Unmatched = append(transfers.Unmatched, deposit)
+		}
+	}
+	Sort(transfers.Unmatched)
+
+	sort.SliceStable(transfers.Matched, func(i, j int) bool {
+		return transfers.Matched[i].Withdrawal.Time < transfers.Matched[j].Withdrawal.Time
+	})
+	for i := range transfers.Matched {
+		transfers.byKey[key(transfers.Matched[i].Withdrawal)] = &transfers.Matched[i]
+		transfers.byKey[key(transfers.Matched[i].Deposit)] = &transfers.Matched[i]
+	}
+
+	return transfers
+}
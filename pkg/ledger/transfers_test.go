@@ -0,0 +1,99 @@
+package ledger
+
+import (
+	"testing"
+)
+
+func TestMatchTransfers(t *testing.T) {
+	setTransferConfig(t)
+
+	day := testTime("2022-03-01", 0)
+	hour := int64(3600 * 1000)
+
+	tests := []struct {
+		name         string
+		transactions []Transaction
+		// Withdrawal then deposit IDs of each matched transfer
+		matched   [][2]string
+		fees      []float64
+		unmatched int
+	}{
+		{
+			name: "fee charged on top",
+			transactions: []Transaction{
+				{ID: "w", Source: "kraken", Type: TypeWithdraw, Time: day, Asset: "BTC", Amount: 0.5, FeeAsset: "BTC", FeeAmount: 0.001},
+				{ID: "d", Source: "binance", Type: TypeDeposit, Time: day + hour, Asset: "BTC", Amount: 0.5},
+			},
+			matched: [][2]string{{"w", "d"}},
+			fees:    []float64{0.001},
+		},
+		{
+			name: "network fee taken from the amount",
+			transactions: []Transaction{
+				{ID: "w", Source: "kraken", Type: TypeWithdraw, Time: day, Asset: "ETH", Amount: 2},
+				{ID: "d", Source: "evm", Type: TypeDeposit, Time: day + hour, Asset: "ETH", Amount: 1.99},
+			},
+			matched: [][2]string{{"w", "d"}},
+			fees:    []float64{0.01},
+		},
+		{
+			name: "same hash beyond the window",
+			transactions: []Transaction{
+				{ID: "w", Source: "kraken", Type: TypeWithdraw, Time: day, Asset: "BTC", Amount: 1, TxHash: "ABC"},
+				{ID: "other", Source: "binance", Type: TypeDeposit, Time: day + hour, Asset: "BTC", Amount: 1, TxHash: "def"},
+				{ID: "d", Source: "bitcoin", Type: TypeDeposit, Time: day + 48*hour, Asset: "BTC", Amount: 0.9, TxHash: "abc"},
+			},
+			matched:   [][2]string{{"w", "d"}},
+			fees:      []float64{0.1},
+			unmatched: 1,
+		},
+		{
+			name: "closest amount wins",
+			transactions: []Transaction{
+				{ID: "w", Source: "kraken", Type: TypeWithdraw, Time: day, Asset: "BTC", Amount: 1},
+				{ID: "far", Source: "binance", Type: TypeDeposit, Time: day + hour, Asset: "BTC", Amount: 0.995},
+				{ID: "d", Source: "bitcoin", Type: TypeDeposit, Time: day + 2*hour, Asset: "BTC", Amount: 0.999},
+			},
+			matched:   [][2]string{{"w", "d"}},
+			fees:      []float64{0.001},
+			unmatched: 1,
+		},
+		{
+			name: "unmatched",
+			transactions: []Transaction{
+				{ID: "late", Source: "kraken", Type: TypeWithdraw, Time: day, Asset: "BTC", Amount: 1},
+				{ID: "d1", Source: "binance", Type: TypeDeposit, Time: day + 48*hour, Asset: "BTC", Amount: 1},
+				{ID: "other", Source: "kraken", Type: TypeWithdraw, Time: day, Asset: "ETH", Amount: 1},
+				{ID: "d2", Source: "binance", Type: TypeDeposit, Time: day + hour, Asset: "BTC", Amount: 0.5},
+				{ID: "less", Source: "evm", Type: TypeDeposit, Time: day + hour, Asset: "ETH", Amount: 0.5},
+				{ID: "fiat", Source: "kraken", Type: TypeWithdraw, Time: day, Asset: "EUR", Amount: 100},
+				{ID: "fiat", Source: "bitstamp", Type: TypeDeposit, Time: day, Asset: "EUR", Amount: 100},
+			},
+			unmatched: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers := MatchTransfers(tt.transactions)
+
+			if len(transfers.Matched) != len(tt.matched) {
+				t.Fatalf("got %d matched transfers, want %d", len(transfers.Matched), len(tt.matched))
+			}
+			for i, transfer := range transfers.Matched {
+				if transfer.Withdrawal.ID != tt.matched[i][0] || transfer.Deposit.ID != tt.matched[i][1] {
+					t.Errorf("transfer %d = %s -> %s, want %s -> %s", i, transfer.Withdrawal.ID, transfer.Deposit.ID, tt.matched[i][0], tt.matched[i][1])
+				}
+				if !almostEqual(transfer.Fee, tt.fees[i]) {
+					t.Errorf("transfer %d fee = %f, want %f", i, transfer.Fee, tt.fees[i])
+				}
+				if transfers.Find(transfer.Deposit) == nil {
+					t.Errorf("transfer %d deposit not found", i)
+				}
+			}
+			if len(transfers.Unmatched) != tt.unmatched {
+				t.Errorf("got %d unmatched transactions, want %d", len(transfers.Unmatched), tt.unmatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Handles portfolio valuation backfilled from transaction history
+package ledger
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const millisecondsPerDay = 24 * 3600 * 1000
+
+// Performance of a portfolio, deposits and withdrawals being left out, in percent
+type Returns struct {
+	// Chained returns between points, whatever the size of flows
+	TimeWeighted           float64 `json:"timeWeighted"`
+	TimeWeightedAnnualized float64 `json:"timeWeightedAnnualized"`
+	// Annualized internal rate of return of flows and the last value, weighted by their size and time
+	MoneyWeighted float64 `json:"moneyWeighted"`
+}
+
+// Calculate returns of points whose invested amount changes are external flows, happening before their value
+func calculateReturns(points []HistoryPoint) *Returns {
+	if len(points) == 0 {
+		return nil
+	}
+
+	returns := &Returns{}
+	factor, previous := 1.0, HistoryPoint{}
+	for _, point := range points {
+		if base := previous.Value + point.Invested - previous.Invested; base > 0 {
+			factor *= point.Value / base
+		}
+		previous = point
+	}
+	returns.TimeWeighted = (factor - 1) * 100
+
+	first, last := points[0], points[len(points)-1]
+	if years := float64(last.Time-first.Time) / millisecondsPerYear; years > 0 && factor > 0 {
+		returns.TimeWeightedAnnualized = (math.Pow(factor, 1/years) - 1) * 100
+	}
+
+	rate, err := internalRateOfReturn(points)
+	if err != nil {
+		log.Warnf("Could not calculate money weighted return: %v", err)
+	} else {
+		returns.MoneyWeighted = rate * 100
+	}
+
+	return returns
+}
+
+// Solve the annual rate at which flows invested and the last value have a zero net present value
+func internalRateOfReturn(points []HistoryPoint) (float64, error) {
+	first, last := points[0], points[len(points)-1]
+
+	npv := func(rate float64) float64 {
+		value, previous := 0.0, 0.0
+		for _, point := range points {
+			years := float64(point.Time-first.Time) / millisecondsPerYear
+			value -= (point.Invested - previous) / math.Pow(1+rate, years)
+			previous = point.Invested
+		}
+		years := float64(last.Time-first.Time) / millisecondsPerYear
+
+		return value + last.Value/math.Pow(1+rate, years)
+	}
+
+	low, high := -0.9999, 10000.0
+	if npv(low)*npv(high) > 0 {
+		return 0, fmt.Errorf("no rate found between %g%% and %g%%", low*100, high*100)
+	}
+
+	for i := 0; i < 200; i++ {
+		middle := (low + high) / 2
+		if npv(low)*npv(middle) <= 0 {
+			high = middle
+		} else {
+			low = middle
+		}
+	}
+
+	return (low + high) / 2, nil
+}
+
+// Request daily prices of every asset held and exchange rates of cash currencies at once
+// Assets unknown to Coingecko are left unpriced, failing to load known ones aborting rather than requesting every day apart
+func preloadPrices(transactions []Transaction, prices *PriceHistory, from int64, to int64) error {
+	preloaded := make(map[string]bool)
+	preload := func(asset string, currency string) error {
+		name := fmt.Sprintf("%s/%s", asset, currency)
+		if preloaded[name] {
+			return nil
+		}
+		preloaded[name] = true
+
+		if _, ok := prices.CoinID(asset); !ok && !IsCash(asset) {
+			return nil
+		}
+		if err := prices.Preload(asset, currency, from, to); err != nil {
+			return fmt.Errorf("could not preload '%s' prices: %w", asset, err)
+		}
+
+		return nil
+	}
+
+	for _, tx := range transactions {
+		for _, asset := range []string{tx.Asset, tx.QuoteAsset, tx.FeeAsset} {
+			var err error
+			switch {
+			case asset == "":
+			case !IsCash(asset):
+				err = preload(prices.Coins().PricedAsset(asset), prices.Currency())
+			case !strings.EqualFold(CashCurrency(asset), prices.Currency()):
+				if err = preload(exchangeRateCoin, CashCurrency(asset)); err == nil {
+					err = preload(exchangeRateCoin, prices.Currency())
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Value of a deposit or withdrawal not matched to another tracked source, in reporting currency
+func externalFlow(tx Transaction, transfers *Transfers, prices *PriceHistory) (float64, error) {
+	if (tx.Type != TypeDeposit && tx.Type != TypeWithdraw) || transfers.Find(tx) != nil {
+		return 0, nil
+	}
+
+	price, err := prices.Price(tx.Asset, tx.Time)
+	if err != nil {
+		return 0, err
+	}
+	if tx.Type == TypeWithdraw {
+		return -price * tx.Amount, nil
+	}
+
+	return price * tx.Amount, nil
+}
+
+// Replay transactions and value holdings at the end of every day, from the first fiat payment until today
+// Invested amounts are the sum of deposits and withdrawals to and from outside tracked sources at market value,
+// and of cash paid beyond deposited balances
+func dailyValues(transactions []Transaction, prices *PriceHistory, now time.Time) ([]HistoryPoint, error) {
+	sorted := append([]Transaction{}, transactions...)
+	Sort(sorted)
+
+	points := []HistoryPoint{}
+	if len(sorted) == 0 {
+		return points, nil
+	}
+
+	start := sorted[0].Time
+	for _, tx := range sorted {
+		if IsFiat(tx.Asset) || IsFiat(tx.QuoteAsset) {
+			start = tx.Time
+			break
+		}
+	}
+	day := time.UnixMilli(start).UTC().Truncate(24 * time.Hour)
+
+	if err := preloadPrices(sorted, prices, day.UnixMilli(), now.UnixMilli()); err != nil {
+		return nil, err
+	}
+	transfers := MatchTransfers(sorted)
+
+	holdings := make(map[string]Holdings)
+	unpriced := make(map[string]bool)
+	invested, next := 0.0, 0
+	for ; !day.After(now); day = day.Add(24 * time.Hour) {
+		end := day.UnixMilli() + millisecondsPerDay
+
+		for ; next < len(sorted) && sorted[next].Time < end; next++ {
+			tx := sorted[next]
+			ApplyTransaction(holdings, &Stats{}, tx)
+
+			flow, err := externalFlow(tx, transfers, prices)
+			if err != nil && !unpriced[tx.Asset] {
+				log.Warnf("Could not value '%s' flow: %v", tx.Asset, err)
+				unpriced[tx.Asset] = true
+			}
+			invested += flow
+
+			// Cash spent without being deposited first, such as card payments, comes from outside
+			for _, asset := range []string{tx.QuoteAsset, tx.FeeAsset} {
+				if h, ok := holdings[asset]; ok && IsCash(asset) && h.Quantity < 0 {
+					payment, err := prices.Convert(-h.Quantity, asset, tx.Time)
+					if err != nil && !unpriced[asset] {
+						log.Warnf("Could not value '%s' payment: %v", asset, err)
+						unpriced[asset] = true
+					}
+					invested += payment
+					delete(holdings, asset)
+				}
+			}
+		}
+
+		// Holdings are valued at the close of the day, the price of the next day start, or at the latest price today
+		closing := end
+		if closing > now.UnixMilli() {
+			closing = now.UnixMilli()
+		}
+
+		point := HistoryPoint{Time: day.UnixMilli(), Invested: invested}
+		for asset, h := range holdings {
+			if h.Quantity <= 1e-12 {
+				continue
+			}
+
+			price, err := prices.Price(asset, closing)
+			if err != nil {
+				if !unpriced[asset] {
+					log.Warnf("Could not value '%s' holdings, counting them as zero: %v", asset, err)
+					unpriced[asset] = true
+				}
+				continue
+			}
+			point.Value += price * h.Quantity
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// Build the portfolio value history of every day since the first fiat payment from transactions, by period
+func BackfillHistory(transactions []Transaction, prices *PriceHistory, period string) (*History, error) {
+	if _, err := periodKey(0, period); err != nil {
+		return nil, err
+	}
+
+	points, err := dailyValues(transactions, prices, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("could not value daily holdings: %w", err)
+	}
+
+	return buildHistory(prices.Currency(), points, period)
+}
@@ -0,0 +1,369 @@
+// Handles data calculation logic for ledger based sources
+package ledger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type Wallet struct {
+	Source   string              `json:"source"`
+	Holdings map[string]Holdings `json:"holdings"`
+	Cash     map[string]Holdings `json:"cash"`
+	Stats    Stats               `json:"stats"`
+	// Ledger transactions, kept to value income once prices are retrieved
+	transactions []Transaction
+}
+
+type Holdings struct {
+	Name         string  `json:"name"`
+	Class        Class   `json:"class,omitempty"`
+	Quantity     float64 `json:"quantity"`
+	CurrentValue float64 `json:"currentValue"`
+	CostBasis    float64 `json:"costBasis,omitempty"`
+}
+
+// Fiat amounts are in reporting currency
+type Stats struct {
+	Currency      string  `json:"currency"`
+	TotalInvested float64 `json:"totalInvested"`
+	// Value of crypto deposited from outside tracked sources
+	TotalDeposited float64 `json:"totalDeposited"`
+	TotalIncome    float64 `json:"totalIncome"`
+	TotalCash      float64 `json:"totalCash"`
+	TotalValue     float64 `json:"totalValue"`
+	GainValue      float64 `json:"gainValue"`
+	TotalAssets    int     `json:"totalAssets"`
+	flows          []fiatFlow
+}
+
+// A fiat amount invested or earned, converted to reporting currency once prices are known
+type fiatFlow struct {
+	currency string
+	amount   float64
+	time     int64
+	income   bool
+}
+
+// Record a fiat amount invested, or taken out when negative
+func (s *Stats) AddInvested(currency string, amount float64, t int64) {
+	s.flows = append(s.flows, fiatFlow{currency: currency, amount: amount, time: t})
+}
+
+// Record a fiat value earned
+func (s *Stats) AddIncome(currency string, amount float64, t int64) {
+	s.flows = append(s.flows, fiatFlow{currency: currency, amount: amount, time: t, income: true})
+}
+
+// Add recorded fiat amounts to totals, converted at their day rate
+func (s *Stats) ConvertFlows(prices *PriceHistory) {
+	s.Currency = prices.Currency()
+
+	for _, flow := range s.flows {
+		value, err := prices.Convert(flow.amount, flow.currency, flow.time)
+		if err != nil {
+			log.Errorf("Could not convert %s amount: %v", flow.currency, err)
+			continue
+		}
+
+		if flow.income {
+			s.TotalIncome += value
+		} else {
+			s.TotalInvested += value
+		}
+	}
+	s.flows = nil
+}
+
+// Create a new Wallet object for a given source
+func NewWallet(source string) *Wallet {
+	return &Wallet{
+		Source:   source,
+		Holdings: make(map[string]Holdings),
+		Cash:     make(map[string]Holdings),
+		Stats: Stats{
+			TotalInvested:  0,
+			TotalDeposited: 0,
+			TotalIncome:    0,
+			TotalCash:      0,
+			TotalValue:     0,
+			GainValue:      0,
+			TotalAssets:    0,
+		},
+	}
+}
+
+// Add a signed quantity to an asset holdings
+func addQuantity(holdings map[string]Holdings, asset string, quantity float64) {
+	if asset == "" || quantity == 0 {
+		return
+	}
+
+	h := holdings[asset]
+	h.Quantity += quantity
+	holdings[asset] = h
+}
+
+// Apply a single ledger transaction to holdings and stats
+func ApplyTransaction(holdings map[string]Holdings, stats *Stats, tx Transaction) {
+	switch tx.Type {
+	case TypeBuy:
+		addQuantity(holdings, tx.Asset, tx.Amount)
+		addQuantity(holdings, tx.QuoteAsset, -tx.QuoteAmount)
+	case TypeSell:
+		addQuantity(holdings, tx.Asset, -tx.Amount)
+		addQuantity(holdings, tx.QuoteAsset, tx.QuoteAmount)
+	case TypeDeposit:
+		addQuantity(holdings, tx.Asset, tx.Amount)
+		if IsCash(tx.Asset) {
+			stats.AddInvested(tx.Asset, tx.Amount, tx.Time)
+		}
+	case TypeWithdraw:
+		addQuantity(holdings, tx.Asset, -tx.Amount)
+		if IsCash(tx.Asset) {
+			stats.AddInvested(tx.Asset, -tx.Amount, tx.Time)
+		}
+	case TypeIncome:
+		addQuantity(holdings, tx.Asset, tx.Amount)
+		if IsCash(tx.QuoteAsset) {
+			stats.AddIncome(tx.QuoteAsset, tx.QuoteAmount, tx.Time)
+		}
+	case TypeFee:
+		addQuantity(holdings, tx.Asset, -tx.Amount)
+	}
+
+	addQuantity(holdings, tx.FeeAsset, -tx.FeeAmount)
+}
+
+// Calculate holdings quantities from all ledger transactions
+func (w *Wallet) CalculateHoldings() error {
+	log.Infof("Calculating %s transactions...", w.Source)
+
+	transactions, err := Load(w.Source)
+	if err != nil {
+		return fmt.Errorf("could not load ledger: %w", err)
+	}
+
+	for _, tx := range transactions {
+		ApplyTransaction(w.Holdings, &w.Stats, tx)
+	}
+	w.transactions = transactions
+
+	return nil
+}
+
+// Calculate the value of income received without a cash quote, such as staking rewards and dividends,
+// at its receipt day price
+func (w *Wallet) calculateIncome(prices *PriceHistory) {
+	log.Info("Calculating income...")
+
+	preloadIncomePrices(w.transactions, prices)
+
+	unpriced := make(map[string]bool)
+	for _, tx := range w.transactions {
+		if tx.Type != TypeIncome || IsCash(tx.QuoteAsset) {
+			continue
+		}
+
+		value, err := prices.Value(tx)
+		if err != nil {
+			if !unpriced[tx.Asset] {
+				log.Warnf("Could not value '%s' income: %v", tx.Asset, err)
+				unpriced[tx.Asset] = true
+			}
+			continue
+		}
+		w.Stats.TotalIncome += value
+	}
+}
+
+// Calculate holdings cost basis, internal transfers from other sources carrying their cost over
+func (w *Wallet) calculateCostBasis(prices *PriceHistory) {
+	log.Info("Calculating cost basis...")
+
+	costs, err := SourceCostBasis(w.Source, prices)
+	if err != nil {
+		log.Errorf("Could not calculate cost basis: %v", err)
+		return
+	}
+
+	ApplyCostBasis(w.Holdings, costs)
+}
+
+// Set holdings cost basis from costs by asset
+func ApplyCostBasis(holdings map[string]Holdings, costs map[string]float64) {
+	for asset, cost := range costs {
+		if d, ok := holdings[asset]; ok {
+			d.CostBasis = cost
+			holdings[asset] = d
+		}
+	}
+}
+
+// Calculate the value of crypto deposited from outside tracked sources
+func (w *Wallet) calculateDeposited(prices *PriceHistory) {
+	log.Info("Calculating external deposits...")
+
+	CalculateDeposited(w.Source, &w.Stats, prices)
+}
+
+// Set stats external deposits value, warning about deposits of unknown cost
+func CalculateDeposited(source string, stats *Stats, prices *PriceHistory) {
+	value, unknown, err := DepositedValue(source, prices)
+	if err != nil {
+		log.Errorf("Could not calculate external deposits value: %v", err)
+		return
+	}
+
+	if unknown > 0 {
+		log.Warnf("%d external deposits of '%s' have an unknown cost, assign one with `tracklet deposits assign`", unknown, source)
+	}
+	stats.TotalDeposited = value
+}
+
+// Retrieve current prices for all assets, in reporting currency
+func (w *Wallet) calculatePrices(coinList *coingecko.CoinList, prices *PriceHistory) {
+	log.Info("Calculating prices...")
+
+	CalculatePrices(w.Holdings, coinList, prices)
+}
+
+// Set holdings class and current value, cash being converted at today's rate and derivatives valued as the asset they track
+func CalculatePrices(holdings map[string]Holdings, coinList *coingecko.CoinList, prices *PriceHistory) {
+	currency := strings.ToLower(prices.Currency())
+
+	for asset, d := range holdings {
+		d.Class = prices.Coins().Classify(asset).Class
+		holdings[asset] = d
+
+		if IsCash(asset) {
+			value, err := prices.Convert(d.Quantity, asset, time.Now().UnixMilli())
+			if err != nil {
+				log.Errorf("Could not convert %s holdings: %v", asset, err)
+				continue
+			}
+
+			d.Name = strings.ToUpper(asset)
+			d.CurrentValue = value
+			holdings[asset] = d
+			continue
+		}
+
+		pricedAsset := prices.Coins().PricedAsset(asset)
+		for _, coin := range coinList.Coins {
+			if strings.EqualFold(pricedAsset, coin.Symbol) {
+				log.Infof("Getting '%s' coin market data", coin.Name)
+
+				coinPrice, err := coingecko.GetCoinPrice(coin.ID)
+				if err != nil {
+					log.Errorf("Could not get coin price: %v", err)
+					break
+				}
+
+				d.CurrentValue = coinPrice.MarketData.CurrentPrice[currency] * d.Quantity
+				d.Name = coin.Name
+				holdings[asset] = d
+				break
+			}
+		}
+	}
+}
+
+// Move cash out of holdings, returning it by asset
+func SeparateCash(holdings map[string]Holdings) map[string]Holdings {
+	cash := make(map[string]Holdings)
+	for asset, d := range holdings {
+		if IsCash(asset) {
+			cash[asset] = d
+			delete(holdings, asset)
+		}
+	}
+
+	return cash
+}
+
+// Calculate global wallet stats, from holdings and cash
+func CalculateStats(holdings map[string]Holdings, cash map[string]Holdings, stats *Stats, prices *PriceHistory) {
+	stats.ConvertFlows(prices)
+
+	for _, asset := range holdings {
+		stats.TotalAssets++
+		stats.TotalValue += asset.CurrentValue
+	}
+
+	for _, asset := range cash {
+		stats.TotalCash += asset.CurrentValue
+		stats.TotalValue += asset.CurrentValue
+	}
+
+	stats.GainValue = (stats.TotalValue - stats.TotalInvested - stats.TotalDeposited)
+}
+
+// Calculate global wallet stats
+func (w *Wallet) calculateStats(prices *PriceHistory) {
+	log.Info("Calculating wallet stats...")
+
+	CalculateStats(w.Holdings, w.Cash, &w.Stats, prices)
+}
+
+// Calculate holdings, their cost and current value, and stats of a source ledger
+func (w *Wallet) Calculate() error {
+	if err := w.CalculateHoldings(); err != nil {
+		return fmt.Errorf("could not calculate transactions: %w", err)
+	}
+
+	log.Info("Getting Coingecko coin list")
+	coinList, err := coingecko.GetCoinList()
+	if err != nil {
+		return fmt.Errorf("could not get coin list: %w", err)
+	}
+	prices := NewPriceHistory(coinList)
+
+	w.calculateCostBasis(prices)
+	w.calculateDeposited(prices)
+	w.calculateIncome(prices)
+	w.calculatePrices(coinList, prices)
+	w.Cash = SeparateCash(w.Holdings)
+	w.calculateStats(prices)
+
+	if err := prices.Save(); err != nil {
+		log.Warnf("Could not cache prices: %v", err)
+	}
+
+	return nil
+}
+
+// Save the wallet to file along with a snapshot of it
+func (w *Wallet) Save() error {
+	if err := utils.WriteToFile(fmt.Sprintf("%s_wallet", w.Source), &w); err != nil {
+		return fmt.Errorf("could not save wallet to file: %w", err)
+	}
+
+	if err := SaveSnapshot(w.Source, w.Holdings, w.Cash, w.Stats); err != nil {
+		return fmt.Errorf("could not save wallet snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Process a source ledger into a wallet, outputting and saving it
+func (w *Wallet) ProcessWallet() error {
+	if err := w.Calculate(); err != nil {
+		return fmt.Errorf("could not calculate wallet: %w", err)
+	}
+
+	if err := utils.OutputResult(w); err != nil {
+		return fmt.Errorf("could not output result: %w", err)
+	}
+
+	if err := w.Save(); err != nil {
+		return fmt.Errorf("could not save wallet: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+// Handles conversion of manual entries to ledger transactions
+package manual
+
+import (
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+)
+
+const source = "manual"
+
+// Convert manual entries to ledger transactions
+// Fiat paid for a buy is deposited beforehand and fiat received from a sell withdrawn afterwards,
+// as Binance fiat payments are, so that it counts as invested money
+// Quote of other types is the fiat value when received or sent, which moves no holdings
+func toLedger(entries []Entry) ([]ledger.Transaction, error) {
+	transactions := []ledger.Transaction{}
+
+	for _, e := range entries {
+		if err := validate(e); err != nil {
+			return nil, fmt.Errorf("invalid manual entry '%s': %w", e.ID, err)
+		}
+
+		t, _ := parseTime(e.Time)
+		tx := ledger.Transaction{
+			ID:          fmt.Sprintf("%s-%s", source, e.ID),
+			Source:      source,
+			Type:        e.Type,
+			Time:        t.UnixMilli(),
+			Asset:       e.Asset,
+			Amount:      e.Amount,
+			QuoteAsset:  e.QuoteAsset,
+			QuoteAmount: e.QuoteAmount,
+			FeeAsset:    e.FeeAsset,
+			FeeAmount:   e.FeeAmount,
+			TxHash:      e.TxHash,
+		}
+
+		fiat := ledger.Transaction{
+			ID:     fmt.Sprintf("%s-fiat", tx.ID),
+			Source: source,
+			Time:   tx.Time,
+			Asset:  e.QuoteAsset,
+		}
+
+		switch {
+		case e.Type == ledger.TypeBuy && ledger.IsFiat(e.QuoteAsset):
+			fiat.Type = ledger.TypeDeposit
+			fiat.Amount = e.QuoteAmount
+			if e.FeeAsset == e.QuoteAsset {
+				fiat.Amount += e.FeeAmount
+			}
+			transactions = append(transactions, fiat, tx)
+		case e.Type == ledger.TypeSell && ledger.IsFiat(e.QuoteAsset):
+			fiat.Type = ledger.TypeWithdraw
+			fiat.Amount = e.QuoteAmount
+			if e.FeeAsset == e.QuoteAsset {
+				fiat.Amount -= e.FeeAmount
+			}
+			transactions = append(transactions, tx, fiat)
+		default:
+			transactions = append(transactions, tx)
+		}
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,217 @@
+// Handles the manual source, kept in a user editable YAML or JSON file
+package manual
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// A manually recorded transaction, quote and fee amounts holding the fiat cost
+type Entry struct {
+	ID          string      `yaml:"id" json:"id"`
+	Time        string      `yaml:"time" json:"time"`
+	Type        ledger.Type `yaml:"type" json:"type"`
+	Asset       string      `yaml:"asset" json:"asset"`
+	Amount      float64     `yaml:"amount" json:"amount"`
+	QuoteAsset  string      `yaml:"quoteAsset,omitempty" json:"quoteAsset,omitempty"`
+	QuoteAmount float64     `yaml:"quoteAmount,omitempty" json:"quoteAmount,omitempty"`
+	FeeAsset    string      `yaml:"feeAsset,omitempty" json:"feeAsset,omitempty"`
+	FeeAmount   float64     `yaml:"feeAmount,omitempty" json:"feeAmount,omitempty"`
+	TxHash      string      `yaml:"txHash,omitempty" json:"txHash,omitempty"`
+	Note        string      `yaml:"note,omitempty" json:"note,omitempty"`
+}
+
+// Return the manual ledger file path, JSON being used for a .json extension and YAML otherwise
+func filePath() (string, error) {
+	if file := viper.GetString("manual.file"); file != "" {
+		return os.ExpandEnv(file), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get user homedir: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".tracklet", "manual.yaml"), nil
+}
+
+// Load all manual entries, an absent file meaning no entry
+func Load() ([]Entry, error) {
+	path, err := filePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read manual ledger: %w", err)
+	}
+
+	entries := []Entry{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &entries)
+	} else {
+		err = yaml.Unmarshal(data, &entries)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal manual ledger '%s': %w", path, err)
+	}
+
+	return entries, nil
+}
+
+// Write manual entries to file and refresh the manual source ledger
+func save(entries []Entry) error {
+	path, err := filePath()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(entries, "", "  ")
+	} else {
+		data, err = yaml.Marshal(entries)
+	}
+	if err != nil {
+		return fmt.Errorf("could not marshal manual ledger: %w", err)
+	}
+
+	if err := ioutil.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("could not write manual ledger: %w", err)
+	}
+
+	return Sync()
+}
+
+// Parse an entry time, accepting dates with or without time of day
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range []string{timeLayout, time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown time format '%s', expected '%s'", value, timeLayout)
+}
+
+// Check an entry holds everything its type needs
+func validate(entry Entry) error {
+	switch entry.Type {
+	case ledger.TypeBuy, ledger.TypeSell:
+		if entry.QuoteAsset == "" || entry.QuoteAmount <= 0 {
+			return fmt.Errorf("%s needs a quote asset and amount", entry.Type)
+		}
+	case ledger.TypeDeposit, ledger.TypeWithdraw, ledger.TypeIncome, ledger.TypeFee:
+	default:
+		return fmt.Errorf("unknown type '%s'", entry.Type)
+	}
+
+	if entry.Asset == "" || entry.Amount <= 0 {
+		return errors.New("an asset and a positive amount are required")
+	}
+
+	if _, err := parseTime(entry.Time); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Record a new manual entry, returning it with its id
+func Add(entry Entry) (*Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Time == "" {
+		entry.Time = time.Now().UTC().Format(timeLayout)
+	}
+	entry.Asset = strings.ToUpper(entry.Asset)
+	entry.QuoteAsset = strings.ToUpper(entry.QuoteAsset)
+	entry.FeeAsset = strings.ToUpper(entry.FeeAsset)
+
+	if err := validate(entry); err != nil {
+		return nil, fmt.Errorf("invalid entry: %w", err)
+	}
+	t, _ := parseTime(entry.Time)
+	entry.Time = t.UTC().Format(timeLayout)
+
+	// Ids are sequential numbers
+	next := 1
+	for _, e := range entries {
+		if n, err := strconv.Atoi(e.ID); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	entry.ID = strconv.Itoa(next)
+
+	entries = append(entries, entry)
+	if err := save(entries); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Remove a manual entry by id
+func Remove(id string) error {
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+
+	kept := []Entry{}
+	for _, e := range entries {
+		if e.ID != id {
+			kept = append(kept, e)
+		}
+	}
+
+	if len(kept) == len(entries) {
+		return fmt.Errorf("no manual entry with id '%s'", id)
+	}
+
+	return save(kept)
+}
+
+// Rebuild the manual source ledger from the manual file, which may have been edited by hand
+func Sync() error {
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+
+	transactions, err := toLedger(entries)
+	if err != nil {
+		return err
+	}
+
+	if err := ledger.Replace(source, transactions); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Create a new Wallet object computed from the manual ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
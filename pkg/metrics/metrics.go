@@ -0,0 +1,142 @@
+// Handles fetcher health metrics, shared between runs through a data file
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+)
+
+const metricsFile = "fetcher_metrics"
+
+// Requests made to a source API and when its datasets were last synced
+type FetcherStats struct {
+	Requests             int64   `json:"requests"`
+	Retries              int64   `json:"retries"`
+	RateLimitWaits       int64   `json:"rateLimitWaits"`
+	RateLimitWaitSeconds float64 `json:"rateLimitWaitSeconds"`
+	// Unix milliseconds of the last successful sync, by dataset
+	LastSync map[string]int64 `json:"lastSync"`
+}
+
+var (
+	mutex sync.Mutex
+	// Stats recorded since the last flush, by source
+	pending = make(map[string]*FetcherStats)
+)
+
+// Return the pending stats of a source, creating them if needed
+func get(source string) *FetcherStats {
+	if pending[source] == nil {
+		pending[source] = &FetcherStats{LastSync: make(map[string]int64)}
+	}
+
+	return pending[source]
+}
+
+// Count a request attempt to a source API
+func Request(source string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	get(source).Requests++
+}
+
+// Count a retry of a failed request, waits after a rate limit response being counted apart
+func Retry(source string, err error, wait time.Duration) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	stats := get(source)
+	stats.Retries++
+	if err != nil && strings.Contains(err.Error(), "429") {
+		stats.RateLimitWaits++
+		stats.RateLimitWaitSeconds += wait.Seconds()
+	}
+}
+
+// Record a dataset of a source as successfully synced now
+func Synced(source string, dataset string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	get(source).LastSync[dataset] = time.Now().UnixMilli()
+}
+
+// Load stats saved by previous runs, by source
+func load() (map[string]*FetcherStats, error) {
+	saved := make(map[string]*FetcherStats)
+	if !utils.FileExists(fmt.Sprintf("%s.json", metricsFile)) {
+		return saved, nil
+	}
+
+	if err := json.Unmarshal(utils.LoadFromFile(fmt.Sprintf("%s.json", metricsFile)), &saved); err != nil {
+		return nil, fmt.Errorf("could not unmarshal fetcher metrics: %w", err)
+	}
+
+	return saved, nil
+}
+
+// Add pending stats to saved ones
+func merge(saved map[string]*FetcherStats) {
+	for source, stats := range pending {
+		if saved[source] == nil {
+			saved[source] = &FetcherStats{}
+		}
+		total := saved[source]
+		if total.LastSync == nil {
+			total.LastSync = make(map[string]int64)
+		}
+
+		total.Requests += stats.Requests
+		total.Retries += stats.Retries
+		total.RateLimitWaits += stats.RateLimitWaits
+		total.RateLimitWaitSeconds += stats.RateLimitWaitSeconds
+		for dataset, t := range stats.LastSync {
+			if t > total.LastSync[dataset] {
+				total.LastSync[dataset] = t
+			}
+		}
+	}
+}
+
+// Return stats of every source, saved and pending ones added up
+func Stats() (map[string]*FetcherStats, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	stats, err := load()
+	if err != nil {
+		return nil, err
+	}
+	merge(stats)
+
+	return stats, nil
+}
+
+// Save pending stats along with those of previous runs
+func Flush() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	stats, err := load()
+	if err != nil {
+		return err
+	}
+	merge(stats)
+
+	if err := utils.WriteToFile(metricsFile, stats); err != nil {
+		return fmt.Errorf("could not save fetcher metrics: %w", err)
+	}
+	pending = make(map[string]*FetcherStats)
+
+	return nil
+}
@@ -0,0 +1,165 @@
+// Handles OKX API endpoints logic
+package okx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	accountBillsEndpoint = "/api/v5/account/bills-archive"
+	assetBillsEndpoint   = "/api/v5/asset/bills"
+	fillsEndpoint        = "/api/v5/trade/fills-history"
+	pageSize             = 100
+)
+
+// Instrument types fills are fetched for
+var instTypes = []string{"SPOT", "MARGIN", "SWAP", "FUTURES", "OPTION"}
+
+type Bill struct {
+	BillID   string `json:"billId"`
+	Ccy      string `json:"ccy"`
+	BalChg   string `json:"balChg"`
+	Bal      string `json:"bal"`
+	Type     string `json:"type"`
+	SubType  string `json:"subType"`
+	InstID   string `json:"instId"`
+	InstType string `json:"instType"`
+	Fee      string `json:"fee"`
+	Pnl      string `json:"pnl"`
+	Ts       string `json:"ts"`
+}
+
+type Fill struct {
+	InstType string `json:"instType"`
+	InstID   string `json:"instId"`
+	TradeID  string `json:"tradeId"`
+	OrdID    string `json:"ordId"`
+	BillID   string `json:"billId"`
+	FillPx   string `json:"fillPx"`
+	FillSz   string `json:"fillSz"`
+	Side     string `json:"side"`
+	Fee      string `json:"fee"`
+	FeeCcy   string `json:"feeCcy"`
+	Ts       string `json:"ts"`
+}
+
+// Check if a Unix milliseconds string is older than the configured history
+func isTooOld(ts string, oldest int64) bool {
+	t, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return t < oldest
+}
+
+// Request every page of a bill id paginated endpoint, newest first
+func getBills(endpoint string, params map[string]string) ([]Bill, error) {
+	client := NewClient()
+	bills := []Bill{}
+	oldest := time.Now().AddDate(0, 0, -client.MaxHistory).UnixMilli()
+	after := ""
+
+	for {
+		params["limit"] = fmt.Sprint(pageSize)
+		if after != "" {
+			params["after"] = after
+		}
+
+		body, err := client.RequestWithRetries(endpoint, params)
+		if err != nil {
+			return nil, err
+		}
+
+		billsPage := []Bill{}
+		if err := json.Unmarshal(body, &billsPage); err != nil {
+			return nil, fmt.Errorf("could not unmarshal bills: %w", err)
+		}
+
+		reachedOldest := false
+		for _, bill := range billsPage {
+			if isTooOld(bill.Ts, oldest) {
+				reachedOldest = true
+				break
+			}
+			bills = append(bills, bill)
+		}
+
+		if reachedOldest || len(billsPage) < pageSize {
+			break
+		}
+		after = billsPage[len(billsPage)-1].BillID
+	}
+
+	return bills, nil
+}
+
+// Get OKX trading account bills (trades, funding fees, interests, pnl...)
+func GetAccountBills() (*[]Bill, error) {
+	bills, err := getBills(accountBillsEndpoint, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("could not request account bills endpoint: %w", err)
+	}
+
+	return &bills, nil
+}
+
+// Get OKX funding account bills (deposits, withdrawals, transfers...)
+func GetAssetBills() (*[]Bill, error) {
+	bills, err := getBills(assetBillsEndpoint, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("could not request asset bills endpoint: %w", err)
+	}
+
+	return &bills, nil
+}
+
+// Get OKX fills history for every instrument type
+func GetFills() (*[]Fill, error) {
+	client := NewClient()
+	fills := []Fill{}
+	oldest := time.Now().AddDate(0, 0, -client.MaxHistory).UnixMilli()
+
+	for _, instType := range instTypes {
+		after := ""
+
+		for {
+			params := map[string]string{
+				"instType": instType,
+				"limit":    fmt.Sprint(pageSize),
+			}
+			if after != "" {
+				params["after"] = after
+			}
+
+			body, err := client.RequestWithRetries(fillsEndpoint, params)
+			if err != nil {
+				return nil, fmt.Errorf("could not request fills endpoint: %w", err)
+			}
+
+			fillsPage := []Fill{}
+			if err := json.Unmarshal(body, &fillsPage); err != nil {
+				return nil, fmt.Errorf("could not unmarshal fills: %w", err)
+			}
+
+			reachedOldest := false
+			for _, fill := range fillsPage {
+				if isTooOld(fill.Ts, oldest) {
+					reachedOldest = true
+					break
+				}
+				fills = append(fills, fill)
+			}
+
+			if reachedOldest || len(fillsPage) < pageSize {
+				break
+			}
+			after = fillsPage[len(fillsPage)-1].BillID
+		}
+	}
+
+	return &fills, nil
+}
@@ -0,0 +1,161 @@
+// Handles HTTP requests logic
+package okx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	APIKey     string
+	SecretKey  string
+	Passphrase string
+	RetryDelay time.Duration
+	MaxRetries int
+	MaxHistory int
+}
+
+type apiResponse struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Create a new Client object
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: time.Second * viper.GetDuration("tracklet.timeout"),
+		},
+		BaseURL:    viper.GetString("exchanges.okx.apiBaseURL"),
+		APIKey:     viper.GetString("exchanges.okx.apiKey"),
+		SecretKey:  viper.GetString("exchanges.okx.secretKey"),
+		Passphrase: viper.GetString("exchanges.okx.passphrase"),
+		RetryDelay: viper.GetDuration("tracklet.retryDelay"),
+		MaxRetries: viper.GetInt("tracklet.maxRetries"),
+		MaxHistory: viper.GetInt("tracklet.maxHistory"),
+	}
+}
+
+// Generate a HMAC signed message for authorizing API requests
+func (c *Client) generateSignature(message string) (string, error) {
+	mac := hmac.New(sha256.New, []byte(c.SecretKey))
+	_, err := mac.Write([]byte(message))
+	if err != nil {
+		return "", fmt.Errorf("could not generate hmac: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Build http query string
+func (c *Client) buildQueryString(q url.Values, params map[string]string) url.Values {
+	if len(params) == 0 {
+		return nil
+	}
+	for elem := range params {
+		q.Add(elem, params[elem])
+	}
+
+	return q
+}
+
+// HTTP get request for a given OKX API endpoint, returning its data
+func (c *Client) request(endpoint string, parameters map[string]string) ([]byte, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", c.BaseURL, endpoint), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+
+	q := req.URL.Query()
+
+	queryString := c.buildQueryString(q, parameters)
+	if queryString != nil {
+		req.URL.RawQuery = queryString.Encode()
+	}
+
+	requestPath := req.URL.Path
+	if req.URL.RawQuery != "" {
+		requestPath = fmt.Sprintf("%s?%s", requestPath, req.URL.RawQuery)
+	}
+
+	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	signature, err := c.generateSignature(fmt.Sprintf("%s%s%s", timestamp, req.Method, requestPath))
+	if err != nil {
+		return nil, fmt.Errorf("error while generating signature: %w", err)
+	}
+
+	req.Header.Add("OK-ACCESS-KEY", c.APIKey)
+	req.Header.Add("OK-ACCESS-SIGN", signature)
+	req.Header.Add("OK-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Add("OK-ACCESS-PASSPHRASE", c.Passphrase)
+
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non valid HTTP status code : %s", response.Status)
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading body: %w", err)
+	}
+
+	okxResponse := apiResponse{}
+	if err := json.Unmarshal(body, &okxResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response: %w", err)
+	}
+
+	if okxResponse.Code != "0" {
+		return nil, fmt.Errorf("api error %s: %s", okxResponse.Code, okxResponse.Msg)
+	}
+
+	return okxResponse.Data, nil
+}
+
+// Retry mechanism for HTTP requests
+func (c *Client) RequestWithRetries(endpoint string, parameters map[string]string) ([]byte, error) {
+	var body []byte
+	var err error
+	retries := 0
+
+	for {
+		metrics.Request(source)
+		body, err = c.request(endpoint, parameters)
+		if body != nil {
+			break
+		}
+
+		if retries == c.MaxRetries {
+			log.Warn("Max retries exceeded...")
+			break
+		}
+
+		retries++
+		metrics.Retry(source, err, time.Second*c.RetryDelay)
+		log.Warnf("Retrying... [%d/%d]: %v", retries, c.MaxRetries, err)
+		time.Sleep(time.Second * c.RetryDelay)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not request '%s' endpoint: %w", endpoint, err)
+	}
+
+	return body, err
+}
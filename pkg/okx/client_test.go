@@ -0,0 +1,117 @@
+package okx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// HMAC-SHA256 test case 2 of RFC 4231, OKX signatures being base64 encoded
+func TestGenerateSignature(t *testing.T) {
+	client := &Client{SecretKey: "Jefe"}
+
+	got, err := client.generateSignature("what do ya want for nothing?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "W9zBRr9gdU5qBCQmCJV1x1oAPwidJzmDnexYuWTsOEM="
+	if got != want {
+		t.Errorf("generateSignature() = %s, want %s", got, want)
+	}
+}
+
+// Serve bills newest first by pages of the requested limit, checking requests are signed
+// Bills are numbered from count down to 1, those numbered up to old being older than the history
+func billsServer(t *testing.T, count int, old int) *int {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		timestamp := r.Header.Get("OK-ACCESS-TIMESTAMP")
+		if _, err := time.Parse("2006-01-02T15:04:05.000Z", timestamp); err != nil {
+			t.Errorf("invalid timestamp %q", timestamp)
+		}
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(timestamp + "GET" + r.URL.Path + "?" + r.URL.RawQuery))
+		if r.Header.Get("OK-ACCESS-KEY") != "key" || r.Header.Get("OK-ACCESS-PASSPHRASE") != "pass" || r.Header.Get("OK-ACCESS-SIGN") != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("request %s is not signed", r.URL)
+		}
+
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		first := count
+		if after := r.URL.Query().Get("after"); after != "" {
+			first, _ = strconv.Atoi(after)
+			first--
+		}
+
+		bills := []Bill{}
+		for id := first; id > 0 && len(bills) < limit; id-- {
+			ts := time.Now().AddDate(0, 0, -1)
+			if id <= old {
+				ts = time.Now().AddDate(-2, 0, 0)
+			}
+			bills = append(bills, Bill{BillID: fmt.Sprint(id), Ts: fmt.Sprint(ts.UnixMilli())})
+		}
+		data, _ := json.Marshal(bills)
+		_ = json.NewEncoder(w).Encode(apiResponse{Code: "0", Data: data})
+	}))
+	t.Cleanup(server.Close)
+
+	viper.Set("exchanges.okx.apiBaseURL", server.URL)
+	viper.Set("exchanges.okx.apiKey", "key")
+	viper.Set("exchanges.okx.secretKey", "secret")
+	viper.Set("exchanges.okx.passphrase", "pass")
+	viper.Set("tracklet.maxRetries", 0)
+	viper.Set("tracklet.maxHistory", 365)
+	t.Cleanup(func() {
+		for _, key := range []string{"exchanges.okx.apiBaseURL", "exchanges.okx.apiKey", "exchanges.okx.secretKey", "exchanges.okx.passphrase", "tracklet.maxRetries", "tracklet.maxHistory"} {
+			viper.Set(key, nil)
+		}
+	})
+
+	return &requests
+}
+
+func TestGetBillsPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        int
+		old          int
+		wantRequests int
+		wantBills    int
+	}{
+		{name: "single page", count: 10, wantRequests: 1, wantBills: 10},
+		{name: "last partial page", count: 2*pageSize + 1, wantRequests: 3, wantBills: 2*pageSize + 1},
+		// A full last page is followed by an empty one
+		{name: "exact pages", count: 2 * pageSize, wantRequests: 3, wantBills: 2 * pageSize},
+		{name: "older than history", count: 3 * pageSize, old: 2*pageSize + 10, wantRequests: 1, wantBills: pageSize - 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := billsServer(t, tt.count, tt.old)
+
+			bills, err := GetAssetBills()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", *requests, tt.wantRequests)
+			}
+			if len(*bills) != tt.wantBills {
+				t.Errorf("got %d bills, want %d", len(*bills), tt.wantBills)
+			}
+		})
+	}
+}
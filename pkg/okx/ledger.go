@@ -0,0 +1,157 @@
+// Handles conversion of OKX data to ledger transactions
+package okx
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+const source = "okx"
+
+// Account bill types changing a balance outside of spot trading
+var derivativeBillTypes = map[string]bool{
+	"2": true, // Trade (non spot instruments only)
+	"3": true, // Delivery
+	"5": true, // Liquidation
+	"7": true, // Interest deduction
+	"8": true, // Funding fee
+	"9": true, // ADL
+}
+
+// Parse an OKX decimal string, defaulting to zero
+func parseFloat(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Errorf("Could not convert string to float: %v", err)
+		return 0
+	}
+
+	return f
+}
+
+// Parse an OKX Unix milliseconds string
+func parseTime(value string) int64 {
+	t, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Errorf("Could not parse time: %v", err)
+		return 0
+	}
+
+	return t
+}
+
+// Convert a spot fill to a ledger transaction
+func fillToTransaction(fill Fill) ledger.Transaction {
+	assets := strings.SplitN(fill.InstID, "-", 2)
+	baseAsset, quoteAsset := assets[0], ""
+	if len(assets) == 2 {
+		quoteAsset = assets[1]
+	}
+
+	size := parseFloat(fill.FillSz)
+
+	txType := ledger.TypeBuy
+	if fill.Side == "sell" {
+		txType = ledger.TypeSell
+	}
+
+	transaction := ledger.Transaction{
+		ID:          fill.TradeID,
+		Source:      source,
+		Type:        txType,
+		Time:        parseTime(fill.Ts),
+		Asset:       baseAsset,
+		Amount:      size,
+		QuoteAsset:  quoteAsset,
+		QuoteAmount: size * parseFloat(fill.FillPx),
+	}
+
+	// OKX fees are negative, positive ones being maker rebates
+	if fee := parseFloat(fill.Fee); fee < 0 {
+		transaction.FeeAsset = fill.FeeCcy
+		transaction.FeeAmount = -fee
+	}
+
+	return transaction
+}
+
+// Convert a balance change to an income or a fee
+func balanceChangeToTransaction(bill Bill) (ledger.Transaction, bool) {
+	change := parseFloat(bill.BalChg)
+	if change == 0 {
+		return ledger.Transaction{}, false
+	}
+
+	txType := ledger.TypeIncome
+	if change < 0 {
+		txType = ledger.TypeFee
+	}
+
+	return ledger.Transaction{
+		ID:     bill.BillID,
+		Source: source,
+		Type:   txType,
+		Time:   parseTime(bill.Ts),
+		Asset:  bill.Ccy,
+		Amount: math.Abs(change),
+	}, true
+}
+
+// Convert all fetched OKX data to ledger transactions
+func (o *OKX) toLedger() []ledger.Transaction {
+	transactions := []ledger.Transaction{}
+
+	if o.Fills != nil {
+		for _, fill := range *o.Fills {
+			if fill.InstType == "SPOT" {
+				transactions = append(transactions, fillToTransaction(fill))
+			}
+		}
+	}
+
+	// Derivatives do not move the underlying asset, only their realized pnl and fees matter
+	if o.AccountBills != nil {
+		for _, bill := range *o.AccountBills {
+			if !derivativeBillTypes[bill.Type] || bill.InstType == "SPOT" {
+				continue
+			}
+
+			if transaction, ok := balanceChangeToTransaction(bill); ok {
+				transactions = append(transactions, transaction)
+			}
+		}
+	}
+
+	if o.AssetBills != nil {
+		for _, bill := range *o.AssetBills {
+			var txType ledger.Type
+			switch bill.Type {
+			case "1", "13": // Deposit, canceled withdrawal
+				txType = ledger.TypeDeposit
+			case "2": // Withdrawal
+				txType = ledger.TypeWithdraw
+			default:
+				continue
+			}
+
+			transactions = append(transactions, ledger.Transaction{
+				ID:     bill.BillID,
+				Source: source,
+				Type:   txType,
+				Time:   parseTime(bill.Ts),
+				Asset:  bill.Ccy,
+				Amount: math.Abs(parseFloat(bill.BalChg)),
+			})
+		}
+	}
+
+	return transactions
+}
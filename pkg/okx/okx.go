@@ -0,0 +1,106 @@
+// Handles OKX package logic to fetch all data
+package okx
+
+import (
+	"fmt"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	"github.com/eliasbokreta/tracklet/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type OKX struct {
+	AccountBills *[]Bill
+	AssetBills   *[]Bill
+	Fills        *[]Fill
+}
+
+// Create a new OKX object
+func New() *OKX {
+	return &OKX{}
+}
+
+// Create a new Wallet object computed from the OKX ledger
+func NewWallet() *ledger.Wallet {
+	return ledger.NewWallet(source)
+}
+
+// Write all fetched data to files
+func (o *OKX) saveDataToFile() error {
+	if err := utils.WriteToFile("okx_account_bills", o.AccountBills); err != nil {
+		log.Errorf("Could not write data to okx_account_bills: %v", err)
+	} else {
+		metrics.Synced(source, "okx_account_bills")
+	}
+
+	if err := utils.WriteToFile("okx_asset_bills", o.AssetBills); err != nil {
+		log.Errorf("Could not write data to okx_asset_bills: %v", err)
+	} else {
+		metrics.Synced(source, "okx_asset_bills")
+	}
+
+	if err := utils.WriteToFile("okx_fills", o.Fills); err != nil {
+		log.Errorf("Could not write data to okx_fills: %v", err)
+	} else {
+		metrics.Synced(source, "okx_fills")
+	}
+
+	if err := ledger.Save(source, o.toLedger()); err != nil {
+		return fmt.Errorf("could not write ledger: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve all account data from OKX
+func (o *OKX) ProcessOKXData(verbose bool) error {
+	log.Info("Starting process OKX data...")
+
+	// TRADING ACCOUNT BILLS
+	log.Info("Fetching account bills data...")
+	accountBills, err := GetAccountBills()
+	if err != nil {
+		return err
+	}
+
+	o.AccountBills = accountBills
+
+	if verbose {
+		if err := utils.OutputResult(o.AccountBills); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// FUNDING ACCOUNT BILLS
+	log.Info("Fetching asset bills data...")
+	assetBills, err := GetAssetBills()
+	if err != nil {
+		return err
+	}
+
+	o.AssetBills = assetBills
+
+	if verbose {
+		if err := utils.OutputResult(o.AssetBills); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	// FILLS HISTORY
+	log.Info("Fetching fills history data...")
+	fills, err := GetFills()
+	if err != nil {
+		return err
+	}
+
+	o.Fills = fills
+
+	if verbose {
+		if err := utils.OutputResult(o.Fills); err != nil {
+			return fmt.Errorf("could not output result: %w", err)
+		}
+	}
+
+	return o.saveDataToFile()
+}
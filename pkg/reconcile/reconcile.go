@@ -0,0 +1,207 @@
+// Handles reconciliation of computed holdings against live balances
+package reconcile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/eliasbokreta/tracklet/pkg/binance"
+	"github.com/eliasbokreta/tracklet/pkg/evm"
+	"github.com/eliasbokreta/tracklet/pkg/kucoin"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	log "github.com/sirupsen/logrus"
+)
+
+// Categories of data likely missing from the history
+const (
+	CategoryDust        = "dust"
+	CategoryEarn        = "earn"
+	CategoryConvert     = "convert"
+	CategoryFees        = "fees"
+	CategoryDeposits    = "deposits"
+	CategoryWithdrawals = "withdrawals"
+	CategoryTrades      = "trades"
+)
+
+var categoryHints = map[string]string{
+	CategoryDust:        "small balances converted to BNB",
+	CategoryEarn:        "interest, staking or savings rewards",
+	CategoryConvert:     "convert or OTC trades",
+	CategoryFees:        "trading or withdrawal fees",
+	CategoryDeposits:    "deposits older than the API history, import a statement",
+	CategoryWithdrawals: "withdrawals older than the API history, import a statement",
+	CategoryTrades:      "trades missing from the history",
+}
+
+type Asset struct {
+	Asset       string   `json:"asset"`
+	Computed    float64  `json:"computed"`
+	Actual      float64  `json:"actual"`
+	Difference  float64  `json:"difference"`
+	Matching    bool     `json:"matching"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+type Report struct {
+	Source        string  `json:"source"`
+	Tolerance     float64 `json:"tolerance"`
+	Discrepancies int     `json:"discrepancies"`
+	Assets        []Asset `json:"assets"`
+}
+
+type reconciler struct {
+	computed func() (map[string]float64, error)
+	live     func() (map[string]float64, error)
+}
+
+// Sources having live balances to reconcile against
+var reconcilers = map[string]reconciler{
+	"binance": {
+		computed: func() (map[string]float64, error) {
+			wallet := ledger.NewWallet("binance")
+			if err := wallet.CalculateHoldings(); err != nil {
+				return nil, err
+			}
+			return quantities(wallet.Holdings), nil
+		},
+		live: binance.GetBalances,
+	},
+	"kucoin": {
+		computed: func() (map[string]float64, error) {
+			wallet := ledger.NewWallet("kucoin")
+			if err := wallet.CalculateHoldings(); err != nil {
+				return nil, err
+			}
+			return quantities(wallet.Holdings), nil
+		},
+		live: kucoin.GetBalances,
+	},
+	"evm": {
+		computed: func() (map[string]float64, error) {
+			wallet := evm.NewWallet()
+			if err := wallet.CalculateHoldings(); err != nil {
+				return nil, err
+			}
+			return quantities(wallet.Holdings), nil
+		},
+		live: evm.GetBalances,
+	},
+}
+
+// Return the sorted list of sources supporting reconciliation
+func Sources() []string {
+	sources := []string{}
+	for source := range reconcilers {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	return sources
+}
+
+// Extract quantities from holdings
+func quantities(holdings map[string]ledger.Holdings) map[string]float64 {
+	q := make(map[string]float64)
+	for asset, h := range holdings {
+		q[asset] = h.Quantity
+	}
+
+	return q
+}
+
+// Guess which data is likely missing to explain a difference
+func suggest(source string, asset string, computed float64, actual float64) []string {
+	relative := math.Abs(actual-computed) / math.Max(math.Abs(actual), math.Abs(computed))
+
+	if actual > computed {
+		switch {
+		case source == "binance" && asset == "BNB":
+			return []string{CategoryDust, CategoryEarn, CategoryDeposits}
+		case computed <= 0:
+			return []string{CategoryDeposits, CategoryConvert, CategoryTrades, CategoryEarn}
+		case relative < 0.05:
+			return []string{CategoryEarn}
+		default:
+			return []string{CategoryDeposits, CategoryConvert, CategoryTrades}
+		}
+	}
+
+	switch {
+	case source == "binance" && asset == "BNB":
+		return []string{CategoryFees, CategoryWithdrawals}
+	case actual <= 0 && source == "binance":
+		return []string{CategoryDust, CategoryWithdrawals, CategoryConvert}
+	case actual <= 0:
+		return []string{CategoryWithdrawals, CategoryConvert, CategoryTrades}
+	case relative < 0.05:
+		return []string{CategoryFees}
+	default:
+		return []string{CategoryWithdrawals, CategoryConvert, CategoryTrades}
+	}
+}
+
+// Compare computed holdings of a source to its live balances
+// Differences above tolerance percent of the largest quantity are reported as discrepancies
+func Reconcile(source string, tolerance float64) (*Report, error) {
+	r, ok := reconcilers[source]
+	if !ok {
+		return nil, fmt.Errorf("reconciliation is not supported for '%s'", source)
+	}
+
+	log.Infof("Calculating %s holdings...", source)
+	computed, err := r.computed()
+	if err != nil {
+		return nil, fmt.Errorf("could not calculate holdings: %w", err)
+	}
+
+	log.Infof("Fetching %s live balances...", source)
+	live, err := r.live()
+	if err != nil {
+		return nil, fmt.Errorf("could not get live balances: %w", err)
+	}
+
+	assets := make(map[string]bool)
+	for asset := range computed {
+		assets[asset] = true
+	}
+	for asset := range live {
+		assets[asset] = true
+	}
+
+	report := &Report{
+		Source:    source,
+		Tolerance: tolerance,
+		Assets:    []Asset{},
+	}
+
+	for asset := range assets {
+		a := Asset{
+			Asset:      asset,
+			Computed:   computed[asset],
+			Actual:     live[asset],
+			Difference: live[asset] - computed[asset],
+		}
+
+		allowed := math.Max(tolerance/100*math.Max(math.Abs(a.Computed), math.Abs(a.Actual)), 1e-8)
+		a.Matching = math.Abs(a.Difference) <= allowed
+		if !a.Matching {
+			report.Discrepancies++
+			for _, category := range suggest(source, asset, a.Computed, a.Actual) {
+				a.Suggestions = append(a.Suggestions, fmt.Sprintf("%s: %s", category, categoryHints[category]))
+			}
+		}
+
+		report.Assets = append(report.Assets, a)
+	}
+
+	// Discrepancies first, then by asset
+	sort.Slice(report.Assets, func(i, j int) bool {
+		if report.Assets[i].Matching != report.Assets[j].Matching {
+			return !report.Assets[i].Matching
+		}
+		return report.Assets[i].Asset < report.Assets[j].Asset
+	})
+
+	return report, nil
+}
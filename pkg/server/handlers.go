@@ -0,0 +1,263 @@
+// Handles API endpoints
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/aggregators/coingecko"
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+)
+
+// Holdings of an asset in a source
+type holding struct {
+	Source string `json:"source"`
+	Asset  string `json:"asset"`
+	ledger.Holdings
+}
+
+type holdingsResponse struct {
+	Currency string    `json:"currency"`
+	Holdings []holding `json:"holdings"`
+}
+
+type statsResponse struct {
+	Currency string                  `json:"currency"`
+	Sources  map[string]ledger.Stats `json:"sources"`
+	Total    ledger.Stats            `json:"total"`
+}
+
+type transactionsResponse struct {
+	Total        int                  `json:"total"`
+	Transactions []ledger.Transaction `json:"transactions"`
+}
+
+// Parse a time given as RFC 3339 or a YYYY-MM-DD date into Unix milliseconds
+func parseTime(value string) (int64, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UnixMilli(), nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s', expected RFC 3339 or YYYY-MM-DD", value)
+	}
+
+	return t.UnixMilli(), nil
+}
+
+// Parse the `from` and `to` query parameters, `to` being excluded
+func timeRange(r *http.Request) (int64, int64, error) {
+	from, to := int64(0), time.Now().UnixMilli()+1
+
+	if value := r.URL.Query().Get("from"); value != "" {
+		t, err := parseTime(value)
+		if err != nil {
+			return 0, 0, err
+		}
+		from = t
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		t, err := parseTime(value)
+		if err != nil {
+			return 0, 0, err
+		}
+		to = t
+	}
+
+	return from, to, nil
+}
+
+// Parse a non negative integer query parameter, defaulting when not set
+func intParameter(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s '%s', expected a positive integer", name, value)
+	}
+
+	return n, nil
+}
+
+// Check if a filter is empty or equal to a value, ignoring case
+func matches(filter string, value string) bool {
+	return filter == "" || strings.EqualFold(filter, value)
+}
+
+// GET /holdings?source=&asset=&class=
+func (s *Server) handleHoldings(w http.ResponseWriter, r *http.Request) {
+	wallets, err := s.Wallets()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	query := r.URL.Query()
+	response := holdingsResponse{Currency: ledger.ReportingCurrency(), Holdings: []holding{}}
+	for source, wallet := range wallets {
+		if !matches(query.Get("source"), source) {
+			continue
+		}
+		for _, assets := range []map[string]ledger.Holdings{wallet.Holdings, wallet.Cash} {
+			for asset, d := range assets {
+				if d.Quantity == 0 || !matches(query.Get("asset"), asset) || !matches(query.Get("class"), string(d.Class)) {
+					continue
+				}
+				response.Holdings = append(response.Holdings, holding{Source: source, Asset: asset, Holdings: d})
+			}
+		}
+	}
+	sort.SliceStable(response.Holdings, func(i, j int) bool {
+		return response.Holdings[i].CurrentValue > response.Holdings[j].CurrentValue
+	})
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GET /stats?source=
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	wallets, err := s.Wallets()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	currency := ledger.ReportingCurrency()
+	response := statsResponse{
+		Currency: currency,
+		Sources:  make(map[string]ledger.Stats),
+		Total:    ledger.Stats{Currency: currency},
+	}
+	for source, wallet := range wallets {
+		if !matches(r.URL.Query().Get("source"), source) {
+			continue
+		}
+
+		stats := wallet.Stats
+		response.Sources[source] = stats
+		response.Total.TotalInvested += stats.TotalInvested
+		response.Total.TotalDeposited += stats.TotalDeposited
+		response.Total.TotalIncome += stats.TotalIncome
+		response.Total.TotalCash += stats.TotalCash
+		response.Total.TotalValue += stats.TotalValue
+		response.Total.GainValue += stats.GainValue
+		response.Total.TotalAssets += stats.TotalAssets
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GET /transactions?source=&asset=&type=&from=&to=&limit=&offset=, newest first
+func (s *Server) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	from, to, err := timeRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := intParameter(r, "limit", 100)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	offset, err := intParameter(r, "offset", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	transactions, err := ledger.LoadAll()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	query := r.URL.Query()
+	filtered := []ledger.Transaction{}
+	for i := len(transactions) - 1; i >= 0; i-- {
+		tx := transactions[i]
+		if tx.Time < from || tx.Time >= to || !matches(query.Get("source"), tx.Source) || !matches(query.Get("type"), string(tx.Type)) {
+			continue
+		}
+		if asset := query.Get("asset"); asset != "" && !matches(asset, tx.Asset) && !matches(asset, tx.QuoteAsset) && !matches(asset, tx.FeeAsset) {
+			continue
+		}
+		filtered = append(filtered, tx)
+	}
+
+	response := transactionsResponse{Total: len(filtered), Transactions: []ledger.Transaction{}}
+	if offset < len(filtered) {
+		end := len(filtered)
+		if offset+limit < end {
+			end = offset + limit
+		}
+		response.Transactions = filtered[offset:end]
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GET /snapshots?source=&from=&to=
+func (s *Server) handleSnapshots(w http.ResponseWriter, r *http.Request) {
+	from, to, err := timeRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	snapshots, err := ledger.LoadAllSnapshots()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	filtered := []ledger.Snapshot{}
+	for _, snapshot := range snapshots {
+		if snapshot.Time >= from && snapshot.Time < to && matches(r.URL.Query().Get("source"), snapshot.Source) {
+			filtered = append(filtered, snapshot)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, filtered)
+}
+
+// GET /tax/{year}?source=
+func (s *Server) handleTax(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/tax/"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("invalid year '%s'", strings.TrimPrefix(r.URL.Path, "/tax/")))
+		return
+	}
+
+	transactions, err := ledger.LoadAllWithCosts()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	coinList, err := coingecko.GetCoinList()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("could not get coin list: %w", err))
+		return
+	}
+
+	// Requests share the price cache file
+	s.mutex.Lock()
+	prices := ledger.NewPriceHistory(coinList)
+	report := ledger.CalculateTax(transactions, prices, year, r.URL.Query().Get("source"))
+	err = prices.Save()
+	s.mutex.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,127 @@
+// Handles the Prometheus metrics endpoint
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+// A metric family in the Prometheus text exposition format
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string
+	samples []string
+}
+
+// Add a sample with its labels, given as name and value pairs
+func (m *metricFamily) add(value float64, labels ...string) {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], escapeLabel(labels[i+1])))
+	}
+
+	sample := m.name
+	if len(pairs) > 0 {
+		sample += "{" + strings.Join(pairs, ",") + "}"
+	}
+	m.samples = append(m.samples, fmt.Sprintf("%s %v", sample, value))
+}
+
+// Write the family help, type and samples, sorted to keep the output stable
+func (m *metricFamily) write(b *strings.Builder) {
+	sort.Strings(m.samples)
+
+	fmt.Fprintf(b, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", m.name, m.kind)
+	for _, sample := range m.samples {
+		b.WriteString(sample + "\n")
+	}
+}
+
+// Escape backslashes, quotes and line feeds of a label value
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// GET /metrics, holdings and fetcher health in the Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	wallets, err := s.Wallets()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fetchers, err := metrics.Stats()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	currency := ledger.ReportingCurrency()
+	quantity := &metricFamily{name: "tracklet_asset_quantity", help: "Quantity of an asset held in a source.", kind: "gauge"}
+	value := &metricFamily{name: "tracklet_asset_value", help: fmt.Sprintf("Current value of an asset held in a source, in %s.", currency), kind: "gauge"}
+	cost := &metricFamily{name: "tracklet_asset_cost_basis", help: fmt.Sprintf("Cost basis of an asset held in a source, in %s.", currency), kind: "gauge"}
+	pnl := &metricFamily{name: "tracklet_asset_pnl", help: fmt.Sprintf("Unrealized gain or loss of an asset held in a source, in %s.", currency), kind: "gauge"}
+	sourceValue := &metricFamily{name: "tracklet_source_value", help: fmt.Sprintf("Total value of a source, in %s.", currency), kind: "gauge"}
+	portfolioValue := &metricFamily{name: "tracklet_portfolio_value", help: "Total value of all sources.", kind: "gauge"}
+
+	total := 0.0
+	for source, wallet := range wallets {
+		for asset, d := range wallet.Holdings {
+			if d.Quantity == 0 {
+				continue
+			}
+			labels := []string{"source", source, "asset", asset, "class", string(d.Class)}
+			quantity.add(d.Quantity, labels...)
+			value.add(d.CurrentValue, labels...)
+			cost.add(d.CostBasis, labels...)
+			pnl.add(d.CurrentValue-d.CostBasis, labels...)
+		}
+		// Cash has no cost basis
+		for asset, d := range wallet.Cash {
+			if d.Quantity == 0 {
+				continue
+			}
+			labels := []string{"source", source, "asset", asset, "class", string(d.Class)}
+			quantity.add(d.Quantity, labels...)
+			value.add(d.CurrentValue, labels...)
+		}
+
+		sourceValue.add(wallet.Stats.TotalValue, "source", source)
+		total += wallet.Stats.TotalValue
+	}
+	portfolioValue.add(total, "currency", currency)
+
+	lastSync := &metricFamily{name: "tracklet_fetcher_last_sync_timestamp_seconds", help: "Time of the last successful sync of a source dataset.", kind: "gauge"}
+	requests := &metricFamily{name: "tracklet_fetcher_requests_total", help: "Requests made to a source API.", kind: "counter"}
+	retries := &metricFamily{name: "tracklet_fetcher_retries_total", help: "Failed requests to a source API retried.", kind: "counter"}
+	rateLimitWaits := &metricFamily{name: "tracklet_fetcher_rate_limit_waits_total", help: "Waits after a source API rate limit response.", kind: "counter"}
+	rateLimitWaitSeconds := &metricFamily{name: "tracklet_fetcher_rate_limit_wait_seconds_total", help: "Time spent waiting after source API rate limit responses.", kind: "counter"}
+
+	for source, stats := range fetchers {
+		requests.add(float64(stats.Requests), "source", source)
+		retries.add(float64(stats.Retries), "source", source)
+		rateLimitWaits.add(float64(stats.RateLimitWaits), "source", source)
+		rateLimitWaitSeconds.add(stats.RateLimitWaitSeconds, "source", source)
+		for dataset, t := range stats.LastSync {
+			lastSync.add(float64(t)/1000, "source", source, "dataset", dataset)
+		}
+	}
+
+	var b strings.Builder
+	for _, family := range []*metricFamily{quantity, value, cost, pnl, sourceValue, portfolioValue, lastSync, requests, retries, rateLimitWaits, rateLimitWaitSeconds} {
+		family.write(&b)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		log.Errorf("Could not write response: %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+// Handles the OpenAPI document of the API
+package server
+
+import (
+	"net/http"
+)
+
+const openAPIDocument = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "tracklet",
+    "description": "Portfolio calculated from tracklet ledgers, amounts being in the reporting currency",
+    "version": "1.0.0"
+  },
+  "components": {
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer"}
+    },
+    "parameters": {
+      "source": {"name": "source", "in": "query", "description": "Only return data of a source, such as binance", "schema": {"type": "string"}},
+      "asset": {"name": "asset", "in": "query", "description": "Only return data of an asset, such as BTC", "schema": {"type": "string"}},
+      "from": {"name": "from", "in": "query", "description": "Start time, RFC 3339 or YYYY-MM-DD", "schema": {"type": "string"}},
+      "to": {"name": "to", "in": "query", "description": "End time excluded, RFC 3339 or YYYY-MM-DD", "schema": {"type": "string"}}
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": {"error": {"type": "string"}}
+      },
+      "Holding": {
+        "type": "object",
+        "properties": {
+          "source": {"type": "string"},
+          "asset": {"type": "string"},
+          "name": {"type": "string"},
+          "class": {"type": "string", "enum": ["fiat", "stablecoin", "crypto", "derivative"]},
+          "quantity": {"type": "number"},
+          "currentValue": {"type": "number"},
+          "costBasis": {"type": "number"}
+        }
+      },
+      "Stats": {
+        "type": "object",
+        "properties": {
+          "currency": {"type": "string"},
+          "totalInvested": {"type": "number"},
+          "totalDeposited": {"type": "number"},
+          "totalIncome": {"type": "number"},
+          "totalCash": {"type": "number"},
+          "totalValue": {"type": "number"},
+          "gainValue": {"type": "number"},
+          "totalAssets": {"type": "integer"}
+        }
+      },
+      "Transaction": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "source": {"type": "string"},
+          "type": {"type": "string", "enum": ["buy", "sell", "deposit", "withdraw", "income", "fee"]},
+          "time": {"type": "integer", "description": "Unix milliseconds"},
+          "asset": {"type": "string"},
+          "amount": {"type": "number"},
+          "quoteAsset": {"type": "string"},
+          "quoteAmount": {"type": "number"},
+          "feeAsset": {"type": "string"},
+          "feeAmount": {"type": "number"},
+          "txHash": {"type": "string"},
+          "origin": {"type": "string"}
+        }
+      },
+      "Snapshot": {
+        "type": "object",
+        "properties": {
+          "time": {"type": "integer", "description": "Unix milliseconds"},
+          "source": {"type": "string"},
+          "holdings": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/Holding"}},
+          "cash": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/Holding"}},
+          "prices": {"type": "object", "additionalProperties": {"type": "number"}},
+          "stats": {"$ref": "#/components/schemas/Stats"}
+        }
+      },
+      "Disposal": {
+        "type": "object",
+        "properties": {
+          "time": {"type": "integer", "description": "Unix milliseconds"},
+          "source": {"type": "string"},
+          "asset": {"type": "string"},
+          "quantity": {"type": "number"},
+          "proceeds": {"type": "number"},
+          "cost": {"type": "number"},
+          "gain": {"type": "number"}
+        }
+      },
+      "TaxReport": {
+        "type": "object",
+        "properties": {
+          "year": {"type": "integer"},
+          "currency": {"type": "string"},
+          "method": {"type": "string", "enum": ["average", "fifo", "lifo"]},
+          "disposals": {"type": "array", "items": {"$ref": "#/components/schemas/Disposal"}},
+          "totalProceeds": {"type": "number"},
+          "totalCost": {"type": "number"},
+          "totalGain": {"type": "number"},
+          "gainByAsset": {"type": "object", "additionalProperties": {"type": "number"}},
+          "totalIncome": {"type": "number"}
+        }
+      }
+    },
+    "responses": {
+      "Error": {"description": "Error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    }
+  },
+  "security": [{"bearer": []}],
+  "paths": {
+    "/holdings": {
+      "get": {
+        "summary": "Holdings of every source at current prices, highest value first",
+        "parameters": [
+          {"$ref": "#/components/parameters/source"},
+          {"$ref": "#/components/parameters/asset"},
+          {"name": "class", "in": "query", "description": "Only return holdings of an asset class", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"description": "Holdings", "content": {"application/json": {"schema": {"type": "object", "properties": {
+            "currency": {"type": "string"},
+            "holdings": {"type": "array", "items": {"$ref": "#/components/schemas/Holding"}}
+          }}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/stats": {
+      "get": {
+        "summary": "Stats of every source and their total",
+        "parameters": [{"$ref": "#/components/parameters/source"}],
+        "responses": {
+          "200": {"description": "Stats", "content": {"application/json": {"schema": {"type": "object", "properties": {
+            "currency": {"type": "string"},
+            "sources": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/Stats"}},
+            "total": {"$ref": "#/components/schemas/Stats"}
+          }}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/transactions": {
+      "get": {
+        "summary": "Ledger transactions of every source, newest first",
+        "parameters": [
+          {"$ref": "#/components/parameters/source"},
+          {"$ref": "#/components/parameters/asset"},
+          {"name": "type", "in": "query", "schema": {"type": "string", "enum": ["buy", "sell", "deposit", "withdraw", "income", "fee"]}},
+          {"$ref": "#/components/parameters/from"},
+          {"$ref": "#/components/parameters/to"},
+          {"name": "limit", "in": "query", "schema": {"type": "integer", "default": 100}},
+          {"name": "offset", "in": "query", "schema": {"type": "integer", "default": 0}}
+        ],
+        "responses": {
+          "200": {"description": "Transactions", "content": {"application/json": {"schema": {"type": "object", "properties": {
+            "total": {"type": "integer", "description": "Number of transactions matching filters"},
+            "transactions": {"type": "array", "items": {"$ref": "#/components/schemas/Transaction"}}
+          }}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/snapshots": {
+      "get": {
+        "summary": "Wallet snapshots of every source, oldest first",
+        "parameters": [
+          {"$ref": "#/components/parameters/source"},
+          {"$ref": "#/components/parameters/from"},
+          {"$ref": "#/components/parameters/to"}
+        ],
+        "responses": {
+          "200": {"description": "Snapshots", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Snapshot"}}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/tax/{year}": {
+      "get": {
+        "summary": "Gains realized by sales for cash and income received during a year",
+        "parameters": [
+          {"name": "year", "in": "path", "required": true, "schema": {"type": "integer"}},
+          {"$ref": "#/components/parameters/source"}
+        ],
+        "responses": {
+          "200": {"description": "Tax report", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/TaxReport"}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/metrics": {
+      "get": {
+        "summary": "Holdings, values, cost basis and fetcher health in the Prometheus text format",
+        "responses": {
+          "200": {"description": "Metrics", "content": {"text/plain": {"schema": {"type": "string"}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  }
+}
+`
+
+// GET /openapi.json, readable without token
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write([]byte(openAPIDocument)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,177 @@
+// Handles the HTTP API exposing the portfolio
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eliasbokreta/tracklet/pkg/ledger"
+	"github.com/eliasbokreta/tracklet/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Server struct {
+	address string
+	token   string
+	ttl     time.Duration
+	mux     *http.ServeMux
+
+	mutex sync.Mutex
+	// Wallets of every source, calculated at most once per ttl
+	wallets    map[string]*ledger.Wallet
+	calculated time.Time
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Create a new Server object listening on `server.address`, requests needing `server.token` as bearer token when set
+func New() *Server {
+	s := &Server{
+		address: viper.GetString("server.address"),
+		token:   viper.GetString("server.token"),
+		ttl:     time.Duration(viper.GetInt("server.cacheTTL")) * time.Second,
+		mux:     http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/openapi.json", s.handleOpenAPI)
+	s.Handle("/holdings", s.handleHoldings)
+	s.Handle("/stats", s.handleStats)
+	s.Handle("/transactions", s.handleTransactions)
+	s.Handle("/snapshots", s.handleSnapshots)
+	s.Handle("/tax/", s.handleTax)
+	s.Handle("/metrics", s.handleMetrics)
+
+	return s
+}
+
+// Override the listening address
+func (s *Server) SetAddress(address string) {
+	s.address = address
+}
+
+// Override the bearer token, none being required when empty
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// Register a GET endpoint, authenticated when a token is set
+func (s *Server) Handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+			return
+		}
+
+		handler(w, r)
+	})
+}
+
+// Check the request bearer token, any request being authorized without token set
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// Log requests along with their status and duration
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		log.Infof("%s %s %d %s", r.Method, r.URL.RequestURI(), recorder.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// Response writer remembering its status
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Serve the API until it fails
+func (s *Server) Run() error {
+	if s.token == "" && !strings.HasPrefix(s.address, "127.0.0.1:") && !strings.HasPrefix(s.address, "localhost:") {
+		log.Warnf("Serving on %s without token, anyone reaching it can read the portfolio", s.address)
+	}
+	log.Infof("Serving API on http://%s", s.address)
+
+	server := &http.Server{
+		Addr:              s.address,
+		Handler:           s.logRequests(s.mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		return fmt.Errorf("could not serve API: %w", err)
+	}
+
+	return nil
+}
+
+// Write a JSON response
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Errorf("Could not write response: %v", err)
+	}
+}
+
+// Write a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// Return the wallets of every source, calculated again once older than the cache ttl
+func (s *Server) Wallets() (map[string]*ledger.Wallet, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.wallets != nil && time.Since(s.calculated) < s.ttl {
+		return s.wallets, nil
+	}
+
+	sources, err := ledger.Sources()
+	if err != nil {
+		return nil, fmt.Errorf("could not list sources: %w", err)
+	}
+
+	wallets := make(map[string]*ledger.Wallet)
+	for _, source := range sources {
+		wallet := ledger.NewWallet(source)
+		if err := wallet.Calculate(); err != nil {
+			return nil, fmt.Errorf("could not calculate '%s' wallet: %w", source, err)
+		}
+		wallets[source] = wallet
+	}
+	s.wallets, s.calculated = wallets, time.Now()
+
+	if err := metrics.Flush(); err != nil {
+		log.Warnf("Could not save fetcher metrics: %v", err)
+	}
+
+	return wallets, nil
+}
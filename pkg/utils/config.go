@@ -12,12 +12,49 @@ func setDefaultValues() {
 	viper.SetDefault("tracklet.timeout", 30)
 	viper.SetDefault("tracklet.retryDelay", 10)
 	viper.SetDefault("tracklet.maxRetries", 12)
+	viper.SetDefault("tracklet.duplicateWindow", 60)
+	viper.SetDefault("tracklet.costBasisMethod", "average")
+	viper.SetDefault("tracklet.currency", "EUR")
 
 	viper.SetDefault("aggregators.coingecko.apiBaseURL", "https://api.coingecko.com")
 
 	viper.SetDefault("exchanges.binance.apiBaseURL", "https://api.binance.com")
 
 	viper.SetDefault("exchanges.kucoin.apiBaseURL", "https://api.kucoin.com")
+
+	viper.SetDefault("exchanges.coinbase.apiBaseURL", "https://api.coinbase.com")
+
+	viper.SetDefault("exchanges.kraken.apiBaseURL", "https://api.kraken.com")
+
+	viper.SetDefault("exchanges.bitstamp.apiBaseURL", "https://www.bitstamp.net")
+
+	viper.SetDefault("exchanges.bitpanda.apiBaseURL", "https://api.bitpanda.com")
+
+	viper.SetDefault("exchanges.okx.apiBaseURL", "https://www.okx.com")
+
+	viper.SetDefault("exchanges.bybit.apiBaseURL", "https://api.bybit.com")
+
+	viper.SetDefault("wallets.bitcoin.apiBaseURL", "https://blockstream.info/api")
+	viper.SetDefault("wallets.bitcoin.gapLimit", 20)
+
+	viper.SetDefault("transfers.window", 48)
+	viper.SetDefault("transfers.amountTolerance", 0.5)
+
+	viper.SetDefault("reconcile.tolerance", 1)
+
+	viper.SetDefault("assets.stablecoinsAsCash", false)
+
+	viper.SetDefault("dashboard.refreshInterval", 300)
+
+	viper.SetDefault("server.address", "127.0.0.1:8080")
+	viper.SetDefault("server.cacheTTL", 300)
+
+	viper.SetDefault("daemon.syncSchedule", "0 */6 * * *")
+	viper.SetDefault("daemon.priceSchedule", "0 * * * *")
+
+	viper.SetDefault("alerts.cooldown", 3600)
+	viper.SetDefault("alerts.sinks.stdout", true)
+	viper.SetDefault("alerts.sinks.smtp.port", 587)
 }
 
 // Load configuration file
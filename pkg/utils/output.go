@@ -1,18 +1,269 @@
 package utils
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats
+const (
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputTable = "table"
+	OutputCSV   = "csv"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
 )
 
-// Print json structures
+var colorCodes = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// Levels of a sparkline, from the lowest value to the highest
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Rows of a result, with lines printed below them in table output
+type Table struct {
+	Header []string
+	Rows   [][]string
+	Footer []string
+	// Columns holding numbers, right aligned and sorted from the highest
+	Numeric map[int]bool
+}
+
+// Results with a table representation, used by table and csv outputs
+type Tabular interface {
+	Table() *Table
+}
+
+// Check if colors can be printed, stdout being a terminal and NO_COLOR unset
+func useColors() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	info, err := os.Stdout.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Color a formatted number green when positive and red when negative
+func ColorSign(text string, value float64) string {
+	switch {
+	case !useColors() || value == 0:
+		return text
+	case value > 0:
+		return colorGreen + text + colorReset
+	default:
+		return colorRed + text + colorReset
+	}
+}
+
+// Remove colors from a cell
+func plain(cell string) string {
+	return colorCodes.ReplaceAllString(cell, "")
+}
+
+// Parse a numeric cell, percent signs and thousands separators ignored
+func cellNumber(cell string) (float64, bool) {
+	value, err := strconv.ParseFloat(strings.NewReplacer(",", "", "%", "", "+", "").Replace(plain(cell)), 64)
+
+	return value, err == nil
+}
+
+// Sort rows by a column name, numbers from the highest and text alphabetically
+func (t *Table) Sort(column string) error {
+	index := -1
+	for i, name := range t.Header {
+		if strings.EqualFold(name, column) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("unknown column '%s', expected one of %s", column, strings.Join(t.Header, ", "))
+	}
+
+	sort.SliceStable(t.Rows, func(i, j int) bool {
+		a, b := t.Rows[i][index], t.Rows[j][index]
+		if t.Numeric[index] {
+			x, okX := cellNumber(a)
+			y, okY := cellNumber(b)
+			if okX != okY {
+				return okX
+			}
+			return x > y
+		}
+		return strings.ToLower(plain(a)) < strings.ToLower(plain(b))
+	})
+
+	return nil
+}
+
+// Return the header, a separator and rows as aligned columns
+func (t *Table) Lines() []string {
+	widths := make([]int, len(t.Header))
+	for _, row := range append([][]string{t.Header}, t.Rows...) {
+		for i, cell := range row {
+			if width := utf8.RuneCountInString(plain(cell)); i < len(widths) && width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	format := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(plain(cell)))
+			if t.Numeric[i] {
+				cells[i] = padding + cell
+			} else {
+				cells[i] = cell + padding
+			}
+		}
+		return strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", width)
+	}
+
+	lines := []string{format(t.Header), format(separators)}
+	for _, row := range t.Rows {
+		lines = append(lines, format(row))
+	}
+
+	return lines
+}
+
+// Print rows as aligned columns, followed by footer lines
+func (t *Table) print() {
+	for _, line := range t.Lines() {
+		fmt.Println(line)
+	}
+
+	if len(t.Footer) > 0 {
+		fmt.Println()
+	}
+	for _, line := range t.Footer {
+		fmt.Println(line)
+	}
+}
+
+// Print rows as comma separated values, colors and footer left out
+func (t *Table) printCSV() error {
+	writer := csv.NewWriter(os.Stdout)
+	for _, row := range append([][]string{t.Header}, t.Rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = plain(cell)
+		}
+		if err := writer.Write(cells); err != nil {
+			return fmt.Errorf("could not write csv output: %w", err)
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// Draw values as a line of block characters, scaled between the lowest and the highest
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := values[0], values[0]
+	for _, value := range values {
+		if value < low {
+			low = value
+		}
+		if value > high {
+			high = value
+		}
+	}
+
+	line := make([]rune, len(values))
+	for i, value := range values {
+		level := 0
+		if high > low {
+			level = int((value - low) / (high - low) * float64(len(sparks)-1))
+		}
+		line[i] = sparks[level]
+	}
+
+	return string(line)
+}
+
+// Check an output format is known
+func ValidateOutput(format string) error {
+	switch strings.ToLower(format) {
+	case OutputJSON, OutputYAML, OutputTable, OutputCSV:
+		return nil
+	default:
+		return fmt.Errorf("unknown output '%s', expected %s, %s, %s or %s", format, OutputTable, OutputJSON, OutputYAML, OutputCSV)
+	}
+}
+
+// Print results in the format set by `--output`, or `tracklet.output`
+// Results without a table representation are printed as json in table and csv formats
 func OutputResult(data interface{}) error {
+	format := strings.ToLower(viper.GetString("tracklet.output"))
+
+	if format == OutputTable || format == OutputCSV {
+		tabular, ok := data.(Tabular)
+		if !ok {
+			log.Warnf("No %s output for this result, printing json", format)
+			format = OutputJSON
+		} else {
+			table := tabular.Table()
+			if column := viper.GetString("tracklet.sort"); column != "" {
+				if err := table.Sort(column); err != nil {
+					return err
+				}
+			}
+			if format == OutputCSV {
+				return table.printCSV()
+			}
+			table.print()
+			return nil
+		}
+	}
+
+	if err := ValidateOutput(format); err != nil {
+		return err
+	}
+
 	output, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("could not marshal output: %w", err)
 	}
 
-	fmt.Println(string(output))
+	if format == OutputYAML {
+		// Going through json keeps field names
+		var document interface{}
+		if err := yaml.Unmarshal(output, &document); err != nil {
+			return fmt.Errorf("could not convert output to yaml: %w", err)
+		}
+		if output, err = yaml.Marshal(document); err != nil {
+			return fmt.Errorf("could not marshal yaml output: %w", err)
+		}
+	}
+
+	fmt.Println(strings.TrimRight(string(output), "\n"))
 
 	return nil
 }
@@ -22,7 +22,7 @@ func GetDateRanges(maxHistory int, timeRange int) []DateRange {
 
 	for d := now; d.Unix() >= now.AddDate(0, 0, -maxHistory).Unix(); {
 		dateRanges = append(dateRanges, DateRange{
-			StartDate: d.AddDate(0, 0, -15).UnixMilli(),
+			StartDate: d.AddDate(0, 0, -timeRange).UnixMilli(),
 			EndDate:   d.UnixMilli(),
 		})
 		d = d.AddDate(0, 0, -timeRange)
@@ -41,6 +41,18 @@ func GetDataPath() (string, error) {
 	return fmt.Sprintf("%s/.tracklet/data", homeDir), nil
 }
 
+// Check if a data file exists
+func FileExists(filename string) bool {
+	dataPath, err := GetDataPath()
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(fmt.Sprintf("%s/%s", dataPath, filename))
+
+	return err == nil
+}
+
 // Load json data from file
 func LoadFromFile(filename string) []byte {
 	dataPath, err := GetDataPath()